@@ -6,13 +6,16 @@ VERSION=$(git describe --always --dirty --tags)
 GOARCH=amd64
 DIR="$( cd "$( dirname "${BASH_SOURCE[0]}" )" && pwd )"
 
+export GOPATH="$(pwd):$(pwd)/vendor"
+export GO111MODULE=off
+
 build() {
   GOOS=$1
   RELEASE="gateway-$VERSION-$GOOS-$GOARCH"
   PKG_DIR="$DIST/$RELEASE"
 
   # do the actual build
-  GOOS=$GOOS GOARCH=$GOARCH gb build  -ldflags "-X main.version=$VERSION"
+  GOOS=$GOOS GOARCH=$GOARCH go build -ldflags "-X main.version=$VERSION" -o bin/$(srcBin $GOOS) github.com/stellar/gateway/cmd/gateway
 
   # make package directory
   rm -rf $PKG_DIR
@@ -5,15 +5,19 @@ import (
 	"flag"
 	"fmt"
 	log "github.com/Sirupsen/logrus"
+	"net/http"
 	"time"
 
 	"github.com/stellar/gateway/config"
 	"github.com/stellar/gateway/db"
 	"github.com/stellar/gateway/handlers"
+	"github.com/stellar/gateway/hooks"
 	"github.com/stellar/gateway/horizon"
 	"github.com/stellar/gateway/listener"
+	"github.com/stellar/gateway/signer"
 	"github.com/stellar/gateway/submitter"
 	"github.com/zenazn/goji"
+	"github.com/zenazn/goji/web"
 	"github.com/zenazn/goji/web/middleware"
 )
 
@@ -23,6 +27,7 @@ type App struct {
 	horizon              horizon.HorizonInterface
 	transactionSubmitter *submitter.TransactionSubmitter
 	repository           db.RepositoryInterface
+	signers              *signer.Registry
 }
 
 // NewApp constructs an new App instance from the provided config.
@@ -36,34 +41,67 @@ func NewApp(config config.Config) (app *App, err error) {
 		return
 	}
 
-	h := horizon.New(*config.Horizon)
+	h := newHorizonPool(config)
+	h.StartHealthChecks()
 
 	if config.NetworkPassphrase == "" {
 		config.NetworkPassphrase = "Test SDF Network ; September 2015"
 	}
 
+	log.Print("Loading signers")
+	signers, err := newSignerRegistry(config)
+	if err != nil {
+		return
+	}
+
 	log.Print("Creating and initializing TransactionSubmitter")
-	ts := submitter.NewTransactionSubmitter(&h, &entityManager, config.NetworkPassphrase)
+	ts := submitter.NewTransactionSubmitter(h, &entityManager, config.NetworkPassphrase)
+	if err != nil {
+		return
+	}
+
+	log.Print("Recovering transactions left in sending state")
+	tr := submitter.NewTransactionRecoverer(h, &entityManager, &repository, config.NetworkPassphrase, time.Now)
+	// Nothing is being submitted yet so all transactions can be recovered
+	err = tr.RecoverAll(0)
 	if err != nil {
 		return
 	}
+	tr.Start()
 
 	log.Print("Initializing Authorizing account")
 
-	if config.Accounts.AuthorizingSeed == nil {
-		log.Warning("No accounts.authorizing_seed param. Skipping...")
+	if config.Accounts.GetAuthorizingAccountId() == "" {
+		log.Warning("No accounts.authorizing_seed or accounts.authorizing_account_id param. Skipping...")
 	} else {
-		err = ts.InitAccount(*config.Accounts.AuthorizingSeed)
+		err = initAccount(&ts, signers, config.Accounts.GetAuthorizingAccountId(), config.Accounts.AuthorizingSigners)
 		if err != nil {
 			return
 		}
 	}
 
-	if config.Accounts.IssuingSeed == nil {
-		log.Warning("No accounts.issuing_seed param. Skipping...")
+	if config.Accounts.GetIssuingAccountId() == "" {
+		log.Warning("No accounts.issuing_seed or accounts.issuing_account_id param. Skipping...")
 	} else {
 		log.Print("Initializing Issuing account")
-		err = ts.InitAccount(*config.Accounts.IssuingSeed)
+		err = initAccount(&ts, signers, config.Accounts.GetIssuingAccountId(), config.Accounts.IssuingSigners)
+		if err != nil {
+			return
+		}
+	}
+
+	if len(config.Accounts.ChannelSeeds) > 0 {
+		log.Printf("Initializing %d channel accounts", len(config.Accounts.ChannelSeeds))
+		var channels []signer.Signer
+		for _, seed := range config.Accounts.ChannelSeeds {
+			var channel signer.Signer
+			channel, err = signer.NewSeedSigner(seed)
+			if err != nil {
+				return
+			}
+			channels = append(channels, channel)
+		}
+		err = ts.InitChannels(channels)
 		if err != nil {
 			return
 		}
@@ -73,13 +111,22 @@ func NewApp(config config.Config) (app *App, err error) {
 
 	log.Print("Creating and starting PaymentListener")
 
-	if config.Accounts.ReceivingAccountId == nil {
-		log.Warning("No accounts.receiving_account_id param. Skipping...")
-	} else if config.Hooks.Receive == nil {
-		log.Warning("No hooks.receive param. Skipping...")
+	receivingAccounts := config.ReceivingAccounts()
+	if len(receivingAccounts) == 0 {
+		log.Warning("No accounts.receiving_account_id param or [[receiving]] accounts. Skipping...")
 	} else {
+		if config.Refunds != nil {
+			log.Print("Initializing receiving accounts for refunds")
+			for _, account := range receivingAccounts {
+				err = initAccount(&ts, signers, account.AccountId, nil)
+				if err != nil {
+					return
+				}
+			}
+		}
+
 		var paymentListener listener.PaymentListener
-		paymentListener, err = listener.NewPaymentListener(&config, &entityManager, &h, &repository, time.Now)
+		paymentListener, err = listener.NewPaymentListener(&config, &entityManager, h, &repository, &ts, time.Now)
 		if err != nil {
 			return
 		}
@@ -91,6 +138,41 @@ func NewApp(config config.Config) (app *App, err error) {
 		log.Print("PaymentListener created")
 	}
 
+	if config.Trustlines != nil {
+		log.Print("Creating and starting TrustlineWatcher")
+		var trustlineWatcher *listener.TrustlineWatcher
+		trustlineWatcher, err = listener.NewTrustlineWatcher(&config, &entityManager, h, &repository, &ts, time.Now)
+		if err != nil {
+			return
+		}
+		err = trustlineWatcher.Watch()
+		if err != nil {
+			return
+		}
+	}
+
+	if config.Hooks != nil || len(config.Receiving) > 0 {
+		log.Print("Starting hook deliverer")
+		var maxAttempts int
+		var secrets []string
+		if config.Hooks != nil {
+			maxAttempts = config.Hooks.MaxAttempts
+			secrets = config.Hooks.Secrets
+		}
+		deliverer := hooks.NewDeliverer(&entityManager, &repository, maxAttempts, time.Now)
+		deliverer.Secrets = secrets
+		deliverer.ErrorHooks = map[string]string{}
+		for _, account := range receivingAccounts {
+			if account.ErrorHook != nil {
+				deliverer.ErrorHooks[account.AccountId] = *account.ErrorHook
+			}
+		}
+		if len(deliverer.Secrets) == 0 {
+			log.Warning("No hooks.secrets param. Hook requests will not be signed.")
+		}
+		deliverer.Start()
+	}
+
 	if len(config.ApiKey) > 0 && len(config.ApiKey) < 15 {
 		err = errors.New("api-key have to be at least 15 chars long.")
 		return
@@ -99,42 +181,148 @@ func NewApp(config config.Config) (app *App, err error) {
 	app = &App{
 		config:               config,
 		entityManager:        &entityManager,
-		horizon:              &h,
+		horizon:              h,
 		repository:           &repository,
 		transactionSubmitter: &ts,
+		signers:              signers,
 	}
 	return
 }
 
-func (a *App) Serve() {
+// newHorizonPool creates horizon.Pool of servers configured in config.toml
+// with configured timeouts and retries.
+func newHorizonPool(config config.Config) (pool *horizon.Pool) {
+	pool = horizon.NewPool(config.HorizonUrls())
+	for _, server := range pool.Servers {
+		if config.HorizonTimeout != nil {
+			server.Timeout = time.Duration(*config.HorizonTimeout) * time.Second
+		}
+		if config.HorizonSubmitTimeout != nil {
+			server.SubmitTimeout = time.Duration(*config.HorizonSubmitTimeout) * time.Second
+		}
+	}
+	if config.HorizonMaxRetries != nil {
+		pool.MaxRetries = *config.HorizonMaxRetries
+	}
+	return
+}
+
+// newSignerRegistry creates signer.Registry with signers of all accounts
+// configured in config.toml and in the keystore file.
+func newSignerRegistry(config config.Config) (signers *signer.Registry, err error) {
+	var remoteUrl, remoteToken string
+	if config.Signers != nil && config.Signers.RemoteUrl != nil {
+		remoteUrl = *config.Signers.RemoteUrl
+	}
+	if config.Signers != nil && config.Signers.RemoteToken != nil {
+		remoteToken = *config.Signers.RemoteToken
+	}
+	signers = signer.NewRegistry(remoteUrl, remoteToken)
+
+	for _, seed := range []*string{config.Accounts.AuthorizingSeed, config.Accounts.IssuingSeed} {
+		if seed == nil {
+			continue
+		}
+		var seedSigner *signer.SeedSigner
+		seedSigner, err = signer.NewSeedSigner(*seed)
+		if err != nil {
+			return
+		}
+		signers.Add(seedSigner)
+	}
+
+	if config.Signers != nil && config.Signers.Keystore != nil {
+		var keystoreSigners []signer.Signer
+		keystoreSigners, err = signer.LoadKeystore(*config.Signers.Keystore, config.Signers.KeystorePassphrase)
+		if err != nil {
+			return
+		}
+		for _, keystoreSigner := range keystoreSigners {
+			signers.Add(keystoreSigner)
+		}
+		log.Printf("Loaded %d keys from keystore", len(keystoreSigners))
+	}
+	return
+}
+
+// initAccount initializes account in TransactionSubmitter with signer of the
+// account's master key (if available) and additional signers from config.
+func initAccount(ts *submitter.TransactionSubmitter, signers *signer.Registry, accountId string, additionalSigners []string) (err error) {
+	var accountSigners []signer.Signer
+
+	masterSigner, err := signers.Get(accountId)
+	if err == nil {
+		accountSigners = append(accountSigners, masterSigner)
+	} else if len(additionalSigners) == 0 {
+		return
+	}
+
+	for _, ref := range additionalSigners {
+		var accountSigner signer.Signer
+		accountSigner, err = signers.Resolve(ref)
+		if err != nil {
+			return
+		}
+		accountSigners = append(accountSigners, accountSigner)
+	}
+
+	return ts.InitAccount(accountId, accountSigners...)
+}
+
+// Handler returns HTTP handler of the gateway server API. It can be used to
+// run the API in tests without starting the server.
+func (a *App) Handler() *web.Mux {
 	requestHandlers := &handlers.RequestHandler{
 		Config:               &a.config,
 		Horizon:              a.horizon,
 		TransactionSubmitter: a.transactionSubmitter,
+		Repository:           a.repository,
+		EntityManager:        a.entityManager,
+		Signers:              a.signers,
 	}
 
-	portString := fmt.Sprintf(":%d", *a.config.Port)
-	flag.Set("bind", portString)
-
-	goji.Abandon(middleware.Logger)
-	goji.Use(handlers.StripTrailingSlashMiddleware())
-	goji.Use(handlers.HeadersMiddleware())
+	mux := web.New()
+	mux.Use(middleware.RequestID)
+	mux.Use(middleware.Recoverer)
+	mux.Use(middleware.AutomaticOptions)
+	mux.Use(handlers.StripTrailingSlashMiddleware())
+	mux.Use(handlers.HeadersMiddleware())
 	if a.config.ApiKey != "" {
-		goji.Use(handlers.ApiKeyMiddleware(a.config.ApiKey))
+		mux.Use(handlers.ApiKeyMiddleware(a.config.ApiKey))
 	}
 
-	if a.config.Accounts.AuthorizingSeed != nil {
-		goji.Post("/authorize", requestHandlers.Authorize)
+	idempotency := handlers.IdempotencyMiddleware(&a.config, a.repository, a.entityManager, time.Now)
+
+	if a.config.Accounts.GetAuthorizingAccountId() != "" {
+		mux.Post("/authorize", idempotency(http.HandlerFunc(requestHandlers.Authorize)))
+		mux.Post("/build/authorize", requestHandlers.BuildAuthorize)
 	} else {
-		log.Warning("accounts.authorizing_seed not provided. /authorize endpoint will not be available.")
+		log.Warning("accounts.authorizing_seed or accounts.authorizing_account_id not provided. /authorize endpoint will not be available.")
 	}
 
-	if a.config.Accounts.IssuingSeed != nil {
-		goji.Post("/send", requestHandlers.Send)
+	if a.config.Accounts.GetIssuingAccountId() != "" {
+		mux.Post("/send", idempotency(http.HandlerFunc(requestHandlers.Send)))
+		mux.Post("/build/send", requestHandlers.BuildSend)
 	} else {
-		log.Warning("accounts.issuing_seed not provided. /send endpoint will not be available.")
+		log.Warning("accounts.issuing_seed or accounts.issuing_account_id not provided. /send endpoint will not be available.")
 	}
 
-	goji.Post("/payment", requestHandlers.Payment)
+	mux.Post("/payment", idempotency(http.HandlerFunc(requestHandlers.Payment)))
+	mux.Post("/build/payment", requestHandlers.BuildPayment)
+	mux.Post("/submit", idempotency(http.HandlerFunc(requestHandlers.Submit)))
+	mux.Get("/transactions", requestHandlers.Transactions)
+	mux.Get("/transactions/:id", requestHandlers.Transaction)
+	mux.Get("/payments/received", requestHandlers.ReceivedPayments)
+	mux.Get("/admin/hook_deliveries", requestHandlers.HookDeliveries)
+	mux.Post("/admin/hook_deliveries/:id/redeliver", requestHandlers.RedeliverHook)
+	mux.Get("/admin/trustline_authorizations", requestHandlers.TrustlineAuthorizations)
+	return mux
+}
+
+func (a *App) Serve() {
+	portString := fmt.Sprintf(":%d", *a.config.Port)
+	flag.Set("bind", portString)
+
+	goji.DefaultMux = a.Handler()
 	goji.Serve()
 }
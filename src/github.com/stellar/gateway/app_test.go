@@ -0,0 +1,316 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stellar/gateway/config"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/migrations"
+	"github.com/stellar/gateway/handlers"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/horizon/horizontest"
+	"github.com/stellar/gateway/listener"
+	"github.com/stellar/go-stellar-base/build"
+	"github.com/stellar/go-stellar-base/keypair"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApp(t *testing.T) {
+	networkPassphrase := "Test SDF Network ; September 2015"
+	issuingSeed := "SC34WILLHVADXMP6ACPMIRA6TRAWJMVCLPFNW7S6MUMXJVLAZUC4EWHP"
+	issuingAccountId := "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR"
+	receivingAccountId := "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB"
+
+	sender, err := keypair.Random()
+	assert.Nil(t, err)
+	destination, err := keypair.Random()
+	assert.Nil(t, err)
+	trustor, err := keypair.Random()
+	assert.Nil(t, err)
+	otherTrustor, err := keypair.Random()
+	assert.Nil(t, err)
+
+	server := horizontest.NewServer(networkPassphrase)
+	defer server.Close()
+	server.AddAccount(issuingAccountId, "1000")
+	server.AddAccount(receivingAccountId, "100")
+	server.AddTrustline(receivingAccountId, "USD", issuingAccountId, "0", true)
+	server.AddAccount(sender.Address(), "100")
+	server.AddTrustline(sender.Address(), "USD", issuingAccountId, "100", true)
+	server.AddAccount(destination.Address(), "100")
+	server.AddTrustline(destination.Address(), "USD", issuingAccountId, "0", true)
+	server.AddAccount(trustor.Address(), "100")
+	server.AddAccount(otherTrustor.Address(), "100")
+	server.SetAuthFlags(issuingAccountId, true, false)
+
+	receiveHooks := make(chan url.Values, 10)
+	hookServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		receiveHooks <- r.PostForm
+	}))
+	defer hookServer.Close()
+
+	dbUrl := "file:" + filepath.Join(t.TempDir(), "gateway.db") + "?_busy_timeout=5000&_txlock=immediate"
+	migrationManager, err := migrations.NewMigrationManager("sqlite3", dbUrl)
+	assert.Nil(t, err)
+	migrationManager.MigrateUp()
+
+	// Trustlines stream starts from the first operation instead of "now" so
+	// operations submitted before the stream is connected are not missed
+	entityManager, err := db.NewEntityManager("sqlite3", dbUrl)
+	assert.Nil(t, err)
+	err = entityManager.Persist(&db.StreamCursor{
+		Name:      listener.TrustlinesStreamName(issuingAccountId),
+		Cursor:    "0",
+		UpdatedAt: time.Now(),
+	})
+	assert.Nil(t, err)
+
+	c := config.Config{
+		Horizon:           &server.URL,
+		NetworkPassphrase: networkPassphrase,
+		Assets:            []string{"USD"},
+		Accounts: &config.Accounts{
+			IssuingSeed:        &issuingSeed,
+			AuthorizingSeed:    &issuingSeed,
+			ReceivingAccountId: &receivingAccountId,
+		},
+		Hooks: &config.Hooks{
+			Receive: &hookServer.URL,
+		},
+		Trustlines: &config.Trustlines{
+			Policy:    "allowlist",
+			Allowlist: []string{trustor.Address()},
+		},
+	}
+	c.Database.Type = "sqlite3"
+	c.Database.Url = dbUrl
+
+	app, err := NewApp(c)
+	if !assert.Nil(t, err) {
+		return
+	}
+	handler := app.Handler()
+
+	request := func(method, path string, form url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	// submit signs and submits transaction with the next sequence number of
+	// the source account
+	submit := func(seed string, muts ...build.TransactionMutator) horizon.SubmitTransactionResponse {
+		source := keypair.MustParse(seed).Address()
+		muts = append(muts,
+			build.SourceAccount{seed},
+			build.Sequence{uint64(server.Sequence(source) + 1)},
+			build.Network{networkPassphrase},
+		)
+		txe := build.Transaction(muts...).Sign(seed)
+		txeB64, err := txe.Base64()
+		assert.Nil(t, err)
+
+		client := horizon.New(server.URL)
+		response, err := client.SubmitTransaction(context.Background(), txeB64)
+		assert.Nil(t, err)
+		return response
+	}
+
+	// trustlineAuthorizations waits until the trustline watcher processes
+	// change_trust of a given account
+	trustlineAuthorizations := func(accountId string) (page handlers.TrustlineAuthorizationsPageResponse) {
+		deadline := time.Now().Add(10 * time.Second)
+		for time.Now().Before(deadline) {
+			response := request("GET", "/admin/trustline_authorizations?account_id="+accountId, nil)
+			assert.Equal(t, http.StatusOK, response.Code)
+			err := json.Unmarshal(response.Body.Bytes(), &page)
+			assert.Nil(t, err)
+			if len(page.Authorizations) > 0 {
+				return
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+		return
+	}
+
+	Convey("Given gateway app running against horizontest", t, func() {
+		Convey("When /send is called", func() {
+			response := request("POST", "/send", url.Values{
+				"destination": {destination.Address()},
+				"asset_code":  {"USD"},
+				"amount":      {"10"},
+			})
+
+			Convey("it should send the payment", func() {
+				assert.Equal(t, http.StatusOK, response.Code)
+				assert.Equal(t, "10.0000000", server.Balance(destination.Address(), "USD", issuingAccountId))
+			})
+		})
+
+		Convey("When /send fails", func() {
+			response := request("POST", "/send", url.Values{
+				"destination": {sender.Address()},
+				"asset_code":  {"USD"},
+				"amount":      {"-1"},
+			})
+
+			Convey("it should return error", func() {
+				assert.Equal(t, http.StatusBadRequest, response.Code)
+			})
+		})
+
+		Convey("When payment is received", func() {
+			submitResponse := submit(sender.Seed(),
+				build.Payment(
+					build.Destination{receivingAccountId},
+					build.CreditAmount{"USD", issuingAccountId, "20"},
+				),
+				build.MemoText{"order-1"},
+			)
+			assert.Nil(t, submitResponse.Errors)
+
+			Convey("it should deliver receive hook and save the payment", func() {
+				select {
+				case hook := <-receiveHooks:
+					assert.Equal(t, "payment", hook.Get("type"))
+					assert.Equal(t, sender.Address(), hook.Get("from"))
+					assert.Equal(t, "20.0000000", hook.Get("amount"))
+					assert.Equal(t, "USD", hook.Get("asset_code"))
+					assert.Equal(t, "order-1", hook.Get("memo"))
+				case <-time.After(10 * time.Second):
+					t.Fatal("Receive hook was not delivered")
+				}
+
+				response := request("GET", "/payments/received?memo=order-1", nil)
+				assert.Equal(t, http.StatusOK, response.Code)
+
+				var page handlers.ReceivedPaymentsPageResponse
+				err := json.Unmarshal(response.Body.Bytes(), &page)
+				assert.Nil(t, err)
+				if assert.Equal(t, 1, len(page.Payments)) {
+					assert.Equal(t, "Success", page.Payments[0].Status)
+					assert.Equal(t, receivingAccountId, page.Payments[0].AccountId)
+					assert.Equal(t, submitResponse.Hash, page.Payments[0].TransactionHash)
+				}
+			})
+		})
+
+		Convey("When trustline is created by allowed account", func() {
+			response := submit(trustor.Seed(), horizontest.ChangeTrust{"USD", issuingAccountId, 1000 * 10000000})
+			assert.Nil(t, response.Errors)
+
+			Convey("it should authorize the trustline", func() {
+				page := trustlineAuthorizations(trustor.Address())
+				if assert.Equal(t, 1, len(page.Authorizations)) {
+					assert.Equal(t, "authorized", page.Authorizations[0].Status)
+					assert.Equal(t, "USD", page.Authorizations[0].AssetCode)
+					assert.NotNil(t, page.Authorizations[0].TransactionHash)
+				}
+				assert.True(t, server.IsAuthorized(trustor.Address(), "USD", issuingAccountId))
+			})
+		})
+
+		Convey("When trustline is created by other account", func() {
+			response := submit(otherTrustor.Seed(), horizontest.ChangeTrust{"USD", issuingAccountId, 1000 * 10000000})
+			assert.Nil(t, response.Errors)
+
+			Convey("it should reject the trustline", func() {
+				page := trustlineAuthorizations(otherTrustor.Address())
+				if assert.Equal(t, 1, len(page.Authorizations)) {
+					assert.Equal(t, "rejected", page.Authorizations[0].Status)
+					assert.Equal(t, "account_not_allowed", *page.Authorizations[0].Reason)
+				}
+				assert.False(t, server.IsAuthorized(otherTrustor.Address(), "USD", issuingAccountId))
+			})
+		})
+	})
+}
+
+func TestResetCursor(t *testing.T) {
+	receivingAccountId := "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB"
+
+	dbUrl := "file:" + filepath.Join(t.TempDir(), "gateway.db") + "?_busy_timeout=5000&_txlock=immediate"
+	migrationManager, err := migrations.NewMigrationManager("sqlite3", dbUrl)
+	assert.Nil(t, err)
+	migrationManager.MigrateUp()
+
+	repository, err := db.NewRepository("sqlite3", dbUrl)
+	assert.Nil(t, err)
+
+	c := config.Config{
+		Accounts: &config.Accounts{ReceivingAccountId: &receivingAccountId},
+	}
+	c.Database.Type = "sqlite3"
+	c.Database.Url = dbUrl
+
+	Convey("ResetCursor", t, func() {
+		Convey("it should create and update stream cursor of receiving account", func() {
+			err := ResetCursor(c, receivingAccountId, "100")
+			assert.Nil(t, err)
+			streamCursor, err := repository.GetStreamCursor(listener.StreamName(receivingAccountId))
+			assert.Nil(t, err)
+			assert.Equal(t, "100", streamCursor.Cursor)
+
+			err = ResetCursor(c, receivingAccountId, "now")
+			assert.Nil(t, err)
+			updatedCursor, err := repository.GetStreamCursor(listener.StreamName(receivingAccountId))
+			assert.Nil(t, err)
+			assert.Equal(t, "now", updatedCursor.Cursor)
+			assert.Equal(t, *streamCursor.Id, *updatedCursor.Id)
+		})
+
+		Convey("it should return error for other accounts", func() {
+			err := ResetCursor(c, "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR", "100")
+			assert.Equal(t, "Not a receiving account: GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR", err.Error())
+		})
+	})
+}
+
+func TestNewHorizonPool(t *testing.T) {
+	horizonUrl := "https://horizon.example.com"
+
+	Convey("Given horizon config", t, func() {
+		c := config.Config{
+			Horizon:          &horizonUrl,
+			HorizonFallbacks: []string{"https://horizon-2.example.com"},
+		}
+
+		Convey("When timeouts and retries are not set", func() {
+			Convey("it should use horizon defaults", func() {
+				pool := newHorizonPool(c)
+				assert.Equal(t, 2, len(pool.Servers))
+				assert.Equal(t, 10*time.Second, pool.Servers[0].Timeout)
+				assert.Equal(t, 60*time.Second, pool.Servers[0].SubmitTimeout)
+				assert.Equal(t, 3, pool.MaxRetries)
+			})
+		})
+
+		Convey("When timeouts and retries are set", func() {
+			timeout, submitTimeout, maxRetries := 5, 30, 0
+			c.HorizonTimeout = &timeout
+			c.HorizonSubmitTimeout = &submitTimeout
+			c.HorizonMaxRetries = &maxRetries
+
+			Convey("it should configure all servers", func() {
+				pool := newHorizonPool(c)
+				for _, server := range pool.Servers {
+					assert.Equal(t, 5*time.Second, server.Timeout)
+					assert.Equal(t, 30*time.Second, server.SubmitTimeout)
+				}
+				assert.Equal(t, 0, pool.MaxRetries)
+			})
+		})
+	})
+}
@@ -2,19 +2,27 @@ package main
 
 import (
 	log "github.com/Sirupsen/logrus"
+	"os"
 	"runtime"
 
+	"github.com/bgentry/speakeasy"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/stellar/gateway"
 	"github.com/stellar/gateway/config"
 	"github.com/stellar/gateway/db/migrations"
+	"github.com/stellar/gateway/listener"
+	"github.com/stellar/gateway/signer"
 )
 
 var app *gateway.App
 var rootCmd *cobra.Command
 var migrateFlag bool
 
+var accountFlag string
+var fromCursorFlag, toCursorFlag, cursorFlag string
+var fromLedgerFlag, toLedgerFlag, ledgerFlag uint32
+
 func main() {
 	runtime.GOMAXPROCS(runtime.NumCPU())
 	rootCmd.Execute()
@@ -33,6 +41,72 @@ func init() {
 	}
 
 	rootCmd.Flags().BoolVarP(&migrateFlag, "migrate-db", "", false, "migrate DB to the newest schema version")
+
+	keystoreCmd := &cobra.Command{
+		Use:   "keystore",
+		Short: "manage encrypted keystore file",
+	}
+
+	keystoreAddCmd := &cobra.Command{
+		Use:   "add [keystore file]",
+		Short: "encrypt secret seed and add it to keystore file",
+		Run:   keystoreAdd,
+	}
+
+	keystoreCmd.AddCommand(keystoreAddCmd)
+	rootCmd.AddCommand(keystoreCmd)
+
+	listenerCmd := &cobra.Command{
+		Use:   "listener",
+		Short: "manage payment listener",
+	}
+
+	listenerReplayCmd := &cobra.Command{
+		Use:   "replay",
+		Short: "process historical payments of a receiving account again",
+		Run:   listenerReplay,
+	}
+	listenerReplayCmd.Flags().StringVarP(&accountFlag, "account", "", "", "receiving account ID, required when there are multiple receiving accounts")
+	listenerReplayCmd.Flags().StringVarP(&fromCursorFlag, "from-cursor", "", "", "replay payments after this cursor")
+	listenerReplayCmd.Flags().Uint32VarP(&fromLedgerFlag, "from-ledger", "", 0, "replay payments starting from this ledger")
+	listenerReplayCmd.Flags().StringVarP(&toCursorFlag, "to-cursor", "", "", "replay payments up to this cursor (inclusive), default: the last payment processed by the listener")
+	listenerReplayCmd.Flags().Uint32VarP(&toLedgerFlag, "to-ledger", "", 0, "replay payments up to this ledger (inclusive), default: the last payment processed by the listener")
+
+	listenerResetCursorCmd := &cobra.Command{
+		Use:   "reset-cursor",
+		Short: "set cursor the payments stream of a receiving account is started from (stop the server first)",
+		Run:   listenerResetCursor,
+	}
+	listenerResetCursorCmd.Flags().StringVarP(&accountFlag, "account", "", "", "receiving account ID, required when there are multiple receiving accounts")
+	listenerResetCursorCmd.Flags().StringVarP(&cursorFlag, "cursor", "", "", "stream payments after this cursor (`now` to skip all existing payments)")
+	listenerResetCursorCmd.Flags().Uint32VarP(&ledgerFlag, "ledger", "", 0, "stream payments starting from this ledger")
+
+	listenerCmd.AddCommand(listenerReplayCmd)
+	listenerCmd.AddCommand(listenerResetCursorCmd)
+	rootCmd.AddCommand(listenerCmd)
+}
+
+func keystoreAdd(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		log.Fatal("Keystore file path is required")
+	}
+
+	seed, err := speakeasy.Ask("Secret seed: ")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	passphrase, err := speakeasy.Ask("Keystore passphrase: ")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = signer.AddToKeystore(args[0], seed, passphrase)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Print("Seed added to keystore")
 }
 
 func migrate(config config.Config) {
@@ -47,27 +121,122 @@ func migrate(config config.Config) {
 	migrationManager.MigrateUp()
 }
 
-func run(cmd *cobra.Command, args []string) {
+func readConfig() (config config.Config) {
 	log.Print("Reading config.toml file")
 	err := viper.ReadInConfig()
 	if err != nil {
 		log.Fatal("Error reading config file: ", err)
 	}
 
-	var config config.Config
 	err = viper.Unmarshal(&config)
 
 	err = config.Validate()
 	if err != nil {
 		log.Fatal(err.Error())
+	}
+	return
+}
+
+func readKeystorePassphrase(config *config.Config) {
+	if config.Signers == nil || config.Signers.Keystore == nil {
 		return
 	}
 
+	var err error
+	config.Signers.KeystorePassphrase = os.Getenv("GATEWAY_KEYSTORE_PASSPHRASE")
+	if config.Signers.KeystorePassphrase == "" {
+		config.Signers.KeystorePassphrase, err = speakeasy.Ask("Keystore passphrase: ")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+	}
+}
+
+// receivingAccountId returns --account flag value or ID of the only receiving
+// account.
+func receivingAccountId(config config.Config) string {
+	if accountFlag != "" {
+		return accountFlag
+	}
+
+	accounts := config.ReceivingAccounts()
+	if len(accounts) != 1 {
+		log.Fatal("--account flag is required when there are multiple receiving accounts")
+	}
+	return accounts[0].AccountId
+}
+
+func listenerReplay(cmd *cobra.Command, args []string) {
+	config := readConfig()
+
+	options := listener.ReplayOptions{AccountId: receivingAccountId(config)}
+
+	switch {
+	case fromCursorFlag != "" && fromLedgerFlag != 0:
+		log.Fatal("Only one of --from-cursor and --from-ledger flags can be set")
+	case fromCursorFlag != "":
+		options.FromCursor = fromCursorFlag
+	case fromLedgerFlag != 0:
+		options.FromCursor = listener.LedgerStartCursor(fromLedgerFlag)
+	default:
+		log.Fatal("--from-cursor or --from-ledger flag is required")
+	}
+
+	switch {
+	case toCursorFlag != "" && toLedgerFlag != 0:
+		log.Fatal("Only one of --to-cursor and --to-ledger flags can be set")
+	case toCursorFlag != "":
+		options.ToCursor = toCursorFlag
+	case toLedgerFlag != 0:
+		options.ToCursor = listener.LedgerEndCursor(toLedgerFlag)
+	}
+
+	if config.Refunds != nil {
+		readKeystorePassphrase(&config)
+	}
+
+	err := gateway.ReplayPayments(config, options)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+}
+
+func listenerResetCursor(cmd *cobra.Command, args []string) {
+	config := readConfig()
+
+	accountId := receivingAccountId(config)
+
+	var cursor string
+	switch {
+	case cursorFlag != "" && ledgerFlag != 0:
+		log.Fatal("Only one of --cursor and --ledger flags can be set")
+	case cursorFlag != "":
+		cursor = cursorFlag
+	case ledgerFlag != 0:
+		cursor = listener.LedgerStartCursor(ledgerFlag)
+	default:
+		log.Fatal("--cursor or --ledger flag is required")
+	}
+
+	err := gateway.ResetCursor(config, accountId, cursor)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	log.Print("Cursor saved")
+}
+
+func run(cmd *cobra.Command, args []string) {
+	config := readConfig()
+
 	if migrateFlag {
 		migrate(config)
 		return
 	}
 
+	readKeystorePassphrase(&config)
+
+	var err error
 	app, err = gateway.NewApp(config)
 
 	if err != nil {
@@ -4,32 +4,189 @@ import (
 	"errors"
 	"net/url"
 
+	"github.com/stellar/go-stellar-base/amount"
 	"github.com/stellar/go-stellar-base/keypair"
+	"github.com/stellar/go-stellar-base/xdr"
 )
 
 type Config struct {
-	Port              *int
-	Horizon           *string
+	Port             *int
+	Horizon          *string
+	HorizonFallbacks []string `mapstructure:"horizon_fallbacks"`
+	// HorizonTimeout and HorizonSubmitTimeout are timeouts (in seconds) of
+	// horizon read requests and transaction submission
+	HorizonTimeout       *int `mapstructure:"horizon_timeout"`
+	HorizonSubmitTimeout *int `mapstructure:"horizon_submit_timeout"`
+	// HorizonMaxRetries is a number of times failed horizon request is
+	// retried when all servers failed
+	HorizonMaxRetries *int   `mapstructure:"horizon_max_retries"`
 	ApiKey            string `mapstructure:"api_key"`
 	NetworkPassphrase string `mapstructure:"network_passphrase"`
+	DryRun            bool   `mapstructure:"dry_run"`
 	Assets            []string
 	Database          struct {
 		Type string
 		Url  string
 	}
-	Accounts *Accounts
-	Hooks    *Hooks
+	Accounts   *Accounts
+	Signers    *Signers
+	Hooks      *Hooks
+	Receiving  []ReceivingAccount
+	Refunds    *Refunds
+	Trustlines *Trustlines
+}
+
+// HorizonUrls returns URLs of all configured horizon servers starting with
+// the primary one.
+func (c *Config) HorizonUrls() []string {
+	return append([]string{*c.Horizon}, c.HorizonFallbacks...)
+}
+
+// ReceivingAccounts returns all accounts monitored for incoming payments:
+// `accounts.receiving_account_id` (if set) followed by `[[receiving]]`
+// accounts. Assets and hooks that are not set for an account are taken from
+// top-level `assets` and `hooks` params.
+func (c *Config) ReceivingAccounts() (accounts []ReceivingAccount) {
+	if c.Accounts != nil && c.Accounts.ReceivingAccountId != nil {
+		accounts = append(accounts, ReceivingAccount{AccountId: *c.Accounts.ReceivingAccountId})
+	}
+	accounts = append(accounts, c.Receiving...)
+
+	for i := range accounts {
+		if len(accounts[i].Assets) == 0 {
+			accounts[i].Assets = c.Assets
+		}
+		if c.Hooks != nil {
+			if accounts[i].ReceiveHook == nil {
+				accounts[i].ReceiveHook = c.Hooks.Receive
+			}
+			if accounts[i].ErrorHook == nil {
+				accounts[i].ErrorHook = c.Hooks.Error
+			}
+		}
+	}
+	return
 }
 
 type Accounts struct {
-	AuthorizingSeed    *string `mapstructure:"authorizing_seed"`
-	IssuingSeed        *string `mapstructure:"issuing_seed"`
-	ReceivingAccountId *string `mapstructure:"receiving_account_id"`
+	AuthorizingSeed      *string `mapstructure:"authorizing_seed"`
+	AuthorizingAccountId *string `mapstructure:"authorizing_account_id"`
+	// AuthorizingSigners are additional signers (secret seeds or account IDs
+	// of keys in keystore or remote signing service) of authorizing account
+	AuthorizingSigners []string `mapstructure:"authorizing_signers"`
+	IssuingSeed        *string  `mapstructure:"issuing_seed"`
+	IssuingAccountId   *string  `mapstructure:"issuing_account_id"`
+	// IssuingSigners are additional signers of issuing account
+	IssuingSigners     []string `mapstructure:"issuing_signers"`
+	ReceivingAccountId *string  `mapstructure:"receiving_account_id"`
+	ChannelSeeds       []string `mapstructure:"channel_seeds"`
+}
+
+// Signers configures signing of transactions for accounts that have only
+// `*_account_id` param set (secret seed is not in the config file).
+type Signers struct {
+	// Keystore is a path to encrypted keystore file
+	Keystore *string
+	// KeystorePassphrase is not read from the config file. It's set by
+	// GATEWAY_KEYSTORE_PASSPHRASE env variable or prompted at startup.
+	KeystorePassphrase string `mapstructure:"-"`
+	// RemoteUrl is an URL of remote signing service
+	RemoteUrl *string `mapstructure:"remote_url"`
+	// RemoteToken is sent to remote signing service in Authorization header
+	RemoteToken *string `mapstructure:"remote_token"`
+	// PaymentSources are IDs of accounts that can be sent as `source` of
+	// /payment and signed with a key from keystore or remote signing
+	// service. Other accounts must send a secret seed.
+	PaymentSources []string `mapstructure:"payment_sources"`
+}
+
+// IsPaymentSource returns true when accountId is in `signers.payment_sources`.
+func (s *Signers) IsPaymentSource(accountId string) bool {
+	if s == nil {
+		return false
+	}
+	for _, source := range s.PaymentSources {
+		if source == accountId {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAuthorizingAccountId returns ID of authorizing account or empty string
+// if authorizing account is not configured.
+func (a *Accounts) GetAuthorizingAccountId() string {
+	return getAccountId(a.AuthorizingSeed, a.AuthorizingAccountId)
+}
+
+// GetIssuingAccountId returns ID of issuing account or empty string if
+// issuing account is not configured.
+func (a *Accounts) GetIssuingAccountId() string {
+	return getAccountId(a.IssuingSeed, a.IssuingAccountId)
+}
+
+func getAccountId(seed, accountId *string) string {
+	if accountId != nil {
+		return *accountId
+	}
+	if seed != nil {
+		kp, err := keypair.Parse(*seed)
+		if err == nil {
+			return kp.Address()
+		}
+	}
+	return ""
 }
 
 type Hooks struct {
 	Receive *string
 	Error   *string
+	// MaxAttempts is a number of delivery attempts after which a hook
+	// request is marked as failed
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// Secrets are used to sign hook requests. Two secrets can be set
+	// during secret rotation.
+	Secrets []string
+}
+
+// ReceivingAccount is an account monitored for incoming payments configured
+// in `[[receiving]]` section.
+type ReceivingAccount struct {
+	AccountId   string `mapstructure:"account_id"`
+	Assets      []string
+	ReceiveHook *string `mapstructure:"receive_hook"`
+	ErrorHook   *string `mapstructure:"error_hook"`
+}
+
+// Refunds configures automatic refunds of rejected incoming payments.
+type Refunds struct {
+	// Reasons are error hook reasons (asset_not_allowed, memo_missing) of
+	// payments that are sent back to the sender
+	Reasons []string
+	// Fee is an amount subtracted from the refunded amount
+	Fee string
+}
+
+// IsRefunded returns true if payments rejected for a given reason should
+// be refunded.
+func (r *Refunds) IsRefunded(reason string) bool {
+	for _, refundedReason := range r.Reasons {
+		if refundedReason == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// Trustlines configures automatic authorization of trustlines to `assets`
+// of the issuing account.
+type Trustlines struct {
+	// Policy decides if a trustline is authorized: allowlist, db or hook
+	Policy string
+	// Allowlist contains IDs of accounts authorized by allowlist policy
+	Allowlist []string
+	// Hook is an URL of the service asked by hook policy
+	Hook *string
 }
 
 func (c *Config) Validate() (err error) {
@@ -49,6 +206,29 @@ func (c *Config) Validate() (err error) {
 		}
 	}
 
+	if c.HorizonTimeout != nil && *c.HorizonTimeout <= 0 {
+		err = errors.New("horizon_timeout param must be greater than 0")
+		return
+	}
+
+	if c.HorizonSubmitTimeout != nil && *c.HorizonSubmitTimeout <= 0 {
+		err = errors.New("horizon_submit_timeout param must be greater than 0")
+		return
+	}
+
+	if c.HorizonMaxRetries != nil && *c.HorizonMaxRetries < 0 {
+		err = errors.New("horizon_max_retries param cannot be negative")
+		return
+	}
+
+	for _, horizonUrl := range c.HorizonFallbacks {
+		_, err = url.Parse(horizonUrl)
+		if err != nil {
+			err = errors.New("Cannot parse horizon_fallbacks param")
+			return
+		}
+	}
+
 	if c.NetworkPassphrase == "" {
 		err = errors.New("network_passphrase param is required")
 		return
@@ -76,27 +256,67 @@ func (c *Config) Validate() (err error) {
 		return
 	}
 
+	if c.Hooks != nil {
+		if len(c.Hooks.Secrets) > 2 {
+			err = errors.New("hooks.secrets can contain at most 2 secrets")
+			return
+		}
+		for _, secret := range c.Hooks.Secrets {
+			if len(secret) < 16 {
+				err = errors.New("hooks.secrets must be at least 16 chars long")
+				return
+			}
+		}
+	}
+
 	if c.Accounts != nil {
-		if c.Accounts.AuthorizingSeed != nil {
-			_, err = keypair.Parse(*c.Accounts.AuthorizingSeed)
+		err = validateAccount("authorizing", c.Accounts.AuthorizingSeed, c.Accounts.AuthorizingAccountId, c.Accounts.AuthorizingSigners)
+		if err != nil {
+			return
+		}
+
+		err = validateAccount("issuing", c.Accounts.IssuingSeed, c.Accounts.IssuingAccountId, c.Accounts.IssuingSigners)
+		if err != nil {
+			return
+		}
+
+		if c.Accounts.ReceivingAccountId != nil {
+			_, err = keypair.Parse(*c.Accounts.ReceivingAccountId)
 			if err != nil {
-				err = errors.New("accounts.authorizing_seed is invalid")
+				err = errors.New("accounts.receiving_account_id is invalid")
 				return
 			}
 		}
 
-		if c.Accounts.IssuingSeed != nil {
-			_, err = keypair.Parse(*c.Accounts.IssuingSeed)
-			if err != nil {
-				err = errors.New("accounts.issuing_seed is invalid")
+		for _, seed := range c.Accounts.ChannelSeeds {
+			var kp keypair.KP
+			kp, err = keypair.Parse(seed)
+			if _, ok := kp.(*keypair.Full); err != nil || !ok {
+				err = errors.New("accounts.channel_seeds contains invalid seed")
 				return
 			}
 		}
+	}
 
-		if c.Accounts.ReceivingAccountId != nil {
-			_, err = keypair.Parse(*c.Accounts.ReceivingAccountId)
+	if c.Signers != nil {
+		if c.Signers.RemoteUrl != nil {
+			_, err = url.Parse(*c.Signers.RemoteUrl)
 			if err != nil {
-				err = errors.New("accounts.receiving_account_id is invalid")
+				err = errors.New("Cannot parse signers.remote_url param")
+				return
+			}
+		}
+
+		if c.Signers.RemoteToken != nil && c.Signers.RemoteUrl == nil {
+			err = errors.New("signers.remote_token param requires signers.remote_url")
+			return
+		}
+
+		for _, source := range c.Signers.PaymentSources {
+			var kp keypair.KP
+			kp, err = keypair.Parse(source)
+			if _, ok := kp.(*keypair.FromAddress); err != nil || !ok {
+				err = errors.New("signers.payment_sources contains invalid account ID")
 				return
 			}
 		}
@@ -120,5 +340,128 @@ func (c *Config) Validate() (err error) {
 		}
 	}
 
+	if c.Refunds != nil {
+		for _, reason := range c.Refunds.Reasons {
+			if reason != "asset_not_allowed" && reason != "memo_missing" {
+				err = errors.New("refunds.reasons contains invalid reason: " + reason)
+				return
+			}
+		}
+
+		if c.Refunds.Fee != "" {
+			var fee xdr.Int64
+			fee, err = amount.Parse(c.Refunds.Fee)
+			if err != nil || fee < 0 {
+				err = errors.New("refunds.fee is invalid")
+				return
+			}
+		}
+	}
+
+	if c.Trustlines != nil {
+		err = c.validateTrustlines()
+		if err != nil {
+			return
+		}
+	}
+
+	receivingAccounts := map[string]bool{}
+	for _, account := range c.ReceivingAccounts() {
+		_, err = keypair.Parse(account.AccountId)
+		if err != nil {
+			err = errors.New("receiving.account_id is invalid")
+			return
+		}
+
+		if receivingAccounts[account.AccountId] {
+			err = errors.New("Receiving account " + account.AccountId + " is configured more than once")
+			return
+		}
+		receivingAccounts[account.AccountId] = true
+
+		if account.ReceiveHook != nil {
+			_, err = url.Parse(*account.ReceiveHook)
+			if err != nil {
+				err = errors.New("Cannot parse receiving.receive_hook param")
+				return
+			}
+		}
+
+		if account.ErrorHook != nil {
+			_, err = url.Parse(*account.ErrorHook)
+			if err != nil {
+				err = errors.New("Cannot parse receiving.error_hook param")
+				return
+			}
+		}
+	}
+
+	return
+}
+
+func validateAccount(name string, seed, accountId *string, signers []string) (err error) {
+	if seed != nil {
+		var kp keypair.KP
+		kp, err = keypair.Parse(*seed)
+		if err != nil {
+			err = errors.New("accounts." + name + "_seed is invalid")
+			return
+		}
+
+		if accountId != nil && kp.Address() != *accountId {
+			err = errors.New("accounts." + name + "_seed does not match accounts." + name + "_account_id")
+			return
+		}
+	}
+
+	if accountId != nil {
+		_, err = keypair.Parse(*accountId)
+		if err != nil {
+			err = errors.New("accounts." + name + "_account_id is invalid")
+			return
+		}
+	}
+
+	for _, signer := range signers {
+		_, err = keypair.Parse(signer)
+		if err != nil {
+			err = errors.New("accounts." + name + "_signers contains invalid signer")
+			return
+		}
+	}
+
+	if len(signers) > 0 && seed == nil && accountId == nil {
+		err = errors.New("accounts." + name + "_signers requires accounts." + name + "_seed or accounts." + name + "_account_id")
+		return
+	}
+	return
+}
+
+func (c *Config) validateTrustlines() (err error) {
+	if c.Accounts == nil || c.Accounts.GetIssuingAccountId() == "" || c.Accounts.GetAuthorizingAccountId() == "" {
+		return errors.New("trustlines requires issuing and authorizing accounts")
+	}
+
+	switch c.Trustlines.Policy {
+	case "allowlist":
+		for _, accountId := range c.Trustlines.Allowlist {
+			_, err = keypair.Parse(accountId)
+			if err != nil {
+				return errors.New("trustlines.allowlist contains invalid account ID")
+			}
+		}
+	case "db":
+		break
+	case "hook":
+		if c.Trustlines.Hook == nil {
+			return errors.New("trustlines.hook param is required by hook policy")
+		}
+		_, err = url.Parse(*c.Trustlines.Hook)
+		if err != nil {
+			return errors.New("Cannot parse trustlines.hook param")
+		}
+	default:
+		return errors.New("Invalid trustlines.policy param")
+	}
 	return
 }
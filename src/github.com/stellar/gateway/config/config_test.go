@@ -0,0 +1,38 @@
+package config
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigValidate(t *testing.T) {
+	Convey("Given config with channel seeds", t, func() {
+		port := 8000
+		horizon := "https://horizon-testnet.stellar.org"
+		config := Config{
+			Port:              &port,
+			Horizon:           &horizon,
+			NetworkPassphrase: "Test SDF Network ; September 2015",
+			Accounts:          &Accounts{},
+		}
+		config.Database.Type = "sqlite3"
+		config.Database.Url = "gateway.db"
+
+		Convey("it should accept seeds", func() {
+			config.Accounts.ChannelSeeds = []string{"SC34WILLHVADXMP6ACPMIRA6TRAWJMVCLPFNW7S6MUMXJVLAZUC4EWHP"}
+			assert.Nil(t, config.Validate())
+		})
+
+		Convey("it should return error when a seed is invalid", func() {
+			config.Accounts.ChannelSeeds = []string{"invalid"}
+			assert.EqualError(t, config.Validate(), "accounts.channel_seeds contains invalid seed")
+		})
+
+		Convey("it should return error when an account ID is used instead of a seed", func() {
+			config.Accounts.ChannelSeeds = []string{"GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR"}
+			assert.EqualError(t, config.Validate(), "accounts.channel_seeds contains invalid seed")
+		})
+	})
+}
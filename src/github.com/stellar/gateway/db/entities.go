@@ -11,11 +11,30 @@ type Entity interface {
 }
 
 type ReceivedPayment struct {
-	Id          *int64    `db:"id"`
-	OperationId string    `db:"operation_id"`
-	ProcessedAt time.Time `db:"processed_at"`
-	PagingToken string    `db:"paging_token"`
-	Status      string    `db:"status"`
+	Id              *int64    `db:"id"`
+	OperationId     string    `db:"operation_id"`
+	AccountId       string    `db:"account_id"`
+	ProcessedAt     time.Time `db:"processed_at"`
+	PagingToken     string    `db:"paging_token"`
+	Status          string    `db:"status"`
+	TransactionHash string    `db:"transaction_hash"`
+	From            string    `db:"from_account"`
+	Amount          string    `db:"amount"`
+	AssetCode       string    `db:"asset_code"`
+	AssetIssuer     string    `db:"asset_issuer"`
+	MemoType        string    `db:"memo_type"`
+	Memo            string    `db:"memo"`
+	// HookStatusCode is a status code of the last receive hook response
+	HookStatusCode *int `db:"hook_status_code"`
+	// HookAttempts is a number of receive hook requests sent
+	HookAttempts int `db:"hook_attempts"`
+	// RefundStatus is set when the payment is refunded: pending (refund
+	// submitted but the result is unknown), sent or failed
+	RefundStatus          *string `db:"refund_status"`
+	RefundTransactionHash *string `db:"refund_transaction_hash"`
+	// RefundTransactionId is an id of SentTransaction of the refund. Pending
+	// refunds are resolved by TransactionRecoverer.
+	RefundTransactionId *int64 `db:"refund_transaction_id"`
 }
 
 type SentTransaction struct {
@@ -28,6 +47,69 @@ type SentTransaction struct {
 	Ledger        *uint64    `db:"ledger"`
 	EnvelopeXdr   string     `db:"envelope_xdr"`
 	ResultXdr     *string    `db:"result_xdr"`
+	// HorizonUrl is an URL of horizon server the transaction was submitted to
+	HorizonUrl *string `db:"horizon_url"`
+	// IdempotencyKey is `idempotency_key` of the request that submitted the
+	// transaction
+	IdempotencyKey *string `db:"idempotency_key"`
+	// RetryOf is an id of the first attempt when the transaction was rebuilt
+	// and resubmitted after transaction_bad_seq
+	RetryOf *int64 `db:"retry_of"`
+}
+
+type IdempotencyKey struct {
+	Id           *int64     `db:"id"`
+	Key          string     `db:"idempotency_key"`
+	RequestHash  string     `db:"request_hash"`
+	Status       string     `db:"status"` // processing/completed
+	ResponseCode *int       `db:"response_code"`
+	ResponseBody *string    `db:"response_body"`
+	CreatedAt    time.Time  `db:"created_at"`
+	CompletedAt  *time.Time `db:"completed_at"`
+}
+
+// HookDelivery is a request to a webhook (ex. hooks.receive) waiting to be
+// delivered or already delivered.
+type HookDelivery struct {
+	Id   *int64 `db:"id"`
+	Hook string `db:"hook"` // receive/error
+	Url  string `db:"url"`
+	// Payload contains form encoded request params
+	Payload       string     `db:"payload"`
+	OperationId   string     `db:"operation_id"`
+	Status        string     `db:"status"` // pending/delivered/failed
+	Attempts      int        `db:"attempts"`
+	NextAttemptAt time.Time  `db:"next_attempt_at"`
+	LastError     *string    `db:"last_error"`
+	CreatedAt     time.Time  `db:"created_at"`
+	DeliveredAt   *time.Time `db:"delivered_at"`
+}
+
+// StreamCursor is a cursor of the last event processed from a horizon stream
+// (ex. payments of a receiving account).
+type StreamCursor struct {
+	Id        *int64    `db:"id"`
+	Name      string    `db:"name"`
+	Cursor    string    `db:"paging_token"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// TrustlineAuthorization is a decision about a trustline created to an asset
+// of the issuing account made by the trustline watcher.
+type TrustlineAuthorization struct {
+	Id          *int64 `db:"id"`
+	OperationId string `db:"operation_id"`
+	AccountId   string `db:"account_id"`
+	AssetCode   string `db:"asset_code"`
+	Status      string `db:"status"` // authorized/rejected/pending/failed
+	// Reason is a rejection reason or an error code of failed allow_trust
+	// transaction
+	Reason          *string   `db:"reason"`
+	TransactionHash *string   `db:"transaction_hash"`
+	CreatedAt       time.Time `db:"created_at"`
+	// TransactionId is an id of SentTransaction of allow_trust. Pending
+	// authorizations are resolved by TransactionRecoverer.
+	TransactionId *int64 `db:"transaction_id"`
 }
 
 func (rp *ReceivedPayment) GetId() *int64 {
@@ -46,6 +128,13 @@ func (st *SentTransaction) SetId(id int64) {
 	st.Id = &id
 }
 
+// SetHorizonUrl saves URL of horizon server that handled transaction submission.
+func (st *SentTransaction) SetHorizonUrl(url string) {
+	if url != "" {
+		st.HorizonUrl = &url
+	}
+}
+
 func (st *SentTransaction) MarkSucceeded(ledger uint64) {
 	st.Status = "success"
 	st.Ledger = &ledger
@@ -58,20 +147,120 @@ func (st *SentTransaction) MarkFailed(resultXdr string) {
 	st.ResultXdr = &resultXdr
 }
 
+// MarkSequenceConsumed marks transaction as failed when its sequence number
+// was used by another transaction so it will never be included in a ledger.
+func (st *SentTransaction) MarkSequenceConsumed() {
+	st.Status = "failure"
+	st.ResultXdr = nil
+}
+
+func (ik *IdempotencyKey) GetId() *int64 {
+	return ik.Id
+}
+
+func (ik *IdempotencyKey) SetId(id int64) {
+	ik.Id = &id
+}
+
+func (ik *IdempotencyKey) MarkCompleted(responseCode int, responseBody string) {
+	ik.Status = "completed"
+	ik.ResponseCode = &responseCode
+	ik.ResponseBody = &responseBody
+	now := time.Now()
+	ik.CompletedAt = &now
+}
+
+func (hd *HookDelivery) GetId() *int64 {
+	return hd.Id
+}
+
+func (hd *HookDelivery) SetId(id int64) {
+	hd.Id = &id
+}
+
+func (hd *HookDelivery) MarkDelivered(now time.Time) {
+	hd.Status = "delivered"
+	hd.Attempts++
+	hd.LastError = nil
+	hd.DeliveredAt = &now
+}
+
+// MarkAttemptFailed records failed delivery attempt. When nextAttemptAt is
+// nil delivery will not be retried anymore (it's dead-lettered).
+func (hd *HookDelivery) MarkAttemptFailed(lastError string, nextAttemptAt *time.Time) {
+	hd.Attempts++
+	hd.LastError = &lastError
+	if nextAttemptAt == nil {
+		hd.Status = "failed"
+	} else {
+		hd.NextAttemptAt = *nextAttemptAt
+	}
+}
+
+// MarkPending schedules delivery to be sent again from the first attempt.
+func (hd *HookDelivery) MarkPending(now time.Time) {
+	hd.Status = "pending"
+	hd.Attempts = 0
+	hd.NextAttemptAt = now
+	hd.DeliveredAt = nil
+}
+
+func (sc *StreamCursor) GetId() *int64 {
+	return sc.Id
+}
+
+func (sc *StreamCursor) SetId(id int64) {
+	sc.Id = &id
+}
+
+func (ta *TrustlineAuthorization) GetId() *int64 {
+	return ta.Id
+}
+
+func (ta *TrustlineAuthorization) SetId(id int64) {
+	ta.Id = &id
+}
+
 func GetInsertQuery(objectType string) (query string, err error) {
 	switch objectType {
 	case "*db.ReceivedPayment":
 		query = `
 		INSERT INTO ReceivedPayment
-			(operation_id, processed_at, paging_token, status)
+			(operation_id, account_id, processed_at, paging_token, status, transaction_hash, from_account, amount, asset_code, asset_issuer, memo_type, memo, hook_status_code, hook_attempts, refund_status, refund_transaction_hash, refund_transaction_id)
 		VALUES
-			(:operation_id, :processed_at, :paging_token, :status)`
+			(:operation_id, :account_id, :processed_at, :paging_token, :status, :transaction_hash, :from_account, :amount, :asset_code, :asset_issuer, :memo_type, :memo, :hook_status_code, :hook_attempts, :refund_status, :refund_transaction_hash, :refund_transaction_id)`
 	case "*db.SentTransaction":
 		query = `
 		INSERT INTO SentTransaction
-			(status, source, submitted_at, succeeded_at, ledger, enveloper_xdr, result_xdr)
+			(status, source, submitted_at, succeeded_at, ledger, envelope_xdr, result_xdr, horizon_url, idempotency_key, retry_of)
+		VALUES
+			(:status, :source, :submitted_at, :succeeded_at, :ledger, :envelope_xdr, :result_xdr, :horizon_url, :idempotency_key, :retry_of)`
+	case "*db.IdempotencyKey":
+		query = `
+		INSERT INTO IdempotencyKey
+			(idempotency_key, request_hash, status, response_code, response_body, created_at, completed_at)
+		VALUES
+			(:idempotency_key, :request_hash, :status, :response_code, :response_body, :created_at, :completed_at)`
+	case "*db.HookDelivery":
+		query = `
+		INSERT INTO HookDelivery
+			(hook, url, payload, operation_id, status, attempts, next_attempt_at, last_error, created_at, delivered_at)
+		VALUES
+			(:hook, :url, :payload, :operation_id, :status, :attempts, :next_attempt_at, :last_error, :created_at, :delivered_at)`
+	case "*db.StreamCursor":
+		query = `
+		INSERT INTO StreamCursor
+			(name, paging_token, updated_at)
 		VALUES
-			(:status, :source, :submitted_at, :succeeded_at, :ledger, :enveloper_xdr, :result_xdr)`
+			(:name, :paging_token, :updated_at)
+		`
+	case "*db.TrustlineAuthorization":
+		query = `
+		INSERT INTO TrustlineAuthorization
+			(operation_id, account_id, asset_code, status, reason, transaction_hash, created_at, transaction_id)
+		VALUES
+			(:operation_id, :account_id, :asset_code, :status, :reason, :transaction_hash, :created_at, :transaction_id)
+		`
 	default:
 		err = fmt.Errorf("No INSERT query for: %s (must be a pointer)", objectType)
 	}
@@ -84,9 +273,22 @@ func GetUpdateQuery(objectType string) (query string, err error) {
 		query = `
 		UPDATE ReceivedPayment SET
 			operation_id = :operation_id,
+			account_id = :account_id,
 			processed_at = :processed_at,
 			paging_token = :paging_token,
-			status = :status
+			status = :status,
+			transaction_hash = :transaction_hash,
+			from_account = :from_account,
+			amount = :amount,
+			asset_code = :asset_code,
+			asset_issuer = :asset_issuer,
+			memo_type = :memo_type,
+			memo = :memo,
+			hook_status_code = :hook_status_code,
+			hook_attempts = :hook_attempts,
+			refund_status = :refund_status,
+			refund_transaction_hash = :refund_transaction_hash,
+			refund_transaction_id = :refund_transaction_id
 		WHERE
 			id = :id
 		`
@@ -98,8 +300,63 @@ func GetUpdateQuery(objectType string) (query string, err error) {
 			submitted_at = :submitted_at,
 			succeeded_at = :succeeded_at,
 			ledger = :ledger,
-			enveloper_xdr = :enveloper_xdr,
-			result_xdr = :result_xdr
+			envelope_xdr = :envelope_xdr,
+			result_xdr = :result_xdr,
+			horizon_url = :horizon_url,
+			idempotency_key = :idempotency_key,
+			retry_of = :retry_of
+		WHERE
+			id = :id
+		`
+	case "*db.IdempotencyKey":
+		query = `
+		UPDATE IdempotencyKey SET
+			idempotency_key = :idempotency_key,
+			request_hash = :request_hash,
+			status = :status,
+			response_code = :response_code,
+			response_body = :response_body,
+			created_at = :created_at,
+			completed_at = :completed_at
+		WHERE
+			id = :id
+		`
+	case "*db.HookDelivery":
+		query = `
+		UPDATE HookDelivery SET
+			hook = :hook,
+			url = :url,
+			payload = :payload,
+			operation_id = :operation_id,
+			status = :status,
+			attempts = :attempts,
+			next_attempt_at = :next_attempt_at,
+			last_error = :last_error,
+			created_at = :created_at,
+			delivered_at = :delivered_at
+		WHERE
+			id = :id
+		`
+	case "*db.StreamCursor":
+		query = `
+		UPDATE StreamCursor SET
+			name = :name,
+			paging_token = :paging_token,
+			updated_at = :updated_at
+		WHERE
+			id = :id
+		`
+	case "*db.TrustlineAuthorization":
+		query = `
+		UPDATE TrustlineAuthorization SET
+			operation_id = :operation_id,
+			account_id = :account_id,
+			asset_code = :asset_code,
+			status = :status,
+			reason = :reason,
+			transaction_hash = :transaction_hash,
+			created_at = :created_at,
+			transaction_id = :transaction_id
 		WHERE
 			id = :id
 		`
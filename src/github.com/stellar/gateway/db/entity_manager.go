@@ -1,6 +1,7 @@
 package db
 
 import (
+	"database/sql"
 	"fmt"
 
 	"github.com/Sirupsen/logrus"
@@ -12,6 +13,7 @@ import (
 
 type EntityManagerInterface interface {
 	Persist(object Entity) (err error)
+	PersistAll(objects ...Entity) (err error)
 }
 
 type EntityManager struct {
@@ -19,6 +21,11 @@ type EntityManager struct {
 	log *logrus.Entry
 }
 
+// namedExecer is implemented by sqlx.DB and sqlx.Tx
+type namedExecer interface {
+	NamedExec(query string, arg interface{}) (sql.Result, error)
+}
+
 func NewEntityManager(dbType string, url string) (em EntityManager, err error) {
 	em.db, err = sqlx.Connect(dbType, url)
 	em.log = logrus.WithFields(logrus.Fields{
@@ -28,6 +35,48 @@ func NewEntityManager(dbType string, url string) (em EntityManager, err error) {
 }
 
 func (em *EntityManager) Persist(object Entity) (err error) {
+	id, err := em.persist(em.db, object)
+	if err != nil {
+		return
+	}
+	if id != nil {
+		object.SetId(*id)
+	}
+	return
+}
+
+// PersistAll saves objects in a single DB transaction. IDs of inserted
+// objects are set only when the transaction is committed.
+func (em *EntityManager) PersistAll(objects ...Entity) (err error) {
+	tx, err := em.db.Beginx()
+	if err != nil {
+		return
+	}
+
+	ids := make([]*int64, len(objects))
+	for i, object := range objects {
+		ids[i], err = em.persist(tx, object)
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return
+	}
+
+	for i, object := range objects {
+		if ids[i] != nil {
+			object.SetId(*ids[i])
+		}
+	}
+	return
+}
+
+// persist inserts or updates object. Returns ID of the inserted object.
+func (em *EntityManager) persist(db namedExecer, object Entity) (insertedId *int64, err error) {
 	objectType := fmt.Sprintf("%T", object)
 	var query string
 
@@ -43,19 +92,19 @@ func (em *EntityManager) Persist(object Entity) (err error) {
 		return
 	}
 
-	result, err := em.db.NamedExec(query, object)
+	result, err := db.NamedExec(query, object)
 	if err != nil {
 		return
 	}
 
 	if object.GetId() == nil {
 		var id int64
-		// Just inserted a new object - set it's ID
+		// Just inserted a new object - return it's ID
 		id, err = result.LastInsertId()
 		if err != nil {
 			return
 		}
-		object.SetId(id)
+		insertedId = &id
 	}
 	return
 }
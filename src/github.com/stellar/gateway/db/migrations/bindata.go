@@ -1,7 +1,41 @@
 // Code generated by go-bindata.
 // sources:
 // mysql/mysql_01_init.sql
+// mysql/mysql_02_idempotency_key.sql
+// mysql/mysql_03_sent_transaction_horizon_url.sql
+// mysql/mysql_04_hook_delivery.sql
+// mysql/mysql_05_received_payment_operation_id.sql
+// mysql/mysql_06_received_payment_details.sql
+// mysql/mysql_07_received_payment_account_id.sql
+// mysql/mysql_08_received_payment_refund.sql
+// mysql/mysql_09_stream_cursor.sql
+// mysql/mysql_10_stream_cursor_seed.sql
+// mysql/mysql_11_trustline_authorization.sql
+// mysql/mysql_12_hook_delivery_operation_id.sql
+// mysql/mysql_13_sent_transaction_idempotency_key.sql
+// mysql/mysql_14_pending_transaction_id.sql
+// mysql/mysql_15_sent_transaction_retry_of.sql
 // postgres/postgres_01_init.sql
+// postgres/postgres_02_idempotency_key.sql
+// postgres/postgres_03_sent_transaction_horizon_url.sql
+// postgres/postgres_04_hook_delivery.sql
+// postgres/postgres_05_received_payment_operation_id.sql
+// postgres/postgres_06_received_payment_details.sql
+// postgres/postgres_07_received_payment_account_id.sql
+// postgres/postgres_08_received_payment_refund.sql
+// postgres/postgres_09_stream_cursor.sql
+// postgres/postgres_10_stream_cursor_seed.sql
+// postgres/postgres_11_trustline_authorization.sql
+// postgres/postgres_12_hook_delivery_operation_id.sql
+// postgres/postgres_13_sent_transaction_idempotency_key.sql
+// postgres/postgres_14_pending_transaction_id.sql
+// postgres/postgres_15_sent_transaction_retry_of.sql
+// sqlite3/sqlite3_01_init.sql
+// sqlite3/sqlite3_02_trustline_authorization.sql
+// sqlite3/sqlite3_03_hook_delivery_operation_id.sql
+// sqlite3/sqlite3_04_sent_transaction_idempotency_key.sql
+// sqlite3/sqlite3_05_pending_transaction_id.sql
+// sqlite3/sqlite3_06_sent_transaction_retry_of.sql
 // DO NOT EDIT!
 
 package migrations
@@ -69,7 +103,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _mysqlMysql_01_initSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x9c\x92\x41\x4f\xc2\x40\x10\x85\xef\xfd\x15\x73\x2c\x51\x12\x21\xc1\x98\x10\x0e\x85\xae\xda\x58\x0a\x29\xdb\x03\xa7\x76\x69\xc7\xba\x91\xee\x92\xdd\x2d\xe2\xbf\x77\xab\x51\x64\x8d\x24\x7a\x9e\x6f\x66\xde\x7b\x33\xfd\x3e\x5c\x34\xbc\x56\xcc\x20\x64\x3b\x6f\x96\x92\x80\x12\xa0\xc1\x34\x26\x50\xa4\x58\x22\xdf\x63\xb5\x64\xaf\x0d\x0a\x53\x80\xef\x01\x14\xbc\x2a\x80\x0b\xe3\x0f\x06\x3d\x48\x16\x14\x92\x2c\x8e\x21\xc8\xe8\x22\x8f\x12\xdb\x3f\x27\x09\xbd\xec\x38\xb9\x43\x3b\x96\x4b\x91\x77\x1d\x7b\xa6\xca\x27\xa6\xfc\xe1\x68\x74\x6c\x7b\xe7\x76\x4a\x96\xa8\x35\x56\x39\xb3\x2b\x2a\xab\xc4\xf0\x06\x1d\x86\xd5\x5c\xd4\xb9\x91\xcf\x28\xce\xcd\xd2\x86\x99\x56\x9f\x21\x96\x69\x34\x0f\xd2\x35\x3c\x90\x35\xf8\x9d\x95\x9e\xd7\x03\x92\xdc\x45\x09\x99\x44\x42\xc8\x70\x0a\x21\xb9\x0d\xb2\x98\xc2\xec\x3e\x48\x57\x84\x4e\x5a\xf3\x78\x33\xf6\x9c\x68\x56\x36\x0f\xaa\x98\xd0\xac\xec\x2c\xfe\x31\x1a\x57\xe6\xe0\xca\xf5\x21\x5b\x55\xe2\x11\x18\x5d\xbb\x40\xbb\x69\xb8\x31\x67\x43\xd3\x6d\x59\x22\x56\x2e\xf3\xe9\xef\x8b\xdb\x62\x55\xa3\x2a\x60\xc3\xeb\x4e\xfc\xd0\x8a\xf9\xc1\xa0\xd8\xe3\xd6\x5e\x34\x3f\x54\x96\x34\x78\x30\xa7\xbb\x14\xea\x76\x6b\x3e\xaa\x27\xe1\xbb\x93\xfe\x7f\x80\xef\xaf\x1a\xca\x17\xe1\x85\xe9\x62\xf9\xdb\xab\x8e\x4f\xaa\xee\xb5\xc6\xde\x5b\x00\x00\x00\xff\xff\x57\x0e\x7e\x05\xf8\x02\x00\x00")
+var _mysqlMysql_01_initSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x92\xcd\x8e\xda\x30\x10\xc7\xef\x79\x8a\x39\x26\x6a\x91\x00\x89\xaa\x12\xe2\x10\x88\xdb\x46\x0d\x01\x05\xe7\xc0\x29\x36\xc9\x34\xb5\x4a\xec\xc8\x9e\x50\xfa\xf6\x55\x58\xb1\x2c\x59\x2d\xd2\xee\xd9\xbf\x19\xff\x3f\x66\x34\x82\x4f\x8d\xaa\xad\x24\x84\xbc\xf5\x56\x19\x0b\x39\x03\x1e\x2e\x13\x06\x22\xc3\x12\xd5\x09\xab\xad\xfc\xd7\xa0\x26\x01\xbe\x07\x20\x54\x25\x40\x69\xf2\x27\x93\x00\xd2\x0d\x87\x34\x4f\x12\x08\x73\xbe\x29\xe2\x74\x95\xb1\x35\x4b\xf9\xe7\x9e\x33\x2d\x5a\x49\xca\xe8\xa2\x9f\x38\x49\x5b\xfe\x96\xd6\x9f\xce\x66\xb7\xb1\x0b\xd7\x5a\x53\xa2\x73\x58\x15\x92\x04\x54\x92\x90\x54\x83\x03\x46\xd6\x4a\xd7\x05\x99\x3f\xa8\x1f\xed\x72\x24\xa9\x73\x0f\x88\x6d\x16\xaf\xc3\x6c\x0f\x3f\xd9\x1e\xfc\xde\x4a\xe0\x05\xc0\xd2\xef\x71\xca\x16\xb1\xd6\x26\x5a\x42\xc4\xbe\x85\x79\xc2\x61\xf5\x23\xcc\x76\x8c\x2f\x3a\xfa\xf5\x75\xee\x0d\xa2\xd9\xa1\x26\x6e\xa5\x76\xb2\xec\x2d\xbe\x33\x9a\xa1\xcc\xc9\xf8\x5e\xa5\x70\xa6\xb3\x25\xde\x80\xd9\x97\x21\xd0\x1d\x1a\x45\xf4\x30\x34\xd7\x95\x25\x62\x35\x64\xae\xfe\x9e\xb9\x23\x56\x35\x5a\x01\x07\x55\xf7\xbd\x4e\xc7\xc1\x6b\x06\xf5\x09\x8f\xa6\xc5\xe2\x5c\x59\x01\x84\x67\xba\xff\xcb\xa2\xeb\x8e\xf4\xf4\x7a\x15\x7d\x09\x7f\xb8\xe9\xe3\x05\xbc\x3c\xd5\xc8\xfc\xd5\x5e\x94\x6d\xb6\x6f\x9d\xea\xfc\xee\x75\xd8\xd6\xdc\xfb\x3f\x00\x57\x0e\x7e\x05\xf8\x02\x00\x00")
 
 func mysqlMysql_01_initSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -84,12 +118,292 @@ func mysqlMysql_01_initSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "mysql/mysql_01_init.sql", size: 760, mode: os.FileMode(420), modTime: time.Unix(1453985025, 0)}
+	info := bindataFileInfo{name: "mysql/mysql_01_init.sql", size: 760, mode: os.FileMode(420), modTime: time.Unix(1454086036, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _postgresPostgres_01_initSql = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x7c\x92\x3f\x4f\xc3\x30\x10\xc5\x77\x7f\x8a\x1b\x5b\x41\x25\x40\x0a\x4b\xa6\x40\x82\x84\x08\x6d\x14\x92\xa1\x53\xe4\xda\xa7\x60\x91\xd8\x91\x7d\x09\xe5\xdb\xe3\x32\x40\xe3\xb6\x8c\xf6\xef\xdd\x9f\xf7\x74\xab\x15\x5c\xf5\xaa\xb5\x9c\x10\xea\x81\x3d\x96\x59\x52\x65\x50\x25\x0f\x79\x06\x25\x0a\x54\x13\xca\x82\x7f\xf5\xa8\x09\x16\x0c\x40\x49\x70\x68\x15\xef\xae\xfd\xc3\x0c\xe8\x0b\x95\xd1\x8d\xff\x9e\xb8\x15\xef\xdc\x2e\xee\xa2\x68\x09\xeb\x4d\x05\xeb\x3a\xcf\x0f\xaa\xc1\x1a\x81\xce\xa1\x6c\x38\x01\xa9\x1e\x1d\xf1\x7e\x98\x4b\x78\xab\x74\xdb\x90\xf9\x40\x7d\xb9\x91\xaf\xa3\xd1\x5d\xe6\x45\xf9\xfc\x9a\x94\x5b\x78\xc9\xb6\xb0\x50\x72\xc9\x96\x31\x9b\x3b\x7a\xf3\x36\x2a\xcb\xb5\xe3\xe2\xb0\xf6\xa9\xa3\x60\xc4\xed\x4d\xb0\x81\x19\xad\xc0\x5f\x1c\xdd\x07\x78\xdc\xf5\x8a\xe8\x3f\xa7\x6e\x14\x02\x51\x86\x92\x34\x7b\x4a\xea\xfc\x4f\xd6\xa1\x6c\xd1\xc2\x4e\xf9\x5c\xe8\x84\xa2\x9e\xb0\xf3\xe1\x37\x7b\x69\x81\x70\x4f\xb3\x11\x16\xdd\xd8\xd1\x0f\x9b\x45\x15\x76\x39\x1b\xd7\xf1\x3d\xa4\xe6\x53\xb3\xb4\xdc\x14\xe7\xef\x21\x3e\x66\x41\xb2\x31\xfb\x0e\x00\x00\xff\xff\x95\xdd\x98\x31\x59\x02\x00\x00")
+var _mysqlMysql_02_idempotency_keySql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x91\xc1\x4e\xf3\x30\x10\x84\xef\x7e\x8a\x3d\x26\xfa\xff\x4a\x14\x51\x84\x54\xf5\x90\x36\x06\xa2\xa6\x6e\x09\xf1\xa1\xa7\xd8\xc4\x0b\x8d\x20\x76\x88\xb7\x40\xdf\x1e\xb5\x48\x0d\x34\x70\x1d\x7d\xb3\x3b\x9a\x19\x0c\xe0\x5f\x5d\x3d\xb5\x9a\x10\x64\xc3\x66\x19\x8f\x72\x0e\x79\x34\x4d\x39\xa8\xc4\x60\xdd\x38\x42\x5b\xee\xe6\xb8\x53\x10\x30\x00\x55\x19\x05\x95\xa5\x60\x38\x0c\x41\x2c\x73\x10\x32\x4d\x21\x92\xf9\xb2\x48\xc4\x2c\xe3\x0b\x2e\xf2\xff\x5f\xdc\xd1\x5c\x3c\xef\xdd\x6f\xba\x2d\x37\xba\x0d\xce\x47\xa3\xce\x79\x40\x5b\x7c\xdd\xa2\xa7\x62\xa3\xfd\xa6\xe3\x2e\x2f\x4e\x30\x4f\x9a\xb6\xbe\x03\x86\x67\xbd\x3b\xbe\x71\xd6\x63\x51\x3a\x83\x5d\xca\x98\x5f\x47\x32\xfd\x8d\x7b\x70\x66\xa7\x80\xf0\x83\xfa\x50\xd9\xa2\x26\x34\x85\x26\x05\x46\x13\x52\x55\xe3\xcf\x77\xa5\xab\x9b\x17\xec\x31\xa7\x97\x56\x59\xb2\x88\xb2\x35\xcc\xf9\x1a\x82\x7d\x7d\xe1\x5e\x95\x22\xb9\x93\xfc\x20\xf6\xab\x0a\x7a\x52\xc8\x42\xe0\xe2\x26\x11\x7c\x92\x58\xeb\xe2\xe9\x31\xf0\xec\x36\xca\xee\x79\x3e\xd9\xd2\xe3\xd5\x98\xb1\xef\x83\xc6\xee\xdd\xb2\x38\x5b\xae\xfe\x18\x74\xcc\x3e\x07\x00\x49\xc5\xa3\x83\xff\x01\x00\x00")
+
+func mysqlMysql_02_idempotency_keySqlBytes() ([]byte, error) {
+	return bindataRead(
+		_mysqlMysql_02_idempotency_keySql,
+		"mysql/mysql_02_idempotency_key.sql",
+	)
+}
+
+func mysqlMysql_02_idempotency_keySql() (*asset, error) {
+	bytes, err := mysqlMysql_02_idempotency_keySqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mysql/mysql_02_idempotency_key.sql", size: 511, mode: os.FileMode(420), modTime: time.Unix(1792201031, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _mysqlMysql_03_sent_transaction_horizon_urlSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd2\xd5\x55\xd0\xce\xcd\x4c\x2f\x4a\x2c\x49\x55\x08\x2d\xe0\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\x08\x4e\xcd\x2b\x09\x29\x4a\xcc\x2b\x4e\x4c\x2e\xc9\xcc\xcf\x4b\x50\x70\x74\x71\x51\x48\xc8\xc8\x2f\xca\xac\xca\xcf\x8b\x2f\x2d\xca\x49\x50\x28\x4b\x2c\x4a\xce\x48\x2c\xd2\x30\x32\x35\xd5\x54\x70\x71\x75\x73\x0c\xf5\x09\x51\xf0\x0b\xf5\xf1\xb1\xe6\xe2\x42\x36\xda\x25\xbf\x3c\x8f\x80\xe1\x2e\x41\xfe\x01\x0a\xce\xfe\x3e\xa1\xbe\x7e\xa8\x96\x58\x73\x01\x06\x00\x09\x05\xa2\x33\xa5\x00\x00\x00")
+
+func mysqlMysql_03_sent_transaction_horizon_urlSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_mysqlMysql_03_sent_transaction_horizon_urlSql,
+		"mysql/mysql_03_sent_transaction_horizon_url.sql",
+	)
+}
+
+func mysqlMysql_03_sent_transaction_horizon_urlSql() (*asset, error) {
+	bytes, err := mysqlMysql_03_sent_transaction_horizon_urlSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mysql/mysql_03_sent_transaction_horizon_url.sql", size: 165, mode: os.FileMode(420), modTime: time.Unix(1792202167, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _mysqlMysql_04_hook_deliverySql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x92\x41\x6f\x82\x40\x10\x85\xef\xfb\x2b\xe6\x08\xa9\x26\xb5\x89\x49\x13\xe3\x01\x65\x5b\x49\x11\x0d\x85\x83\x27\x76\x22\xd3\xba\x11\x59\xb2\x8c\x56\xff\x7d\x83\xb5\x55\xb1\xf1\xb8\xfb\xbe\xbc\x7d\x6f\x76\xba\x5d\x78\xd8\xe8\x4f\x8b\x4c\x90\x56\x62\x1c\x4b\x2f\x91\x90\x78\xa3\x50\x82\x9a\x18\xb3\xf6\xa9\xd0\x3b\xb2\x07\x05\x8e\x00\x50\x3a\x57\xa0\x4b\x76\x7a\x3d\x17\xa2\x59\x02\x51\x1a\x86\xe0\xa5\xc9\x2c\x0b\xa2\x71\x2c\xa7\x32\x4a\x3a\x0d\xb7\x32\x66\xad\x60\x87\x76\xb9\x42\xeb\x3c\x3d\x9e\xe9\xa3\xbc\xb5\xc5\x85\xda\xef\xb7\xe4\x0a\x0f\x85\xc1\x5c\x01\xd3\x9e\xaf\x25\x53\x91\x45\xd6\xa6\xcc\x74\x7e\xcf\xa2\x66\xe4\x6d\x7d\x26\x7a\xed\x08\xc8\x4c\x9b\x8a\xeb\xdb\x3e\xc7\x08\x25\xed\x39\x3b\x31\x19\xb2\x82\x1c\x99\x58\x6f\xe8\x1a\x2b\xb0\xe6\x8c\xac\x35\xf6\x14\xd6\x97\x2f\x5e\x1a\x5e\x10\x4b\x4b\xc8\x94\xdf\xf1\xc8\x7f\x46\xdc\x66\xda\x4e\xf3\x38\x98\x7a\xf1\x02\xde\xe4\x02\x9c\xe6\x23\xdc\xe6\xb6\x39\x9d\xca\x66\x37\x99\x9d\xdf\x31\x74\x6e\x0b\xb9\xc2\x05\x19\xbd\x06\x91\x1c\x06\x65\x69\xfc\xd1\xdf\x83\xe3\x89\x17\xbf\xcb\x64\xb8\xe5\x8f\xe7\x81\x10\x97\x2b\xe2\x9b\xaf\x52\xf8\xf1\x6c\xfe\xef\x8a\x0c\xc4\xf7\x00\x80\xf7\x48\xf2\x4f\x02\x00\x00")
+
+func mysqlMysql_04_hook_deliverySqlBytes() ([]byte, error) {
+	return bindataRead(
+		_mysqlMysql_04_hook_deliverySql,
+		"mysql/mysql_04_hook_delivery.sql",
+	)
+}
+
+func mysqlMysql_04_hook_deliverySql() (*asset, error) {
+	bytes, err := mysqlMysql_04_hook_deliverySqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mysql/mysql_04_hook_delivery.sql", size: 591, mode: os.FileMode(420), modTime: time.Unix(1792202457, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _mysqlMysql_05_received_payment_operation_idSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\x90\xc1\x4a\xc4\x30\x14\x45\xf7\xf9\x8a\xbb\x54\xb4\xfe\xc0\xe8\xa2\x92\x08\xd5\xda\x8e\xa5\x05\x5d\xd9\x38\xb9\x8e\xc1\x69\x52\xd2\xd8\xea\xdf\xcb\x58\x06\xb4\x0c\xb8\xbc\x39\x87\x13\x78\x49\x82\xb3\xce\x6e\x83\x8e\x44\xd3\x8b\x24\x41\xc5\xce\x8f\x84\xf9\xe8\x77\x76\xa3\x23\x07\x0c\x7a\xa4\xc1\x0b\x5f\x7d\x20\xe2\x1b\x61\x9d\xe1\x27\x26\x3d\x40\x1b\x43\x73\x8e\x77\xb2\xb7\x6e\xfb\x03\x1d\x27\x0e\x11\xc1\x4f\x42\xaa\x5c\xd5\x0a\x7e\x67\x18\x70\x53\x95\xf7\x68\x2b\x6e\x68\x47\x9a\xb5\xfe\xea\xe8\x62\x3b\x43\x01\xdc\x96\x59\x71\x04\xef\x6b\x01\x65\x31\x7b\x17\xad\xef\x19\x74\xb4\xde\x3d\x5b\xd3\xe2\x6a\xe6\xcb\xe7\xb4\x90\x07\x7f\x3f\x2f\x0f\x96\x35\xed\x4a\x88\x34\xaf\x55\x85\x3a\xbd\xce\xd5\x91\x0f\x53\x29\xd1\x14\xd9\x43\xa3\x70\xa7\x9e\xb0\x28\x9f\xfc\xdd\xa7\x2b\x21\x7e\x5f\x50\xfa\xc9\xfd\xd3\x97\x55\xb9\x46\x56\x48\xf5\xb8\x68\xaf\xc4\xf7\x00\xd5\xa0\x84\x10\x8c\x01\x00\x00")
+
+func mysqlMysql_05_received_payment_operation_idSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_mysqlMysql_05_received_payment_operation_idSql,
+		"mysql/mysql_05_received_payment_operation_id.sql",
+	)
+}
+
+func mysqlMysql_05_received_payment_operation_idSql() (*asset, error) {
+	bytes, err := mysqlMysql_05_received_payment_operation_idSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mysql/mysql_05_received_payment_operation_id.sql", size: 396, mode: os.FileMode(420), modTime: time.Unix(1792202826, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _mysqlMysql_06_received_payment_detailsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x92\x4f\x6b\x83\x40\x10\xc5\xef\x7e\x8a\xb9\x25\xa1\x09\x24\xa1\xc9\x25\x27\xcb\x5a\x28\x15\x13\x44\xa1\x3d\xe9\xb0\xd9\x56\x29\xbb\x2b\xbb\x63\x4a\xbe\x7d\x29\xd5\xae\x7f\xa0\xa6\x27\xf1\xcd\xfb\x39\x33\xce\x5b\xad\xe0\x4e\x96\xef\x06\x49\x40\x5a\x79\x7e\x98\x04\x31\x24\xfe\x43\x18\x40\x1e\x0b\x2e\xca\x8b\x38\x9f\xf0\x2a\x85\xa2\xdc\x03\xf0\x19\x83\x9c\x0c\x2a\x8b\x9c\x4a\xad\xb2\x02\x6d\x91\xc3\x05\x0d\x2f\xd0\xcc\xf7\xf7\x0b\x88\x8e\x09\x44\x69\x18\x02\x0b\x1e\xfd\x34\x4c\x60\x36\x5b\xb6\xe4\x9b\xd1\x32\x43\xce\x75\xad\xc8\x51\xbb\xfd\xdf\x14\xca\xbe\x7f\xbb\xdb\x4d\x00\xd6\x0a\xca\xb8\x3e\x0b\x07\x6d\xb6\xb7\x30\xa5\xb5\xb5\x30\xb7\x8f\x26\x85\xd4\x19\x5d\xab\x6e\xa3\xf5\x34\xf2\x8f\x5d\x0a\xad\x3f\x32\x4b\x48\xb5\x6d\x36\x2a\x15\xcd\x37\x9b\xc5\xaf\xf9\xbb\x51\xdf\x8e\x44\x42\x56\x64\x9d\x77\xd4\x61\xdd\x12\xcf\xc1\x6b\x3b\xd3\xfc\xe7\xb9\x38\x78\x5e\x37\x16\x4c\x7f\xaa\xc9\x60\xb0\xf8\x78\x82\xa7\x88\x05\x2f\xcd\xd7\x96\xad\x38\x8e\x8b\x2b\xf5\xf2\xe0\x64\x94\x43\xc1\x1d\x74\x28\x36\x17\x73\xb2\x3b\x49\x5f\xeb\xbc\x8e\xfe\xe9\xa0\x84\x44\x42\x56\x64\xf3\x83\xf7\x35\x00\x2f\x8d\x93\x48\x20\x03\x00\x00")
+
+func mysqlMysql_06_received_payment_detailsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_mysqlMysql_06_received_payment_detailsSql,
+		"mysql/mysql_06_received_payment_details.sql",
+	)
+}
+
+func mysqlMysql_06_received_payment_detailsSql() (*asset, error) {
+	bytes, err := mysqlMysql_06_received_payment_detailsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mysql/mysql_06_received_payment_details.sql", size: 800, mode: os.FileMode(420), modTime: time.Unix(1792202922, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _mysqlMysql_07_received_payment_account_idSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd2\xd5\x55\xd0\xce\xcd\x4c\x2f\x4a\x2c\x49\x55\x08\x2d\xe0\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\x08\x4a\x4d\x4e\xcd\x2c\x4b\x4d\x09\x48\xac\xcc\x4d\xcd\x2b\x49\xe0\x52\x50\x70\x74\x71\x51\x48\x48\x4c\x4e\xce\x2f\xcd\x2b\x89\xcf\x4c\x49\x50\x28\x4b\x2c\x4a\xce\x48\x2c\xd2\x30\x35\xd3\x54\xf0\xf3\x0f\x51\xf0\x0b\xf5\xf1\x51\x70\x71\x75\x73\x0c\xf5\x09\x51\x50\x57\xd7\x81\xea\xf1\x76\x8d\x44\xd5\xa7\x81\xcc\xd3\xb4\xe6\xe2\x42\x76\x88\x4b\x7e\x79\x1e\x41\xa7\xb8\x04\xf9\x07\x28\x78\xfa\xb9\xb8\x46\xa0\x98\xac\x03\x93\x42\x16\xb4\xe6\x02\x0c\x00\x1f\x30\x37\xd1\xe8\x00\x00\x00")
+
+func mysqlMysql_07_received_payment_account_idSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_mysqlMysql_07_received_payment_account_idSql,
+		"mysql/mysql_07_received_payment_account_id.sql",
+	)
+}
+
+func mysqlMysql_07_received_payment_account_idSql() (*asset, error) {
+	bytes, err := mysqlMysql_07_received_payment_account_idSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mysql/mysql_07_received_payment_account_id.sql", size: 232, mode: os.FileMode(420), modTime: time.Unix(1792203083, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _mysqlMysql_08_received_payment_refundSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\xce\xb1\x0a\xc2\x30\x10\xc6\xf1\x3d\x4f\x71\xa3\x62\x0b\x0a\xe2\xd2\x29\x92\x3a\x05\x2d\xa5\x9d\x9b\xa3\x3d\x4d\x86\xa6\x92\x5c\x2b\xbe\xbd\xa3\x1a\x07\xd7\x8f\xef\x0f\xbf\x3c\x87\xcd\xe8\x6e\x01\x99\xa0\xbd\x0b\xa9\x9b\xb2\x86\x46\x1e\x75\x09\xa6\xa6\x9e\xdc\x42\x43\x85\xcf\x91\x3c\x1b\x01\x20\x95\x02\x13\xe8\x3a\xfb\xa1\x8b\x8c\x3c\x47\x03\x0b\x86\xde\x62\x58\xed\xb6\x6b\x50\xe5\x49\xb6\xba\x81\x73\xab\x75\x96\xfc\x39\xa0\x8f\xd8\xb3\x9b\x7c\x67\x31\xda\x77\x79\xd8\x7f\x97\x85\x10\x9f\x2e\x35\x3d\xfc\x5f\x99\xaa\x2f\x55\x4a\xcb\xd2\xfd\x87\x50\x88\xd7\x00\x5a\x11\x00\x1e\x01\x01\x00\x00")
+
+func mysqlMysql_08_received_payment_refundSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_mysqlMysql_08_received_payment_refundSql,
+		"mysql/mysql_08_received_payment_refund.sql",
+	)
+}
+
+func mysqlMysql_08_received_payment_refundSql() (*asset, error) {
+	bytes, err := mysqlMysql_08_received_payment_refundSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mysql/mysql_08_received_payment_refund.sql", size: 257, mode: os.FileMode(420), modTime: time.Unix(1792203334, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _mysqlMysql_09_stream_cursorSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\xd0\x41\x4b\xc3\x30\x14\x07\xf0\x7b\x3e\xc5\x3b\xa6\xe8\x60\x3d\x08\xc2\xd8\x21\x6b\x9f\x1a\xec\xd2\x99\x25\x87\x9d\x9a\x60\x63\x0d\xd2\xb4\xc4\x54\xbf\xbe\x74\x0a\x55\xd9\x29\xe1\xbd\x1f\x8f\x3f\xff\xd5\x0a\xae\x7a\xdf\x45\x9b\x1c\xe8\x91\x14\x12\x99\x42\x50\x6c\x57\x21\x98\x63\x8a\xce\xf6\xc5\x14\xdf\x87\x68\x80\x12\x00\xe3\x5b\x03\x3e\x24\x9a\xe7\x19\x88\x5a\x81\xd0\x55\x05\x4c\xab\xba\xe1\xa2\x90\xb8\x47\xa1\xae\x67\x17\x6c\xef\x0c\x7c\xd8\xf8\xfc\x6a\x23\xcd\xd7\xeb\x85\x9f\xf7\xa3\xed\x7c\xe8\x9a\x34\xbc\xb9\xb0\xb8\x9b\xff\x6c\x1a\x5b\x9b\x5c\xdb\xd8\x64\x60\xfe\x25\xdf\xbb\x3f\xe2\x20\xf9\x9e\xc9\x13\x3c\xe2\x09\xe8\x9c\x2e\x9b\xcf\x6b\xc1\x9f\x34\x9e\x87\x3f\x49\xe8\xf7\x9b\x91\x0c\x50\xdc\x73\x81\x5b\x1e\xc2\x50\xee\xa0\xc4\x3b\xa6\x2b\x05\xc5\x03\x93\x47\x54\xdb\x29\xbd\xdc\x6e\x08\xf9\xdd\x4b\x39\x7c\x06\x52\xca\xfa\x70\xb1\x97\x0d\xf9\x1a\x00\x52\x22\xf5\x37\x44\x01\x00\x00")
+
+func mysqlMysql_09_stream_cursorSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_mysqlMysql_09_stream_cursorSql,
+		"mysql/mysql_09_stream_cursor.sql",
+	)
+}
+
+func mysqlMysql_09_stream_cursorSql() (*asset, error) {
+	bytes, err := mysqlMysql_09_stream_cursorSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mysql/mysql_09_stream_cursor.sql", size: 324, mode: os.FileMode(420), modTime: time.Unix(1792203572, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _mysqlMysql_10_stream_cursor_seedSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\x91\x41\x8f\x9b\x30\x14\x84\xef\xfc\x8a\xe9\x09\x50\x21\x3f\xa0\x55\x0f\x14\x68\x82\x54\x20\xc2\x46\x6d\x4f\xd8\x0d\x2f\x04\x6d\x30\xc8\x98\x44\xf9\xf7\x2b\x08\x2b\x85\xd3\xde\xfc\xf4\xac\x99\x6f\xe6\xf9\x3e\xbe\x76\x6d\xa3\xa5\x21\x94\x83\xe5\xfb\x60\x46\x6a\x83\x41\x3e\x3a\x52\x06\xa3\xd1\x24\xbb\x11\x67\xdd\x77\x30\x17\x82\xa2\x3b\x8d\x2f\x7b\x79\xa3\x1a\xe7\x5e\x83\x6e\xa4\x1f\xd0\x74\xa2\xf6\xd6\xaa\x66\x96\x92\xa7\x53\x3f\x29\xb3\xc3\xf1\xa9\x36\xae\xdf\xef\xad\xb9\xf4\x93\xf9\xd8\x23\x89\x20\x35\xe1\x22\x55\x7d\xa5\x1a\xff\x1f\x8b\xd3\xb5\x1d\x0d\x29\xd2\x3b\x2b\xc9\x58\x5c\x70\x24\x19\xcf\x21\xd8\x42\x14\x4e\x7a\xec\xb5\x80\x23\x94\xec\x48\x78\x10\x83\x6c\x5a\xd5\x54\xa6\x7f\x23\x35\xcf\xd3\x50\x4b\x43\x75\x25\x8d\x70\x2d\x80\xc5\xbf\xe3\x90\x23\xcc\xb3\x30\xe0\x8e\xbd\xf2\x8f\xdf\x6c\x0f\x62\xe5\xa8\xda\x5a\xb8\x1e\xc2\x80\x71\x27\x0d\xfe\x3a\xcb\x63\xab\x8b\x80\xa1\xcc\x58\xb2\xcf\xe2\xc8\x75\xe7\x29\x3c\x04\x85\xeb\xa1\xe4\x61\xc5\x93\x34\x66\x3c\x48\x8f\xce\x6c\xf8\xab\xc8\x53\x88\x62\xe9\x83\xea\xb5\x01\x61\x01\x7f\x0e\x71\x11\x6f\x4c\xf1\xe5\x07\x6c\x1b\x41\x16\x7d\xce\x87\x2c\x9f\x9b\x80\xb3\x06\x7a\xe6\x5f\xdd\x36\xdd\xcc\x10\xfb\x22\x2f\x8f\xf8\xf9\x6f\xa3\xf1\xdd\xb2\x5e\xcf\x1e\xf5\x77\x65\xbd\x0f\x00\xe5\xd1\xf8\x4c\x08\x02\x00\x00")
+
+func mysqlMysql_10_stream_cursor_seedSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_mysqlMysql_10_stream_cursor_seedSql,
+		"mysql/mysql_10_stream_cursor_seed.sql",
+	)
+}
+
+func mysqlMysql_10_stream_cursor_seedSql() (*asset, error) {
+	bytes, err := mysqlMysql_10_stream_cursor_seedSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mysql/mysql_10_stream_cursor_seed.sql", size: 520, mode: os.FileMode(420), modTime: time.Unix(1792203743, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _mysqlMysql_11_trustline_authorizationSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x93\x41\x6f\xaa\x40\x14\x85\xf7\xfc\x8a\xbb\x13\xf2\x34\x79\x9a\xa7\x79\x89\x71\x81\x32\xef\x95\x14\xd1\x52\x58\xb8\x82\x09\x4c\xcb\x24\x38\x63\x66\x2e\x35\xe9\xaf\x6f\x98\x85\x8c\xa4\xa6\xb6\xcb\x0b\xdf\x99\x7b\x72\x4e\xee\x64\x02\xbf\x8e\xfc\x55\x51\x64\x90\x9d\x9c\x4d\x42\xfc\x94\x40\xea\xaf\x23\x02\x45\xaa\x5a\x8d\x0d\x17\xcc\x6f\xb1\x96\x8a\xbf\x53\xe4\x52\x14\xe0\x3a\x00\x05\xaf\x0a\xe0\x02\xdd\xe9\xd4\x83\x78\x97\x42\x9c\x45\x11\xf8\x59\xba\xcb\xc3\x78\x93\x90\x2d\x89\xd3\x71\xc7\xc9\x13\x53\x46\x97\x77\x8a\x37\xaa\xca\x9a\x2a\x77\x36\x9f\xf7\x32\xc3\xd1\xb2\x94\xad\xc0\x2b\x6a\xbe\x18\x42\x5a\x33\xcc\x4b\x59\xb1\x1e\x9a\xce\x06\x90\x46\x8a\xad\xb6\x80\xdf\x03\x40\x31\xaa\xa5\xe8\x01\x63\x26\x20\xff\xfc\x2c\xb2\x28\x54\x54\x68\x5a\x1a\xeb\x35\xd5\x75\xcf\x2f\xfe\x7c\x82\x97\x8a\x51\x64\x55\x4e\xb1\x80\x8a\x22\x43\x7e\x64\x57\x6b\xf7\x49\xb8\xf5\x93\x03\x3c\x92\x03\xb8\x5d\x7c\x5e\xa7\xcb\xe2\xf0\x29\x23\xe6\xe3\x20\x2a\xf7\x7a\x36\xb4\xc1\xec\xa4\x5c\x7b\x1a\x9b\x56\x3c\xc7\x03\x12\xff\x0f\x63\xb2\x0a\x85\x90\xc1\xfa\xe2\x75\xf3\xe0\x27\xcf\x24\x5d\xb5\xf8\xf2\x77\xe9\xdc\x2c\xbb\x69\xe4\xb9\xe1\x1a\xbf\x59\xb4\x6d\xeb\xc7\x05\x5e\xbc\x8e\x46\xf7\x65\xd6\x6f\xcd\xed\xb7\x87\xb9\x58\xff\xee\xce\xc7\xbe\x8d\x40\x9e\x85\x13\x24\xbb\xfd\x17\xb7\xb1\xbc\x01\x35\x8d\x3c\x37\x5c\x63\xb1\x74\x3e\x06\x00\x3b\x39\x30\x8c\x73\x03\x00\x00")
+
+func mysqlMysql_11_trustline_authorizationSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_mysqlMysql_11_trustline_authorizationSql,
+		"mysql/mysql_11_trustline_authorization.sql",
+	)
+}
+
+func mysqlMysql_11_trustline_authorizationSql() (*asset, error) {
+	bytes, err := mysqlMysql_11_trustline_authorizationSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mysql/mysql_11_trustline_authorization.sql", size: 883, mode: os.FileMode(420), modTime: time.Unix(1792205389, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _mysqlMysql_12_hook_delivery_operation_idSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd2\xd5\x55\xd0\xce\xcd\x4c\x2f\x4a\x2c\x49\x55\x08\x2d\xe0\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\xf0\xc8\xcf\xcf\x76\x49\xcd\xc9\x2c\x4b\x2d\xaa\x4c\xe0\x52\x50\x70\x74\x71\x51\xf0\x76\x8d\x54\x48\xc8\x2f\x48\x2d\x4a\x2c\xc9\xcc\xcf\x8b\xcf\x4c\x49\x50\xd0\x40\xe5\x6b\x5a\x73\x71\x21\x9b\xea\x92\x5f\x9e\x87\xdf\x5c\x97\x20\xff\x00\x05\x4f\x3f\x17\xd7\x08\x34\xa3\xad\xb9\x00\x03\x00\xe1\x25\x57\x70\x9f\x00\x00\x00")
+
+func mysqlMysql_12_hook_delivery_operation_idSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_mysqlMysql_12_hook_delivery_operation_idSql,
+		"mysql/mysql_12_hook_delivery_operation_id.sql",
+	)
+}
+
+func mysqlMysql_12_hook_delivery_operation_idSql() (*asset, error) {
+	bytes, err := mysqlMysql_12_hook_delivery_operation_idSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mysql/mysql_12_hook_delivery_operation_id.sql", size: 159, mode: os.FileMode(420), modTime: time.Unix(1792207291, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _mysqlMysql_13_sent_transaction_idempotency_keySql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd2\xd5\x55\xd0\xce\xcd\x4c\x2f\x4a\x2c\x49\x55\x08\x2d\xe0\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\x08\x4e\xcd\x2b\x09\x29\x4a\xcc\x2b\x4e\x4c\x2e\xc9\xcc\xcf\x4b\xe0\x52\x50\x70\x74\x71\x51\x48\xc8\x4c\x49\xcd\x2d\xc8\x2f\x49\xcd\x4b\xae\x8c\xcf\x4e\xad\x4c\x50\x28\x4b\x2c\x4a\xce\x48\x2c\xd2\x30\x32\x35\xd5\x54\x70\x71\x75\x73\x0c\xf5\x09\x51\xf0\x0b\xf5\xf1\xd1\x81\x6a\xf1\x76\x8d\xc4\xa2\x4d\x03\x43\x48\xd3\x9a\x8b\x0b\xd9\x45\x2e\xf9\xe5\x79\x04\xdd\xe4\x12\xe4\x1f\xa0\xe0\xe9\xe7\xe2\x1a\x81\x69\x87\x0e\x4c\x1e\x43\xc6\x9a\x0b\x30\x00\x6f\x10\x05\xb5\xfb\x00\x00\x00")
+
+func mysqlMysql_13_sent_transaction_idempotency_keySqlBytes() ([]byte, error) {
+	return bindataRead(
+		_mysqlMysql_13_sent_transaction_idempotency_keySql,
+		"mysql/mysql_13_sent_transaction_idempotency_key.sql",
+	)
+}
+
+func mysqlMysql_13_sent_transaction_idempotency_keySql() (*asset, error) {
+	bytes, err := mysqlMysql_13_sent_transaction_idempotency_keySqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mysql/mysql_13_sent_transaction_idempotency_key.sql", size: 251, mode: os.FileMode(420), modTime: time.Unix(1792207500, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _mysqlMysql_14_pending_transaction_idSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x91\xcd\x8a\x83\x30\x14\x85\xf7\x79\x8a\xbb\x54\x46\x17\xae\x5d\x65\x48\x06\x86\x09\x8e\x48\x84\x76\x65\x82\xa6\x6d\xa0\xc6\x12\x63\x4b\xfb\xf4\x45\xe8\x8f\xb5\x4a\xdb\x6d\x3e\xce\xc9\xc7\xb9\x61\x08\x5f\xb5\x5e\x5b\xe9\x14\xe4\x3b\x84\x19\xa7\x19\x70\xfc\xcd\x28\x88\x4c\x95\x4a\xef\x55\x95\xca\x63\xad\x8c\x13\x08\x00\x13\x02\xc2\xaa\x55\x67\xaa\xc2\x59\x69\x5a\x59\x3a\xdd\x98\x42\x57\x02\xb4\x71\x5e\x14\xf9\x40\xe8\x0f\xce\x19\x87\x24\x67\x2c\xb8\x64\xfe\xe8\x72\x36\xe7\xcd\x00\x3f\x46\x8f\x3e\xdc\x76\xad\xdb\x6a\xa3\x70\xe7\x36\x8d\xd5\x27\xd9\x77\xdc\xb4\x3e\xf6\x19\x07\xbc\xf1\x4b\x6f\x30\x1c\x88\x34\x07\xf3\xae\x12\xc9\xfe\x53\xf8\x4d\x08\x5d\x3c\x7d\x14\x5c\xf1\x18\xc4\xe8\xe5\x01\x86\xb5\xd3\xb3\xdd\xdb\xa7\x79\x8c\xce\x03\x00\x26\x7b\x9c\xc2\xf4\x01\x00\x00")
+
+func mysqlMysql_14_pending_transaction_idSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_mysqlMysql_14_pending_transaction_idSql,
+		"mysql/mysql_14_pending_transaction_id.sql",
+	)
+}
+
+func mysqlMysql_14_pending_transaction_idSql() (*asset, error) {
+	bytes, err := mysqlMysql_14_pending_transaction_idSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mysql/mysql_14_pending_transaction_id.sql", size: 500, mode: os.FileMode(420), modTime: time.Unix(1792207896, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _mysqlMysql_15_sent_transaction_retry_ofSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd2\xd5\x55\xd0\xce\xcd\x4c\x2f\x4a\x2c\x49\x55\x08\x2d\xe0\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\x08\x4e\xcd\x2b\x09\x29\x4a\xcc\x2b\x4e\x4c\x2e\xc9\xcc\xcf\x4b\xe0\x52\x50\x70\x74\x71\x51\x48\x28\x4a\x2d\x29\xaa\x8c\xcf\x4f\x4b\x50\xc8\xcc\x2b\xd1\x30\x34\xd4\x54\x70\x71\x75\x73\x0c\xf5\x09\x51\xf0\x0b\xf5\xf1\xd1\x81\x2a\xf3\x76\x8d\x44\x56\xaa\x81\x60\x6b\x5a\x73\x71\x21\xdb\xeb\x92\x5f\x9e\x47\xd0\x66\x97\x20\xff\x00\x05\x4f\x3f\x17\xd7\x08\x24\x53\x75\x60\x12\x08\x21\x6b\x2e\xc0\x00\x4c\x7e\xfa\xc2\xd3\x00\x00\x00")
+
+func mysqlMysql_15_sent_transaction_retry_ofSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_mysqlMysql_15_sent_transaction_retry_ofSql,
+		"mysql/mysql_15_sent_transaction_retry_of.sql",
+	)
+}
+
+func mysqlMysql_15_sent_transaction_retry_ofSql() (*asset, error) {
+	bytes, err := mysqlMysql_15_sent_transaction_retry_ofSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mysql/mysql_15_sent_transaction_retry_of.sql", size: 211, mode: os.FileMode(420), modTime: time.Unix(1792208074, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgresPostgres_01_initSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x92\x41\x4f\xc2\x40\x10\x85\xef\xfb\x2b\xe6\x08\x51\x12\x35\xc1\x0b\xa7\x2a\x35\x31\x56\x20\xb5\x1c\x38\x35\xc3\xee\xa4\x4e\x6c\x77\x9b\xdd\x69\xc5\x7f\x6f\x20\x51\xe9\x02\x9e\xbf\x97\x99\xf7\x66\xde\x64\x02\x57\x0d\x57\x1e\x85\x60\xdd\xaa\xc7\x3c\x4d\x8a\x14\x8a\xe4\x21\x4b\x21\x27\x4d\xdc\x93\x59\xe1\x57\x43\x56\x60\xa4\x00\xd8\x40\x20\xcf\x58\x5f\x2b\x00\xd7\x92\x47\x61\x67\x4b\x36\xd0\xa3\xd7\xef\xe8\x47\x77\xd3\xe9\x18\x16\xcb\x02\x16\xeb\x2c\xdb\xab\x5a\xef\x34\x85\x40\xa6\x44\x01\xe1\x86\x82\x60\xd3\x0e\x25\x58\xb1\xad\x4a\x71\x1f\x64\x2f\x0f\x0a\x82\xd2\x85\xcb\x7c\x95\x3f\xbf\x26\xf9\x06\x5e\xd2\x0d\x8c\xd8\x8c\xd5\x78\xa6\x86\x89\xde\xc8\x4a\xe1\xd1\x06\xd4\x7b\xdb\xa7\x89\xa2\x15\xb7\x37\x91\x03\xd7\x79\x4d\xbf\x78\x7a\x1f\xe1\x6e\xdb\xb0\xc8\x7f\x49\x43\xa7\x35\x91\x89\x25\xf3\xf4\x29\x59\x67\x7f\xb2\x9a\x4c\x45\x1e\xb6\x5c\xb1\x95\x13\x4a\xb6\xa7\xda\xb5\x54\xee\x8c\x07\xa1\x9d\x0c\x56\x78\x0a\x5d\x2d\x07\xf6\x63\xf4\xf0\x93\x78\xca\xd9\x73\x1d\xf7\x61\xee\x3e\xad\x9a\xe7\xcb\xd5\xf9\x3e\xcc\x8e\x59\x74\xd9\x99\xfa\x1e\x00\x95\xdd\x98\x31\x59\x02\x00\x00")
 
 func postgresPostgres_01_initSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -104,7 +418,407 @@ func postgresPostgres_01_initSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "postgres/postgres_01_init.sql", size: 601, mode: os.FileMode(420), modTime: time.Unix(1453986637, 0)}
+	info := bindataFileInfo{name: "postgres/postgres_01_init.sql", size: 601, mode: os.FileMode(420), modTime: time.Unix(1454086036, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgresPostgres_02_idempotency_keySql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\xd0\x31\x4f\xc3\x30\x10\x05\xe0\xdd\xbf\xe2\xc6\x44\x50\x09\x10\x65\xe9\x14\x88\x91\xa2\x86\xb4\x44\xf1\xd0\x29\x32\xf6\xa9\xb1\xa8\x63\x63\x5f\x81\xfc\x7b\x54\x86\xa8\x4d\x33\xda\xef\xd3\x3b\xe9\x2d\x16\x70\x63\xcd\x3e\x48\x42\x10\x9e\xbd\xd4\x3c\x6b\x38\x34\xd9\x73\xc9\xa1\xd0\x68\xbd\x23\xec\xd5\xb0\xc6\x01\x12\x06\x60\x34\x44\x0c\x46\x1e\x6e\xff\x1f\x63\xde\x7e\xe2\x00\xdf\x32\xa8\x4e\x86\xe4\x61\xb9\x4c\xa1\xda\x34\x50\x89\xb2\x3c\xc1\x80\x5f\x47\x8c\xd4\x76\x32\x76\xa3\x7a\x7a\xbc\x44\x91\x24\x1d\xe3\x18\xdf\xdf\x4d\x3b\xa2\x77\x7d\xc4\x56\x39\x8d\x60\x7a\xc2\x3d\x06\xc8\xf9\x6b\x26\xca\x19\xf5\xe1\xf4\x00\x84\xbf\x74\x45\x54\x40\x49\xa8\x5b\x49\x40\xc6\x62\x24\x69\xfd\xc5\x25\xe5\xac\x3f\xe0\x15\x99\xf6\x6c\xeb\xe2\x2d\xab\x77\xb0\xe6\x3b\x48\x8c\x4e\x4f\x7f\xa2\x2a\xde\x05\x87\x64\xb2\x4c\xca\xd2\x15\x63\xe7\x53\xe7\xee\xa7\x67\x79\xbd\xd9\xce\x4e\xbd\x62\x7f\x03\x00\xe3\xc8\xc8\xbf\x97\x01\x00\x00")
+
+func postgresPostgres_02_idempotency_keySqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgresPostgres_02_idempotency_keySql,
+		"postgres/postgres_02_idempotency_key.sql",
+	)
+}
+
+func postgresPostgres_02_idempotency_keySql() (*asset, error) {
+	bytes, err := postgresPostgres_02_idempotency_keySqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/postgres_02_idempotency_key.sql", size: 407, mode: os.FileMode(420), modTime: time.Unix(1792201031, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgresPostgres_03_sent_transaction_horizon_urlSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd2\xd5\x55\xd0\xce\xcd\x4c\x2f\x4a\x2c\x49\x55\x08\x2d\xe0\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x08\x4e\xcd\x2b\x09\x29\x4a\xcc\x2b\x4e\x4c\x2e\xc9\xcc\xcf\x53\x70\x74\x71\x51\xc8\xc8\x2f\xca\xac\xca\xcf\x8b\x2f\x2d\xca\x51\x28\x4b\x2c\x4a\xce\x48\x2c\xd2\x30\x32\x35\xd5\x54\x70\x71\x75\x73\x0c\xf5\x09\x51\xf0\x0b\xf5\xf1\xb1\xe6\xe2\x42\x36\xd6\x25\xbf\x3c\x0f\xaf\xc1\x2e\x41\xfe\x01\x0a\xce\xfe\x3e\xa1\xbe\x7e\xc8\x16\x58\x73\x01\x06\x00\xcc\x38\x5d\x11\x9d\x00\x00\x00")
+
+func postgresPostgres_03_sent_transaction_horizon_urlSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgresPostgres_03_sent_transaction_horizon_urlSql,
+		"postgres/postgres_03_sent_transaction_horizon_url.sql",
+	)
+}
+
+func postgresPostgres_03_sent_transaction_horizon_urlSql() (*asset, error) {
+	bytes, err := postgresPostgres_03_sent_transaction_horizon_urlSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/postgres_03_sent_transaction_horizon_url.sql", size: 157, mode: os.FileMode(420), modTime: time.Unix(1792202167, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgresPostgres_04_hook_deliverySql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x91\xc1\x4e\xc2\x40\x10\x86\xef\xfb\x14\x73\x84\x08\x89\x9a\x70\xe2\x54\xed\x1a\x89\xb5\x90\x06\x12\x39\x6d\x26\xec\x04\x36\xb4\xdd\x66\x76\x40\xfa\xf6\x06\x8b\x62\x37\x72\xfe\xbe\xfd\x77\x66\xfe\xf1\x18\xee\x2a\xb7\x65\x14\x82\x55\xa3\x9e\x0b\x9d\x2c\x35\x2c\x93\xa7\x4c\xc3\xab\xf7\xfb\x94\x4a\x77\x24\x6e\x61\xa0\x00\x9c\x85\x40\xec\xb0\x1c\x29\x80\x9d\xf7\x7b\x38\x22\x6f\x76\xc8\x83\xc7\xfb\x21\xe4\xf3\x25\xe4\xab\x2c\x3b\xc3\x03\x97\x57\x36\x99\xf4\x61\x83\x6d\xe9\xd1\x82\xd0\x49\x7a\xc0\x37\xc4\x28\xce\xd7\xc6\xd9\xdb\xcf\x83\xa0\x1c\xc2\x2f\x7f\x88\xbe\x46\x11\xaa\x1a\x09\xe0\x6a\xa1\x2d\x71\x0f\xd6\x74\x12\x73\x31\x0c\x0a\x88\xab\x28\x08\x56\x4d\xcf\x2a\x31\x88\x21\x66\xcf\xdd\x8c\xa9\x7e\x49\x56\xd9\x95\x6f\x98\x50\xc8\xde\x0e\xb0\xdd\xd5\x62\x25\xce\x59\x14\xb3\xf7\xa4\x58\xc3\x9b\x5e\xc3\xc0\xd9\xa1\x1a\x4e\xd5\x4f\x05\xb3\x3c\xd5\x1f\xdf\x47\xbe\x84\xb5\xa6\x5b\xdc\xc4\x3b\xcc\xf3\xa8\xa9\xce\x1b\x41\x24\x9e\xc3\xff\xd6\x9d\xfa\xcf\x5a\xa5\xc5\x7c\xf1\x4f\xdd\x53\xf5\x35\x00\x64\x71\xd4\xfb\x19\x02\x00\x00")
+
+func postgresPostgres_04_hook_deliverySqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgresPostgres_04_hook_deliverySql,
+		"postgres/postgres_04_hook_delivery.sql",
+	)
+}
+
+func postgresPostgres_04_hook_deliverySql() (*asset, error) {
+	bytes, err := postgresPostgres_04_hook_deliverySqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/postgres_04_hook_delivery.sql", size: 537, mode: os.FileMode(420), modTime: time.Unix(1792202457, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgresPostgres_05_received_payment_operation_idSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x90\xb1\x4e\xf3\x30\x18\x45\x77\x3f\xc5\x1d\xff\x5f\x10\x5e\x20\x30\x54\xc4\x40\x24\x48\x8a\x69\x04\x5b\x65\xfa\x5d\x8a\x45\x63\x47\x8e\x49\xe0\xed\x51\x49\x86\x56\x5d\x58\x7d\x8e\xce\xb5\xbe\x2c\xc3\x59\xeb\xb6\xd1\x26\xa2\xe9\x54\x96\xc1\xb0\x0d\x03\x21\x9f\xdd\xce\x6d\x6c\x62\x8f\xde\x0e\x14\xbc\xf2\x2d\x44\x22\xbd\x13\xce\x0b\xbf\x30\xda\x1e\x56\x84\x72\x8e\x0f\xb2\x73\x7e\xfb\x0b\x3d\x47\xf6\x09\x31\x8c\xaa\xd0\xf7\x7a\xa5\x71\x63\xea\x07\x18\x6e\xe8\x06\xca\xd2\x7e\xb7\xf4\x09\x61\x27\x8c\x0a\x68\x9e\xca\xea\xf6\x84\xee\x23\x7b\xfa\x7c\xa7\x8d\x9e\xdc\x8b\xd0\x31\xda\xe4\x82\x5f\x3b\xc1\xd5\xa4\x1c\x3f\x2e\xaa\x62\x76\x9d\xe0\x72\x36\x9c\xe4\x4a\x5d\x1b\xbd\x58\x69\x34\x55\xf9\xd8\x68\x94\x55\xa1\x5f\x10\xe7\xcd\x6e\xda\x5c\x1f\xa5\xea\xea\xe4\x4f\xff\x0e\x85\xff\xb9\x52\x87\xb7\x2b\xc2\xe8\x55\x61\xea\xe5\x1f\xe2\xb9\xfa\x19\x00\xfc\x36\xe2\x3e\x76\x01\x00\x00")
+
+func postgresPostgres_05_received_payment_operation_idSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgresPostgres_05_received_payment_operation_idSql,
+		"postgres/postgres_05_received_payment_operation_id.sql",
+	)
+}
+
+func postgresPostgres_05_received_payment_operation_idSql() (*asset, error) {
+	bytes, err := postgresPostgres_05_received_payment_operation_idSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/postgres_05_received_payment_operation_id.sql", size: 374, mode: os.FileMode(420), modTime: time.Unix(1792202826, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgresPostgres_06_received_payment_detailsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x92\x51\x6b\xfa\x30\x14\xc5\xdf\xf3\x29\xee\x9b\xca\x5f\xc1\xbf\x4c\x5f\xfa\xd4\xad\x19\x0c\x4a\x95\x52\x61\x6f\xe1\x12\xef\x6c\x19\x49\x4a\x72\x75\xf8\xed\x87\xd3\x05\xdb\x81\xf6\x31\xe7\x9e\x5f\xda\x9b\x73\x66\x33\xf8\x67\x9a\xbd\x47\x26\xd8\xb6\x22\xcd\x2b\x59\x42\x95\x3e\xe7\x12\x4a\xd2\xd4\x1c\x69\xb7\xc1\x93\x21\xcb\x02\x20\xcd\x32\x60\x8f\x36\xa0\xe6\xc6\x59\x55\x63\xa8\xe1\x88\x5e\xd7\xe8\xc7\xab\xa7\x09\x14\xeb\x0a\x8a\x6d\x9e\x43\x26\x5f\xd3\x6d\x5e\xc1\x68\x34\xbd\x72\x1f\xde\x19\x85\x5a\xbb\x83\xe5\xc8\x2c\x57\x77\x19\x34\x1d\xf7\x62\xb9\xbc\x6f\x0f\x81\x58\x69\xb7\xa3\x88\xfc\x5f\x0c\x20\x9a\x10\x0e\xe4\x87\xfe\x94\x21\xe3\x14\x9f\xda\x9b\x8f\xcc\x1f\x02\x83\x77\xa8\x9d\xfb\x54\x81\x91\x0f\xe1\xb2\x49\x63\x99\xf6\xe4\xa3\xf5\xcc\x75\xcc\xc8\x4c\xa6\xe5\x10\x9d\x7f\x6e\x9f\x27\x42\xbc\x94\x32\xad\x24\xbc\x15\x99\x7c\x07\x7f\x4d\xb6\xbd\x24\xab\xce\x2b\xc1\xba\xe8\x27\x0e\xe3\xf3\x60\x92\x08\x71\xdb\x92\xcc\x7d\x59\x91\x95\xeb\xcd\x9d\xcb\x12\xf1\xa0\x49\x3f\x7c\xbf\x4a\xd3\xdf\xc1\x6d\x57\xa2\x88\xa6\x7b\x8c\x61\xf7\xa4\x4b\x9a\x51\x8c\x71\x75\x94\x78\xe8\xbf\x77\x77\x80\xcc\x64\x5a\x0e\x89\xf8\x1e\x00\x24\xce\x57\x63\x28\x03\x00\x00")
+
+func postgresPostgres_06_received_payment_detailsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgresPostgres_06_received_payment_detailsSql,
+		"postgres/postgres_06_received_payment_details.sql",
+	)
+}
+
+func postgresPostgres_06_received_payment_detailsSql() (*asset, error) {
+	bytes, err := postgresPostgres_06_received_payment_detailsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/postgres_06_received_payment_details.sql", size: 808, mode: os.FileMode(420), modTime: time.Unix(1792202922, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgresPostgres_07_received_payment_account_idSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\xcf\x3f\x0b\xc2\x30\x14\x04\xf0\xfd\x7d\x8a\xdb\xda\xa2\x1d\x75\xe9\x14\x4d\x04\x21\xa4\x25\xa4\xe0\x56\x42\x1a\x34\x43\xff\x10\x6a\xc5\x6f\x2f\xa8\x60\x41\xd0\xf9\x3d\x7e\x77\x97\xe7\x58\x75\xe1\x1c\xed\xe4\x51\x8f\xc4\xa4\x11\x1a\x86\xed\xa4\x80\xf6\xce\x87\xd9\xb7\x95\xbd\x77\xbe\x9f\xc0\x38\x87\x75\x6e\xb8\xf6\x53\x13\x5a\xcc\x36\xba\x8b\x8d\xe9\x66\x9b\x41\x95\x06\xaa\x96\x12\x5c\x1c\x58\x2d\x0d\x92\xa4\x20\xda\x6b\xc1\x8c\xc0\x51\x71\x71\x42\x7c\x6b\xe3\x4b\x6b\x16\x52\xa9\xbe\xb2\xd2\xcf\x79\x8d\xd0\x66\x05\xd1\xb2\x29\x1f\x6e\x3d\x71\x5d\x56\x7f\xf1\x82\x7e\x6e\x7a\x1a\xcb\xef\xc7\x00\x89\x31\xd1\x0d\x10\x01\x00\x00")
+
+func postgresPostgres_07_received_payment_account_idSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgresPostgres_07_received_payment_account_idSql,
+		"postgres/postgres_07_received_payment_account_id.sql",
+	)
+}
+
+func postgresPostgres_07_received_payment_account_idSql() (*asset, error) {
+	bytes, err := postgresPostgres_07_received_payment_account_idSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/postgres_07_received_payment_account_id.sql", size: 272, mode: os.FileMode(420), modTime: time.Unix(1792203083, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgresPostgres_08_received_payment_refundSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\xce\xb1\x0a\xc2\x30\x10\xc6\xf1\x3d\x4f\x71\xa3\x62\x0b\x0a\xe2\xd2\x29\x92\x3a\x05\x2d\xa5\x99\xcb\x91\x9e\x26\x43\x53\x49\xae\x15\xdf\xde\x4d\x4c\x17\xd7\xe3\xfe\x7c\xbf\xb2\x84\xdd\xe8\x1f\x11\x99\xc0\x3c\x85\xd4\x5d\xdd\x42\x27\xcf\xba\x86\x96\x2c\xf9\x85\x86\x06\xdf\x23\x05\x16\x00\x52\x29\x88\x74\x9f\xc3\xd0\x27\x46\x9e\x13\x2c\x18\xad\xc3\xb8\x39\xec\xb7\xa0\xea\x8b\x34\xba\x83\xab\xd1\xba\xc8\xbf\x39\x62\x48\x68\xd9\x4f\xa1\x77\x98\xdc\xb7\x3b\x1d\xf3\xae\x12\xe2\x57\xa4\xa6\x57\xf8\x63\x52\xed\xad\xc9\x51\xc5\xea\xba\x1e\xaf\xc4\x67\x00\xc9\x83\x1e\x9d\xf5\x00\x00\x00")
+
+func postgresPostgres_08_received_payment_refundSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgresPostgres_08_received_payment_refundSql,
+		"postgres/postgres_08_received_payment_refund.sql",
+	)
+}
+
+func postgresPostgres_08_received_payment_refundSql() (*asset, error) {
+	bytes, err := postgresPostgres_08_received_payment_refundSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/postgres_08_received_payment_refund.sql", size: 245, mode: os.FileMode(420), modTime: time.Unix(1792203334, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgresPostgres_09_stream_cursorSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\xcf\x31\x4b\xc4\x30\x18\xc6\xf1\x3d\x9f\xe2\x19\x5b\xf4\xe0\x1c\x9c\x6e\xaa\xd7\x0c\xc5\x9a\x9e\xb1\x01\x6f\x2a\x2f\x97\x50\x83\x26\x0d\x49\xaa\x5f\x5f\x5a\x50\xb4\xdc\x9a\xfc\x5f\xf8\x3d\xbb\x1d\x6e\x9c\x1d\x23\x65\x03\x15\xd8\x51\xf2\xaa\xe7\xe8\xab\x87\x96\xe3\x25\x47\x43\xee\x38\xc7\x34\x45\x14\x0c\xb0\x1a\xc9\x44\x4b\x1f\xb7\x0c\xf0\xe4\x0c\x3e\x29\x5e\xde\x28\x16\x77\xfb\x7d\x09\xd1\xf5\x10\xaa\x6d\x97\xdf\x40\xa3\xf5\xe3\x90\xa7\x77\xe3\x7f\xab\xfb\x4d\x34\x07\x4d\xd9\xe8\x81\x32\xb2\x75\x26\x65\x72\xe1\x5f\x70\x92\xcd\x53\x25\xcf\x78\xe4\x67\x14\x56\x97\xac\x3c\xb0\x1f\xa3\x12\xcd\xb3\xe2\x68\x44\xcd\x5f\x91\x56\xea\x65\xa5\x0e\xab\xac\x13\x1b\xff\xf2\xba\x9c\xff\x5d\x5c\x4f\x5f\x9e\xd5\xb2\x3b\x5d\x59\x7c\x60\xdf\x03\x00\xfa\x38\x2a\xcf\x1c\x01\x00\x00")
+
+func postgresPostgres_09_stream_cursorSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgresPostgres_09_stream_cursorSql,
+		"postgres/postgres_09_stream_cursor.sql",
+	)
+}
+
+func postgresPostgres_09_stream_cursorSql() (*asset, error) {
+	bytes, err := postgresPostgres_09_stream_cursorSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/postgres_09_stream_cursor.sql", size: 284, mode: os.FileMode(420), modTime: time.Unix(1792203572, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgresPostgres_10_stream_cursor_seedSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x91\x41\x6f\xa3\x30\x14\x84\xef\xfe\x15\xb3\x27\x40\x0b\xf9\x01\xbb\xea\x81\x12\xda\x22\x35\x10\x01\x51\xda\x5e\x22\x07\x5e\xc0\x6a\x30\xc8\x36\x44\x91\xf2\xe3\x2b\x08\x91\xb8\xf4\xf6\xa4\x67\xcf\xcc\x37\xcf\xf3\xf0\xb7\x11\x95\xe2\x86\xb0\xeb\x98\xe7\x21\x33\x5c\x19\x74\xfc\xda\x90\x34\xd0\x46\x11\x6f\x34\x4e\xaa\x6d\x60\x6a\x82\xa4\x0b\xe9\xc5\x9e\x0f\x54\xe2\xd4\x2a\xd0\x40\xea\x0a\x45\x05\x89\x41\xc8\x6a\x94\xe2\x45\xd1\xf6\xd2\xac\xb0\xbd\xab\xe9\xf9\xf9\x45\x98\xba\xed\xcd\x63\x8f\x68\x0d\xae\x08\x35\x97\xe5\x99\x4a\x1c\xaf\x93\xd3\x59\x68\x43\x92\xd4\x8a\x45\x71\x16\xa6\x39\xa2\x38\x4f\x90\x4d\x81\x82\x5e\xe9\x56\xc1\x96\xbc\x21\x17\x1d\xaf\x84\xac\x0e\xa6\xfd\x26\xe9\xa2\xef\x4a\x6e\xa8\x3c\x70\xe3\x30\x20\x0b\xdf\xc3\x20\x87\x35\x07\xd6\xff\x2c\xdc\x6e\x0f\xe7\x83\x28\x5d\x04\x7e\x96\xdb\x1b\xff\xc3\x9e\x86\xa5\x16\xfc\x0c\x47\x51\x09\x69\x1c\x67\x9c\x07\xae\x8a\x9a\x2b\xc7\x45\x9c\xec\x6d\x07\x7e\x8e\x3c\xda\x84\xf8\x4a\xe2\x10\xd6\x2e\x0f\x2c\x06\xbc\xa4\xc9\x06\xe9\x54\x03\x95\x33\x38\x03\xf6\x6f\x61\x1a\x2e\x7c\xf1\xe7\x09\x96\x05\x3f\x5e\xff\x9e\x0d\x71\x32\x52\xc3\x9e\x21\x46\xda\xbb\xfe\xb2\x85\x91\xf2\x35\x4d\x76\x5b\x3c\x7f\x2e\x3e\xff\x67\x6c\x79\xdc\x75\x7b\x91\xec\x67\x00\xf5\xc7\x91\xb7\xee\x01\x00\x00")
+
+func postgresPostgres_10_stream_cursor_seedSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgresPostgres_10_stream_cursor_seedSql,
+		"postgres/postgres_10_stream_cursor_seed.sql",
+	)
+}
+
+func postgresPostgres_10_stream_cursor_seedSql() (*asset, error) {
+	bytes, err := postgresPostgres_10_stream_cursor_seedSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/postgres_10_stream_cursor_seed.sql", size: 494, mode: os.FileMode(420), modTime: time.Unix(1792203743, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgresPostgres_11_trustline_authorizationSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x93\x41\x6b\x83\x30\x18\x86\xef\xf9\x15\xdf\xad\x95\xb5\xb0\x95\xb5\x17\x4f\x6e\x66\x50\xe6\xb4\x13\x85\xf5\x24\x1f\x1a\x66\x20\x35\x25\xf9\x5c\x61\xbf\x7e\xb4\xa0\xc6\xb6\xeb\xa1\xe7\xf7\x21\xef\xeb\x63\x32\x9f\xc3\xc3\x4e\x7e\x1b\x24\x01\xf9\x9e\xbd\xa6\x3c\xc8\x38\x64\xc1\x4b\xc4\x21\x33\xad\x25\x25\x1b\x11\xb4\x54\x6b\x23\x7f\x91\xa4\x6e\x60\xca\x00\x64\x05\x56\x18\x89\x6a\xc6\x00\xf4\x5e\x98\x53\x54\xc8\x0a\x7e\xd0\x94\x35\x9a\xe9\x62\xb9\xf4\x20\x4e\x32\x88\xf3\x28\x3a\x52\x58\x96\xba\x6d\xc8\x65\x96\xab\x33\xc4\x5a\x41\x45\xa9\x2b\xd1\x23\x4f\x8b\x31\x62\x09\xa9\xb5\x43\xfc\x38\x8e\x8d\x40\xab\x9b\x3e\x3e\x8d\x08\xf9\x5b\x90\x47\x03\x43\x06\x1b\x8b\xe5\x69\x70\x8d\xb6\xee\xe9\xd5\xf3\x25\x5c\x1a\x81\x24\xaa\x02\x09\x48\xee\x84\x25\xdc\xed\x47\x8d\x9b\x74\xfd\x11\xa4\x5b\x78\xe7\x5b\x98\xca\xca\x63\x9e\xcf\x3a\x8d\x79\xbc\xfe\xcc\x39\xac\xe3\x90\x7f\x01\x75\x36\xd1\xb5\x59\x8c\xe4\x25\xf1\xbf\xd2\x5d\xce\xf3\xbb\x86\x9b\x47\x3b\xc6\x6f\x1c\x3c\x50\x33\x90\x95\xb3\xfe\xfc\x12\x28\xa5\x0f\x4a\x5a\xba\xbc\x00\x4e\xd1\x9d\xbf\xb6\xf7\x3e\x99\xdc\x29\xb5\x5b\x57\x0c\x63\x0a\xa7\x34\x89\xaf\x7e\xc9\x00\xcf\x9c\x89\x47\x09\xee\xc3\x08\xf5\xa1\x61\x61\x9a\x6c\x6e\x3e\x0c\xff\x3a\xa2\x94\x3e\x28\x69\xc9\x67\x7f\x03\x00\x72\x9b\x7d\x49\x6c\x03\x00\x00")
+
+func postgresPostgres_11_trustline_authorizationSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgresPostgres_11_trustline_authorizationSql,
+		"postgres/postgres_11_trustline_authorization.sql",
+	)
+}
+
+func postgresPostgres_11_trustline_authorizationSql() (*asset, error) {
+	bytes, err := postgresPostgres_11_trustline_authorizationSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/postgres_11_trustline_authorization.sql", size: 876, mode: os.FileMode(420), modTime: time.Unix(1792205389, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgresPostgres_12_hook_delivery_operation_idSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd2\xd5\x55\xd0\xce\xcd\x4c\x2f\x4a\x2c\x49\x55\x08\x2d\xe0\x72\x0e\x72\x75\x0c\x71\x55\xf0\xf4\x73\x71\x8d\x50\xc8\xc8\xcf\xcf\x4e\x49\xcd\xc9\x2c\x4b\x2d\xaa\x8c\xcf\x2f\x48\x2d\x4a\x2c\xc9\xcc\xcf\x8b\xcf\x4c\x51\xf0\xf7\x53\xf0\xc8\xcf\xcf\x76\x81\x4a\x2a\x68\x20\xcb\x6a\x5a\x73\x71\x21\x1b\xeb\x92\x5f\x9e\xc7\xe5\x12\xe4\x1f\x40\xc8\x58\x6b\x2e\xc0\x00\x9a\x83\x4c\xaf\x8e\x00\x00\x00")
+
+func postgresPostgres_12_hook_delivery_operation_idSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgresPostgres_12_hook_delivery_operation_idSql,
+		"postgres/postgres_12_hook_delivery_operation_id.sql",
+	)
+}
+
+func postgresPostgres_12_hook_delivery_operation_idSql() (*asset, error) {
+	bytes, err := postgresPostgres_12_hook_delivery_operation_idSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/postgres_12_hook_delivery_operation_id.sql", size: 142, mode: os.FileMode(420), modTime: time.Unix(1792207291, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgresPostgres_13_sent_transaction_idempotency_keySql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x8f\xc1\xca\x82\x40\x14\x85\xf7\xf7\x29\xee\x52\xf9\x71\xf3\x83\x2b\x57\x53\x33\x41\x30\x68\xd8\x08\xed\x64\xb0\x4b\x49\x78\x47\xc6\x4b\xe1\xdb\x07\x6d\x0a\x85\x68\x7f\xce\xf7\x9d\x93\x65\xf8\x37\xf4\x97\xe8\x85\xb0\x19\x41\x59\x67\x6a\x74\x6a\x63\x0d\x1e\x89\xc5\x45\xcf\x93\xef\xa4\x0f\x8c\x4a\x6b\xec\xcf\x34\x8c\x41\x88\xbb\xb9\xbd\xd1\x8c\x77\x1f\xbb\xab\x8f\xc9\x7f\x9e\xa7\xa8\xcd\x4e\x35\xd6\x61\xd9\x58\x5b\x00\x6c\x6b\xa3\x9c\xc1\x7d\xa9\xcd\x09\x27\x62\x91\x37\xac\x5d\x82\xaa\x72\xe5\x4b\x16\x99\xb4\x00\xf8\x9c\xab\xc3\x83\x41\xd7\xd5\xe1\x37\x45\x01\x5f\xdf\xbd\x40\xab\xca\x73\x00\xe2\xc6\xa5\x06\x1f\x01\x00\x00")
+
+func postgresPostgres_13_sent_transaction_idempotency_keySqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgresPostgres_13_sent_transaction_idempotency_keySql,
+		"postgres/postgres_13_sent_transaction_idempotency_key.sql",
+	)
+}
+
+func postgresPostgres_13_sent_transaction_idempotency_keySql() (*asset, error) {
+	bytes, err := postgresPostgres_13_sent_transaction_idempotency_keySqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/postgres_13_sent_transaction_idempotency_key.sql", size: 287, mode: os.FileMode(420), modTime: time.Unix(1792207500, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgresPostgres_14_pending_transaction_idSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x92\x4d\x6a\x85\x30\x14\x85\xe7\x59\xc5\x1d\x5a\x8a\x2b\x70\x94\x36\x29\x14\x82\x4a\x88\xd0\x99\x04\xbd\xb5\x81\x1a\x25\x5e\x5b\xda\xd5\x17\x6d\x0b\x8a\xfa\xf0\xbd\xf9\x39\xdf\xf9\xf2\x13\xc7\x70\xdf\xba\x26\x58\x42\x28\x7a\xc6\x95\x91\x1a\x0c\x7f\x50\x12\x34\x56\xe8\x3e\xb0\xce\xed\x57\x8b\x9e\x80\x0b\x01\x01\x5f\x47\x5f\x97\x14\xac\x1f\x6c\x45\xae\xf3\xa5\xab\xc1\x79\xc2\x06\x03\x08\xf9\xc4\x0b\x65\x20\x2d\x94\x4a\x18\x7b\xd4\x92\x1b\x09\xcf\xa9\x90\x2f\x10\xfe\x68\xfd\x2f\xad\xdc\x27\x65\xe9\x66\x36\xda\x4d\xde\x25\x6c\x25\x6b\xc2\x38\xd0\xbb\xf3\xc8\x47\x7a\xeb\x82\xfb\xb6\x53\x70\x76\xbe\x41\x96\xfe\x69\x76\x49\xdb\x91\x3d\x98\x8d\xd6\xc9\x49\x76\x79\xd1\xa2\xfb\xf4\x4c\xe8\x2c\xbf\x66\xee\xdc\x81\x67\xea\xa6\xb8\xd8\x3a\xf5\x0e\x09\xbb\xf8\x13\x66\xdc\x41\xf1\x67\x00\x66\x0b\x42\x48\x51\x02\x00\x00")
+
+func postgresPostgres_14_pending_transaction_idSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgresPostgres_14_pending_transaction_idSql,
+		"postgres/postgres_14_pending_transaction_id.sql",
+	)
+}
+
+func postgresPostgres_14_pending_transaction_idSql() (*asset, error) {
+	bytes, err := postgresPostgres_14_pending_transaction_idSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/postgres_14_pending_transaction_id.sql", size: 593, mode: os.FileMode(420), modTime: time.Unix(1792207896, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _postgresPostgres_15_sent_transaction_retry_ofSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\xce\xcd\xaa\xc2\x30\x10\xc5\xf1\xfd\x3c\xc5\x2c\xef\x45\xfa\x04\x59\x45\x33\x82\x10\x5a\xa9\x29\xb8\x2b\x45\xa6\x25\x0b\x27\x32\x1d\x90\xbe\xbd\x20\xf8\x01\x82\xae\x0f\xe7\xc7\xbf\xaa\x70\x75\xce\x93\x0e\xc6\xd8\x5d\xc0\xc7\x44\x2d\x26\xbf\x8e\x84\x07\x16\x4b\x3a\xc8\x3c\x9c\x2c\x17\x41\x1f\x02\x2a\x9b\x2e\x7d\x19\x31\x8b\xf1\xc4\x8a\x81\xb6\xbe\x8b\x09\xeb\x2e\x46\x07\xb0\x69\xc9\x27\xc2\x5d\x1d\xe8\x88\x33\x8b\xd9\x0b\xe8\x9f\xe7\xa6\xfe\xc0\xff\x1e\xe3\xbf\x03\x78\x8f\x0a\xe5\x2a\x10\xda\x66\xff\x03\x75\xf0\x35\xfe\x2e\x28\x9b\x2e\x7d\x19\x1d\xdc\x06\x00\xef\x0a\x6e\x09\xf7\x00\x00\x00")
+
+func postgresPostgres_15_sent_transaction_retry_ofSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_postgresPostgres_15_sent_transaction_retry_ofSql,
+		"postgres/postgres_15_sent_transaction_retry_of.sql",
+	)
+}
+
+func postgresPostgres_15_sent_transaction_retry_ofSql() (*asset, error) {
+	bytes, err := postgresPostgres_15_sent_transaction_retry_ofSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "postgres/postgres_15_sent_transaction_retry_of.sql", size: 247, mode: os.FileMode(420), modTime: time.Unix(1792208074, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _sqlite3Sqlite3_01_initSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x56\x4d\x6f\xe3\x36\x10\xbd\xeb\x57\xcc\x2d\x31\x9a\x2c\x9c\xa0\xd9\x4b\x4e\x6e\xac\xa2\xc6\x7a\xe5\xac\x63\x03\xdd\x13\xc1\x90\x13\x9b\x88\x48\x2a\xe4\x28\x8d\xfa\xeb\x0b\x7d\x46\x1f\x96\xbd\xee\x55\xf3\x66\xf4\xf8\xde\xcc\x90\xd7\xd7\xf0\x9b\x56\x3b\xc7\x09\x61\x9b\x04\xd7\xd7\xf0\xf4\x63\xa9\x08\xc1\x8b\x3d\x6a\x0e\xca\x83\x70\xc8\x09\x25\x70\x02\x6b\x04\x5e\x81\xa2\x0b\x0f\xf8\x96\xf2\x18\xc8\x42\x62\x3d\xed\x1c\x7a\xe0\x46\x82\xce\xfc\x5b\x5c\xe5\xe6\xc5\xf8\x0b\xa1\x6b\x20\xec\x66\xca\x3c\x39\xe4\x9a\x89\xd4\x79\xeb\x98\x47\x94\x5f\xf2\x94\x92\x83\xb2\xe6\x4b\xf0\xb0\x0e\x67\x9b\x10\x36\xb3\x3f\x96\x21\xac\x51\xa0\x7a\x47\xf9\xc8\x33\x8d\x86\xe0\x32\x00\x50\x12\x94\x21\xdc\xa1\x83\xc7\xf5\xe2\xfb\x6c\xfd\x13\xbe\x85\x3f\x61\xb6\xdd\xac\x16\xd1\xc3\x3a\xfc\x1e\x46\x9b\xab\x00\xc0\x26\x58\xd6\x64\x4a\xc2\x3b\x77\x62\xcf\xdd\xe5\xed\xdd\xdd\x04\xa2\xd5\x06\xa2\xed\x72\x99\xa3\x12\x67\x05\x7a\x8f\x92\x71\x02\x52\x1a\x3d\x71\x9d\x74\x21\x7c\xa7\xcc\x8e\x91\x7d\x45\x33\x5e\xc8\x13\xa7\xd4\x8f\xc7\xc9\x71\xe3\xb9\x28\x08\xed\xb9\xdf\x37\xc8\xaf\xbf\x7f\x02\x61\x1e\xfe\x39\xdb\x2e\x37\x70\x71\x91\xe7\xbc\x38\xab\x19\x17\xc2\xa6\x86\x1a\xfc\xdd\xd7\x51\x3c\xd7\x1d\x64\x87\x43\x1f\xea\x3d\x12\x13\x56\x62\x03\xbf\xb9\x3d\x81\x56\xde\xa7\xe8\x7e\x85\x88\x46\x6d\x19\x65\x49\xab\xf8\xf4\x28\xf8\x97\x38\xef\xad\x7d\x65\xa5\xce\x25\xf3\xba\x0f\x6a\x58\xad\x75\x01\xe4\x44\xa8\x13\xf2\x0d\x6a\x50\x75\x9a\x63\x2b\x79\xdb\x4d\x72\xe4\x60\x0e\x5f\x52\x23\x2b\x12\x4d\xc2\xcd\x74\x32\xe0\x50\x21\x8f\xda\xde\xce\x09\x26\xf7\x41\xdd\xfc\xdb\x68\xf1\x63\x1b\xc2\x22\x9a\x87\x7f\x83\xab\x66\x20\x29\x67\x80\x75\xfa\x7a\x15\x0d\x67\xa4\x0d\x98\xdc\xd7\x35\x0f\x17\xcb\x8d\x3a\x58\x24\x0f\x9c\x4a\x6e\x49\x77\xa8\xc4\x67\xf8\x0a\x0a\x26\x75\xb5\x72\xb6\x9f\xd0\xd0\xe6\x53\x9d\xb3\x66\xfb\x80\xfe\xb5\x61\xb9\xa7\xde\xa6\x4e\xe0\x41\x3f\x8b\x70\xfa\xac\x15\xd1\xb1\x99\xf7\xa9\x10\x88\xb2\x0f\xe9\x9b\x1c\xa3\xcc\xb9\x3e\xab\x9d\x32\x34\x88\xa2\x79\xc7\xd8\x26\xc8\x3e\xa4\x03\xc2\x0f\xea\xfc\xc2\xa1\x4f\x63\x2a\x62\x35\xd1\x62\x60\xfb\x55\xf6\xd6\xa9\x7f\xad\x61\xa9\x8b\xc7\x81\xed\xee\x29\xe5\x5d\x48\xd4\x89\x25\x34\x22\xfb\x86\xd9\x59\xea\xaa\xcf\x54\xf6\x8a\x59\xf7\xaf\xdd\x23\xbc\xa5\xe8\x69\xd8\xd8\x6d\xd0\x09\xaf\x1c\xfa\xc4\x1a\x8f\xc7\x27\xba\x41\x3d\x5b\x99\x95\x5a\xf6\x21\xd5\x45\x35\xee\xa9\xb0\x3a\x89\x71\x00\xe9\xd7\xa9\x86\xef\xb2\xa7\xc2\x64\x28\xf1\x5f\xd6\xbe\xce\x31\x56\xef\xe8\xce\x13\x38\xdf\x4f\x8d\x20\xb7\x3d\x41\x06\x3e\xb7\x83\x09\xcf\x62\xcb\xe5\xb0\x9b\x3a\x5b\x61\x34\xfd\x84\x17\xa3\x2b\x33\xff\x83\xc1\x0f\x62\x15\x62\x5c\xe4\x98\x7b\x62\xe8\x9c\x75\xff\xd3\x25\x59\x0a\x7a\xcc\xa5\xb6\x13\xe5\x66\xca\x05\xad\x12\xb3\xfa\x86\xe8\xf3\x5d\x45\x3d\xc3\x4a\xdc\x55\xff\x60\x03\x9b\x9f\x8a\x37\xcb\x43\xf1\x64\x39\xcb\x66\xc3\x75\xfb\x02\x9c\x4e\x4e\x3f\x2b\xee\x7a\xa0\x34\x91\xe3\x72\x8d\x5e\x19\xe5\x2b\xab\x7a\x64\x15\x2c\x56\x51\xef\x18\xf9\xd7\x3c\xbd\xfd\x02\x9c\xdb\x7f\x4c\x30\x5f\xaf\x1e\xab\x83\xf7\x36\xfa\x7d\x3b\xd6\xdb\xde\x9d\x58\x77\xf5\x74\x42\x6d\x07\x3a\x81\x36\xbb\xfb\xe0\xbf\x01\x00\xe2\x40\x25\x9e\x98\x0a\x00\x00")
+
+func sqlite3Sqlite3_01_initSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_sqlite3Sqlite3_01_initSql,
+		"sqlite3/sqlite3_01_init.sql",
+	)
+}
+
+func sqlite3Sqlite3_01_initSql() (*asset, error) {
+	bytes, err := sqlite3Sqlite3_01_initSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "sqlite3/sqlite3_01_init.sql", size: 2712, mode: os.FileMode(420), modTime: time.Unix(1792205017, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _sqlite3Sqlite3_02_trustline_authorizationSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x93\x51\x6b\xf2\x30\x14\x86\xef\xfb\x2b\xce\x9d\xca\xa7\xf0\x4d\xa6\x37\xbd\xea\x6c\x06\x65\x35\x75\xa5\x85\x79\x55\x0e\x6d\xb0\x81\x9a\x48\x72\x3a\x61\xbf\x7e\x28\xb4\x8d\xce\xc9\xb6\xeb\xf3\x34\xef\xdb\x27\x39\xb3\x19\xfc\xdb\xcb\x9d\x41\x12\x90\x1f\xbc\x55\xca\x82\x8c\x41\x16\x3c\xc5\x0c\x32\xd3\x5a\x6a\xa4\x12\x41\x4b\xb5\x36\xf2\x03\x49\x6a\x05\x63\x0f\x40\x56\x20\x15\x89\x9d\x30\xb0\x49\xa3\x75\x90\x6e\xe1\x85\x6d\x21\xc8\xb3\x24\xe2\xab\x94\xad\x19\xcf\xa6\x1e\x80\x3e\x08\x73\xfe\xaa\x90\x15\xbc\xa3\x29\x6b\x34\xe3\xf9\x62\x31\x01\x9e\x64\xc0\xf3\x38\x3e\x51\x58\x96\xba\x55\xe4\x32\x8b\xe5\x15\x62\xad\xa0\xa2\xd4\x95\xe8\x91\x87\xf9\x25\x62\x09\xa9\xb5\xc3\xf8\xff\xe5\xd8\x08\xb4\x5a\xf5\xe3\x73\x89\x90\x3d\x07\x79\x3c\x30\x64\x50\x59\x2c\xcf\x85\x6b\xb4\x75\x4f\x2f\x1f\xbf\xc2\xa5\x11\x48\xa2\x2a\x90\x80\xe4\x5e\x58\xc2\xfd\xa1\x4f\xf4\x26\xbe\xd7\xd9\xcc\x79\xf4\x9a\x33\x88\x78\xc8\xde\x80\x3a\xa9\xe8\x4a\x2d\x2e\x44\x25\xfc\x5b\xf7\x2e\x37\xf1\xbb\x84\xbb\x47\x3b\x76\xef\x1c\x3c\x50\x53\x90\x95\xd3\xfe\xfa\x2d\x34\x8d\x3e\x36\xd2\xd2\xaf\xde\x81\xd3\xe1\x8f\x37\xdc\xeb\x1f\x8d\x7e\xe0\xb6\x2b\x59\x0c\xc1\x85\x13\x90\xf0\x9b\x3f\x34\xc0\x53\xa7\xce\x29\xcd\x5d\x93\x50\x1f\x95\x17\xa6\xc9\xe6\xee\x9a\xf8\xb7\x91\xa6\xd1\xc7\x46\x5a\xf2\xbd\xcf\x01\x00\x6a\x5b\xd6\x0b\x7a\x03\x00\x00")
+
+func sqlite3Sqlite3_02_trustline_authorizationSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_sqlite3Sqlite3_02_trustline_authorizationSql,
+		"sqlite3/sqlite3_02_trustline_authorization.sql",
+	)
+}
+
+func sqlite3Sqlite3_02_trustline_authorizationSql() (*asset, error) {
+	bytes, err := sqlite3Sqlite3_02_trustline_authorizationSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "sqlite3/sqlite3_02_trustline_authorization.sql", size: 890, mode: os.FileMode(420), modTime: time.Unix(1792205391, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _sqlite3Sqlite3_03_hook_delivery_operation_idSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd2\xd5\x55\xd0\xce\xcd\x4c\x2f\x4a\x2c\x49\x55\x08\x2d\xe0\x72\x0e\x72\x75\x0c\x71\x55\xf0\xf4\x73\x71\x8d\x50\xc8\xc8\xcf\xcf\x4e\x49\xcd\xc9\x2c\x4b\x2d\xaa\x8c\xcf\x2f\x48\x2d\x4a\x2c\xc9\xcc\xcf\x8b\xcf\x4c\x51\xf0\xf7\x53\xf0\xc8\xcf\xcf\x76\x81\x4a\x2a\x68\x20\xcb\x6a\x5a\x73\x71\x21\x1b\xeb\x92\x5f\x9e\xc7\xe5\x12\xe4\x1f\x40\xc8\x58\x6b\x2e\xc0\x00\x9a\x83\x4c\xaf\x8e\x00\x00\x00")
+
+func sqlite3Sqlite3_03_hook_delivery_operation_idSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_sqlite3Sqlite3_03_hook_delivery_operation_idSql,
+		"sqlite3/sqlite3_03_hook_delivery_operation_id.sql",
+	)
+}
+
+func sqlite3Sqlite3_03_hook_delivery_operation_idSql() (*asset, error) {
+	bytes, err := sqlite3Sqlite3_03_hook_delivery_operation_idSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "sqlite3/sqlite3_03_hook_delivery_operation_id.sql", size: 142, mode: os.FileMode(420), modTime: time.Unix(1792207291, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _sqlite3Sqlite3_04_sent_transaction_idempotency_keySql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x8f\xc1\xca\x82\x40\x14\x85\xf7\xf7\x29\xee\x52\xf9\x71\xf3\x83\x2b\x57\x53\x33\x41\x30\x68\xd8\x08\xed\x64\xb0\x4b\x49\x78\x47\xc6\x4b\xe1\xdb\x07\x6d\x0a\x85\x68\x7f\xce\xf7\x9d\x93\x65\xf8\x37\xf4\x97\xe8\x85\xb0\x19\x41\x59\x67\x6a\x74\x6a\x63\x0d\x1e\x89\xc5\x45\xcf\x93\xef\xa4\x0f\x8c\x4a\x6b\xec\xcf\x34\x8c\x41\x88\xbb\xb9\xbd\xd1\x8c\x77\x1f\xbb\xab\x8f\xc9\x7f\x9e\xa7\xa8\xcd\x4e\x35\xd6\x61\xd9\x58\x5b\x00\x6c\x6b\xa3\x9c\xc1\x7d\xa9\xcd\x09\x27\x62\x91\x37\xac\x5d\x82\xaa\x72\xe5\x4b\x16\x99\xb4\x00\xf8\x9c\xab\xc3\x83\x41\xd7\xd5\xe1\x37\x45\x01\x5f\xdf\xbd\x40\xab\xca\x73\x00\xe2\xc6\xa5\x06\x1f\x01\x00\x00")
+
+func sqlite3Sqlite3_04_sent_transaction_idempotency_keySqlBytes() ([]byte, error) {
+	return bindataRead(
+		_sqlite3Sqlite3_04_sent_transaction_idempotency_keySql,
+		"sqlite3/sqlite3_04_sent_transaction_idempotency_key.sql",
+	)
+}
+
+func sqlite3Sqlite3_04_sent_transaction_idempotency_keySql() (*asset, error) {
+	bytes, err := sqlite3Sqlite3_04_sent_transaction_idempotency_keySqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "sqlite3/sqlite3_04_sent_transaction_idempotency_key.sql", size: 287, mode: os.FileMode(420), modTime: time.Unix(1792207500, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _sqlite3Sqlite3_05_pending_transaction_idSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x92\x4d\x6a\x85\x30\x14\x85\xe7\x59\xc5\x1d\x5a\x8a\x2b\x70\x94\x36\x29\x14\x82\x4a\x88\xd0\x99\x04\xbd\xb5\x81\x1a\x25\x5e\x5b\xda\xd5\x17\x6d\x0b\x8a\xfa\xf0\xbd\xf9\x39\xdf\xf9\xf2\x13\xc7\x70\xdf\xba\x26\x58\x42\x28\x7a\xc6\x95\x91\x1a\x0c\x7f\x50\x12\x34\x56\xe8\x3e\xb0\xce\xed\x57\x8b\x9e\x80\x0b\x01\x01\x5f\x47\x5f\x97\x14\xac\x1f\x6c\x45\xae\xf3\xa5\xab\xc1\x79\xc2\x06\x03\x08\xf9\xc4\x0b\x65\x20\x2d\x94\x4a\x18\x7b\xd4\x92\x1b\x09\xcf\xa9\x90\x2f\x10\xfe\x68\xfd\x2f\xad\xdc\x27\x65\xe9\x66\x36\xda\x4d\xde\x25\x6c\x25\x6b\xc2\x38\xd0\xbb\xf3\xc8\x47\x7a\xeb\x82\xfb\xb6\x53\x70\x76\xbe\x41\x96\xfe\x69\x76\x49\xdb\x91\x3d\x98\x8d\xd6\xc9\x49\x76\x79\xd1\xa2\xfb\xf4\x4c\xe8\x2c\xbf\x66\xee\xdc\x81\x67\xea\xa6\xb8\xd8\x3a\xf5\x0e\x09\xbb\xf8\x13\x66\xdc\x41\xf1\x67\x00\x66\x0b\x42\x48\x51\x02\x00\x00")
+
+func sqlite3Sqlite3_05_pending_transaction_idSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_sqlite3Sqlite3_05_pending_transaction_idSql,
+		"sqlite3/sqlite3_05_pending_transaction_id.sql",
+	)
+}
+
+func sqlite3Sqlite3_05_pending_transaction_idSql() (*asset, error) {
+	bytes, err := sqlite3Sqlite3_05_pending_transaction_idSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "sqlite3/sqlite3_05_pending_transaction_id.sql", size: 593, mode: os.FileMode(420), modTime: time.Unix(1792207896, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _sqlite3Sqlite3_06_sent_transaction_retry_ofSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\xce\xcd\xaa\xc2\x30\x10\xc5\xf1\xfd\x3c\xc5\x2c\xef\x45\xfa\x04\x59\x45\x33\x82\x10\x5a\xa9\x29\xb8\x2b\x45\xa6\x25\x0b\x27\x32\x1d\x90\xbe\xbd\x20\xf8\x01\x82\xae\x0f\xe7\xc7\xbf\xaa\x70\x75\xce\x93\x0e\xc6\xd8\x5d\xc0\xc7\x44\x2d\x26\xbf\x8e\x84\x07\x16\x4b\x3a\xc8\x3c\x9c\x2c\x17\x41\x1f\x02\x2a\x9b\x2e\x7d\x19\x31\x8b\xf1\xc4\x8a\x81\xb6\xbe\x8b\x09\xeb\x2e\x46\x07\xb0\x69\xc9\x27\xc2\x5d\x1d\xe8\x88\x33\x8b\xd9\x0b\xe8\x9f\xe7\xa6\xfe\xc0\xff\x1e\xe3\xbf\x03\x78\x8f\x0a\xe5\x2a\x10\xda\x66\xff\x03\x75\xf0\x35\xfe\x2e\x28\x9b\x2e\x7d\x19\x1d\xdc\x06\x00\xef\x0a\x6e\x09\xf7\x00\x00\x00")
+
+func sqlite3Sqlite3_06_sent_transaction_retry_ofSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_sqlite3Sqlite3_06_sent_transaction_retry_ofSql,
+		"sqlite3/sqlite3_06_sent_transaction_retry_of.sql",
+	)
+}
+
+func sqlite3Sqlite3_06_sent_transaction_retry_ofSql() (*asset, error) {
+	bytes, err := sqlite3Sqlite3_06_sent_transaction_retry_ofSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "sqlite3/sqlite3_06_sent_transaction_retry_of.sql", size: 247, mode: os.FileMode(420), modTime: time.Unix(1792208074, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -161,8 +875,42 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-	"mysql/mysql_01_init.sql":       mysqlMysql_01_initSql,
+	"mysql/mysql_01_init.sql": mysqlMysql_01_initSql,
+	"mysql/mysql_02_idempotency_key.sql": mysqlMysql_02_idempotency_keySql,
+	"mysql/mysql_03_sent_transaction_horizon_url.sql": mysqlMysql_03_sent_transaction_horizon_urlSql,
+	"mysql/mysql_04_hook_delivery.sql": mysqlMysql_04_hook_deliverySql,
+	"mysql/mysql_05_received_payment_operation_id.sql": mysqlMysql_05_received_payment_operation_idSql,
+	"mysql/mysql_06_received_payment_details.sql": mysqlMysql_06_received_payment_detailsSql,
+	"mysql/mysql_07_received_payment_account_id.sql": mysqlMysql_07_received_payment_account_idSql,
+	"mysql/mysql_08_received_payment_refund.sql": mysqlMysql_08_received_payment_refundSql,
+	"mysql/mysql_09_stream_cursor.sql": mysqlMysql_09_stream_cursorSql,
+	"mysql/mysql_10_stream_cursor_seed.sql": mysqlMysql_10_stream_cursor_seedSql,
+	"mysql/mysql_11_trustline_authorization.sql": mysqlMysql_11_trustline_authorizationSql,
+	"mysql/mysql_12_hook_delivery_operation_id.sql": mysqlMysql_12_hook_delivery_operation_idSql,
+	"mysql/mysql_13_sent_transaction_idempotency_key.sql": mysqlMysql_13_sent_transaction_idempotency_keySql,
+	"mysql/mysql_14_pending_transaction_id.sql": mysqlMysql_14_pending_transaction_idSql,
+	"mysql/mysql_15_sent_transaction_retry_of.sql": mysqlMysql_15_sent_transaction_retry_ofSql,
 	"postgres/postgres_01_init.sql": postgresPostgres_01_initSql,
+	"postgres/postgres_02_idempotency_key.sql": postgresPostgres_02_idempotency_keySql,
+	"postgres/postgres_03_sent_transaction_horizon_url.sql": postgresPostgres_03_sent_transaction_horizon_urlSql,
+	"postgres/postgres_04_hook_delivery.sql": postgresPostgres_04_hook_deliverySql,
+	"postgres/postgres_05_received_payment_operation_id.sql": postgresPostgres_05_received_payment_operation_idSql,
+	"postgres/postgres_06_received_payment_details.sql": postgresPostgres_06_received_payment_detailsSql,
+	"postgres/postgres_07_received_payment_account_id.sql": postgresPostgres_07_received_payment_account_idSql,
+	"postgres/postgres_08_received_payment_refund.sql": postgresPostgres_08_received_payment_refundSql,
+	"postgres/postgres_09_stream_cursor.sql": postgresPostgres_09_stream_cursorSql,
+	"postgres/postgres_10_stream_cursor_seed.sql": postgresPostgres_10_stream_cursor_seedSql,
+	"postgres/postgres_11_trustline_authorization.sql": postgresPostgres_11_trustline_authorizationSql,
+	"postgres/postgres_12_hook_delivery_operation_id.sql": postgresPostgres_12_hook_delivery_operation_idSql,
+	"postgres/postgres_13_sent_transaction_idempotency_key.sql": postgresPostgres_13_sent_transaction_idempotency_keySql,
+	"postgres/postgres_14_pending_transaction_id.sql": postgresPostgres_14_pending_transaction_idSql,
+	"postgres/postgres_15_sent_transaction_retry_of.sql": postgresPostgres_15_sent_transaction_retry_ofSql,
+	"sqlite3/sqlite3_01_init.sql": sqlite3Sqlite3_01_initSql,
+	"sqlite3/sqlite3_02_trustline_authorization.sql": sqlite3Sqlite3_02_trustline_authorizationSql,
+	"sqlite3/sqlite3_03_hook_delivery_operation_id.sql": sqlite3Sqlite3_03_hook_delivery_operation_idSql,
+	"sqlite3/sqlite3_04_sent_transaction_idempotency_key.sql": sqlite3Sqlite3_04_sent_transaction_idempotency_keySql,
+	"sqlite3/sqlite3_05_pending_transaction_id.sql": sqlite3Sqlite3_05_pending_transaction_idSql,
+	"sqlite3/sqlite3_06_sent_transaction_retry_of.sql": sqlite3Sqlite3_06_sent_transaction_retry_ofSql,
 }
 
 // AssetDir returns the file names below a certain
@@ -204,13 +952,48 @@ type bintree struct {
 	Func     func() (*asset, error)
 	Children map[string]*bintree
 }
-
 var _bintree = &bintree{nil, map[string]*bintree{
 	"mysql": &bintree{nil, map[string]*bintree{
 		"mysql_01_init.sql": &bintree{mysqlMysql_01_initSql, map[string]*bintree{}},
+		"mysql_02_idempotency_key.sql": &bintree{mysqlMysql_02_idempotency_keySql, map[string]*bintree{}},
+		"mysql_03_sent_transaction_horizon_url.sql": &bintree{mysqlMysql_03_sent_transaction_horizon_urlSql, map[string]*bintree{}},
+		"mysql_04_hook_delivery.sql": &bintree{mysqlMysql_04_hook_deliverySql, map[string]*bintree{}},
+		"mysql_05_received_payment_operation_id.sql": &bintree{mysqlMysql_05_received_payment_operation_idSql, map[string]*bintree{}},
+		"mysql_06_received_payment_details.sql": &bintree{mysqlMysql_06_received_payment_detailsSql, map[string]*bintree{}},
+		"mysql_07_received_payment_account_id.sql": &bintree{mysqlMysql_07_received_payment_account_idSql, map[string]*bintree{}},
+		"mysql_08_received_payment_refund.sql": &bintree{mysqlMysql_08_received_payment_refundSql, map[string]*bintree{}},
+		"mysql_09_stream_cursor.sql": &bintree{mysqlMysql_09_stream_cursorSql, map[string]*bintree{}},
+		"mysql_10_stream_cursor_seed.sql": &bintree{mysqlMysql_10_stream_cursor_seedSql, map[string]*bintree{}},
+		"mysql_11_trustline_authorization.sql": &bintree{mysqlMysql_11_trustline_authorizationSql, map[string]*bintree{}},
+		"mysql_12_hook_delivery_operation_id.sql": &bintree{mysqlMysql_12_hook_delivery_operation_idSql, map[string]*bintree{}},
+		"mysql_13_sent_transaction_idempotency_key.sql": &bintree{mysqlMysql_13_sent_transaction_idempotency_keySql, map[string]*bintree{}},
+		"mysql_14_pending_transaction_id.sql": &bintree{mysqlMysql_14_pending_transaction_idSql, map[string]*bintree{}},
+		"mysql_15_sent_transaction_retry_of.sql": &bintree{mysqlMysql_15_sent_transaction_retry_ofSql, map[string]*bintree{}},
 	}},
 	"postgres": &bintree{nil, map[string]*bintree{
 		"postgres_01_init.sql": &bintree{postgresPostgres_01_initSql, map[string]*bintree{}},
+		"postgres_02_idempotency_key.sql": &bintree{postgresPostgres_02_idempotency_keySql, map[string]*bintree{}},
+		"postgres_03_sent_transaction_horizon_url.sql": &bintree{postgresPostgres_03_sent_transaction_horizon_urlSql, map[string]*bintree{}},
+		"postgres_04_hook_delivery.sql": &bintree{postgresPostgres_04_hook_deliverySql, map[string]*bintree{}},
+		"postgres_05_received_payment_operation_id.sql": &bintree{postgresPostgres_05_received_payment_operation_idSql, map[string]*bintree{}},
+		"postgres_06_received_payment_details.sql": &bintree{postgresPostgres_06_received_payment_detailsSql, map[string]*bintree{}},
+		"postgres_07_received_payment_account_id.sql": &bintree{postgresPostgres_07_received_payment_account_idSql, map[string]*bintree{}},
+		"postgres_08_received_payment_refund.sql": &bintree{postgresPostgres_08_received_payment_refundSql, map[string]*bintree{}},
+		"postgres_09_stream_cursor.sql": &bintree{postgresPostgres_09_stream_cursorSql, map[string]*bintree{}},
+		"postgres_10_stream_cursor_seed.sql": &bintree{postgresPostgres_10_stream_cursor_seedSql, map[string]*bintree{}},
+		"postgres_11_trustline_authorization.sql": &bintree{postgresPostgres_11_trustline_authorizationSql, map[string]*bintree{}},
+		"postgres_12_hook_delivery_operation_id.sql": &bintree{postgresPostgres_12_hook_delivery_operation_idSql, map[string]*bintree{}},
+		"postgres_13_sent_transaction_idempotency_key.sql": &bintree{postgresPostgres_13_sent_transaction_idempotency_keySql, map[string]*bintree{}},
+		"postgres_14_pending_transaction_id.sql": &bintree{postgresPostgres_14_pending_transaction_idSql, map[string]*bintree{}},
+		"postgres_15_sent_transaction_retry_of.sql": &bintree{postgresPostgres_15_sent_transaction_retry_ofSql, map[string]*bintree{}},
+	}},
+	"sqlite3": &bintree{nil, map[string]*bintree{
+		"sqlite3_01_init.sql": &bintree{sqlite3Sqlite3_01_initSql, map[string]*bintree{}},
+		"sqlite3_02_trustline_authorization.sql": &bintree{sqlite3Sqlite3_02_trustline_authorizationSql, map[string]*bintree{}},
+		"sqlite3_03_hook_delivery_operation_id.sql": &bintree{sqlite3Sqlite3_03_hook_delivery_operation_idSql, map[string]*bintree{}},
+		"sqlite3_04_sent_transaction_idempotency_key.sql": &bintree{sqlite3Sqlite3_04_sent_transaction_idempotency_keySql, map[string]*bintree{}},
+		"sqlite3_05_pending_transaction_id.sql": &bintree{sqlite3Sqlite3_05_pending_transaction_idSql, map[string]*bintree{}},
+		"sqlite3_06_sent_transaction_retry_of.sql": &bintree{sqlite3Sqlite3_06_sent_transaction_retry_ofSql, map[string]*bintree{}},
 	}},
 }}
 
@@ -260,3 +1043,4 @@ func _filePath(dir, name string) string {
 	cannonicalName := strings.Replace(name, "\\", "/", -1)
 	return filepath.Join(append([]string{dir}, strings.Split(cannonicalName, "/")...)...)
 }
+
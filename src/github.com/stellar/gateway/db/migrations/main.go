@@ -6,7 +6,7 @@ import (
 	"github.com/rubenv/sql-migrate"
 )
 
-// go-bindata -ignore .+\.go$ -pkg migrations -o bindata.go ./mysql ./postgres
+// go-bindata -ignore .+\.go$ -pkg migrations -o bindata.go ./mysql ./postgres ./sqlite3
 
 type MigrationManager struct {
 	db     *sqlx.DB
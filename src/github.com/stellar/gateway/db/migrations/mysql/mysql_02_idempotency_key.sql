@@ -0,0 +1,16 @@
+-- +migrate Up
+CREATE TABLE `IdempotencyKey` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `idempotency_key` varchar(255) NOT NULL,
+  `request_hash` varchar(64) NOT NULL,
+  `status` varchar(10) NOT NULL,
+  `response_code` int(11) DEFAULT NULL,
+  `response_body` text DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  `completed_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idempotency_key` (`idempotency_key`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+DROP TABLE `IdempotencyKey`;
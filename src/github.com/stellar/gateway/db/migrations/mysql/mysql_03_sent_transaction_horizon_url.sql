@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE `SentTransaction` ADD `horizon_url` varchar(255) DEFAULT NULL;
+
+-- +migrate Down
+ALTER TABLE `SentTransaction` DROP COLUMN `horizon_url`;
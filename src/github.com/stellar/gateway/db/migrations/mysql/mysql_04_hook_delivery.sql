@@ -0,0 +1,19 @@
+-- +migrate Up
+CREATE TABLE `HookDelivery` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `hook` varchar(20) NOT NULL,
+  `url` varchar(255) NOT NULL,
+  `payload` text NOT NULL,
+  `operation_id` varchar(255) NOT NULL,
+  `status` varchar(10) NOT NULL,
+  `attempts` int(11) NOT NULL,
+  `next_attempt_at` datetime NOT NULL,
+  `last_error` text DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  `delivered_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `status_next_attempt_at` (`status`, `next_attempt_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+DROP TABLE `HookDelivery`;
@@ -0,0 +1,9 @@
+-- +migrate Up
+-- Remove duplicates saved before the index was added, keeping the newest row
+DELETE older FROM `ReceivedPayment` older
+  JOIN `ReceivedPayment` newer ON older.`operation_id` = newer.`operation_id` AND older.`id` < newer.`id`;
+
+ALTER TABLE `ReceivedPayment` ADD UNIQUE KEY `operation_id` (`operation_id`);
+
+-- +migrate Down
+ALTER TABLE `ReceivedPayment` DROP INDEX `operation_id`;
@@ -0,0 +1,25 @@
+-- +migrate Up
+ALTER TABLE `ReceivedPayment`
+  ADD `transaction_hash` varchar(64) NOT NULL DEFAULT '',
+  ADD `from_account` varchar(56) NOT NULL DEFAULT '',
+  ADD `amount` varchar(255) NOT NULL DEFAULT '',
+  ADD `asset_code` varchar(12) NOT NULL DEFAULT '',
+  ADD `asset_issuer` varchar(56) NOT NULL DEFAULT '',
+  ADD `memo_type` varchar(10) NOT NULL DEFAULT '',
+  ADD `memo` varchar(255) NOT NULL DEFAULT '',
+  ADD `hook_status_code` int(11) DEFAULT NULL,
+  ADD `hook_attempts` int(11) NOT NULL DEFAULT 0,
+  ADD KEY `memo` (`memo`);
+
+-- +migrate Down
+ALTER TABLE `ReceivedPayment`
+  DROP INDEX `memo`,
+  DROP `transaction_hash`,
+  DROP `from_account`,
+  DROP `amount`,
+  DROP `asset_code`,
+  DROP `asset_issuer`,
+  DROP `memo_type`,
+  DROP `memo`,
+  DROP `hook_status_code`,
+  DROP `hook_attempts`;
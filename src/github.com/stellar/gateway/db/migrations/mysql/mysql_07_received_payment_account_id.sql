@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE `ReceivedPayment`
+  ADD `account_id` varchar(56) NOT NULL DEFAULT '',
+  ADD KEY `account_id` (`account_id`);
+
+-- +migrate Down
+ALTER TABLE `ReceivedPayment`
+  DROP INDEX `account_id`,
+  DROP `account_id`;
@@ -0,0 +1,12 @@
+-- +migrate Up
+CREATE TABLE `StreamCursor` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `name` varchar(100) NOT NULL,
+  `paging_token` varchar(50) NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `name` (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+DROP TABLE `StreamCursor`;
@@ -0,0 +1,10 @@
+-- +migrate Up
+-- Start payment streams from the newest payment saved for every receiving
+-- account. Payments saved without account ID are handled by the listener.
+INSERT INTO `StreamCursor` (`name`, `paging_token`, `updated_at`)
+  SELECT CONCAT('payments:', `account_id`), CAST(MAX(CAST(`paging_token` AS UNSIGNED)) AS CHAR), UTC_TIMESTAMP()
+  FROM `ReceivedPayment`
+  WHERE `account_id` != '' AND CONCAT('payments:', `account_id`) NOT IN (SELECT `name` FROM `StreamCursor`)
+  GROUP BY `account_id`;
+
+-- +migrate Down
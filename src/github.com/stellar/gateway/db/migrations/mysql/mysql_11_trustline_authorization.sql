@@ -0,0 +1,26 @@
+-- +migrate Up
+CREATE TABLE `TrustlineAuthorization` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `operation_id` varchar(255) NOT NULL,
+  `account_id` varchar(56) NOT NULL,
+  `asset_code` varchar(12) NOT NULL,
+  `status` varchar(10) NOT NULL,
+  `reason` varchar(255) DEFAULT NULL,
+  `transaction_hash` varchar(64) DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `operation_id` (`operation_id`),
+  KEY `account_id` (`account_id`, `id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE `TrustlineAllowlist` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `account_id` varchar(56) NOT NULL,
+  `asset_code` varchar(12) NOT NULL DEFAULT '',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `account_id_asset_code` (`account_id`, `asset_code`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+DROP TABLE `TrustlineAuthorization`;
+DROP TABLE `TrustlineAllowlist`;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE `HookDelivery`
+  ADD KEY `operation_id` (`operation_id`);
+
+-- +migrate Down
+ALTER TABLE `HookDelivery`
+  DROP INDEX `operation_id`;
@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE `SentTransaction`
+  ADD `idempotency_key` varchar(255) DEFAULT NULL,
+  ADD KEY `idempotency_key` (`idempotency_key`);
+
+-- +migrate Down
+ALTER TABLE `SentTransaction`
+  DROP INDEX `idempotency_key`,
+  DROP `idempotency_key`;
@@ -0,0 +1,17 @@
+-- +migrate Up
+ALTER TABLE `ReceivedPayment`
+  ADD `refund_transaction_id` int(11) DEFAULT NULL,
+  ADD KEY `refund_transaction_id` (`refund_transaction_id`);
+
+ALTER TABLE `TrustlineAuthorization`
+  ADD `transaction_id` int(11) DEFAULT NULL,
+  ADD KEY `transaction_id` (`transaction_id`);
+
+-- +migrate Down
+ALTER TABLE `TrustlineAuthorization`
+  DROP INDEX `transaction_id`,
+  DROP `transaction_id`;
+
+ALTER TABLE `ReceivedPayment`
+  DROP INDEX `refund_transaction_id`,
+  DROP `refund_transaction_id`;
@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE `SentTransaction`
+  ADD `retry_of` int(11) DEFAULT NULL,
+  ADD KEY `retry_of` (`retry_of`);
+
+-- +migrate Down
+ALTER TABLE `SentTransaction`
+  DROP INDEX `retry_of`,
+  DROP `retry_of`;
@@ -0,0 +1,16 @@
+-- +migrate Up
+CREATE TABLE IdempotencyKey (
+  id serial,
+  idempotency_key varchar(255) NOT NULL,
+  request_hash varchar(64) NOT NULL,
+  status varchar(10) NOT NULL,
+  response_code integer DEFAULT NULL,
+  response_body text DEFAULT NULL,
+  created_at timestamp NOT NULL,
+  completed_at timestamp DEFAULT NULL,
+  PRIMARY KEY (id),
+  UNIQUE (idempotency_key)
+);
+
+-- +migrate Down
+DROP TABLE IdempotencyKey;
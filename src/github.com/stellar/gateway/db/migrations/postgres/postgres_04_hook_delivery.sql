@@ -0,0 +1,20 @@
+-- +migrate Up
+CREATE TABLE HookDelivery (
+  id serial,
+  hook varchar(20) NOT NULL,
+  url varchar(255) NOT NULL,
+  payload text NOT NULL,
+  operation_id varchar(255) NOT NULL,
+  status varchar(10) NOT NULL,
+  attempts integer NOT NULL,
+  next_attempt_at timestamp NOT NULL,
+  last_error text DEFAULT NULL,
+  created_at timestamp NOT NULL,
+  delivered_at timestamp DEFAULT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX hookdelivery_status_next_attempt_at ON HookDelivery (status, next_attempt_at);
+
+-- +migrate Down
+DROP TABLE HookDelivery;
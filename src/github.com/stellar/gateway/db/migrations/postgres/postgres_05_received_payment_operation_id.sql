@@ -0,0 +1,10 @@
+-- +migrate Up
+-- Remove duplicates saved before the index was added, keeping the newest row
+DELETE FROM ReceivedPayment older
+  USING ReceivedPayment newer
+  WHERE older.operation_id = newer.operation_id AND older.id < newer.id;
+
+CREATE UNIQUE INDEX receivedpayment_operation_id ON ReceivedPayment (operation_id);
+
+-- +migrate Down
+DROP INDEX receivedpayment_operation_id;
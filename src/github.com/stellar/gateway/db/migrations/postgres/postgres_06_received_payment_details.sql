@@ -0,0 +1,27 @@
+-- +migrate Up
+ALTER TABLE ReceivedPayment
+  ADD transaction_hash varchar(64) NOT NULL DEFAULT '',
+  ADD from_account varchar(56) NOT NULL DEFAULT '',
+  ADD amount varchar(255) NOT NULL DEFAULT '',
+  ADD asset_code varchar(12) NOT NULL DEFAULT '',
+  ADD asset_issuer varchar(56) NOT NULL DEFAULT '',
+  ADD memo_type varchar(10) NOT NULL DEFAULT '',
+  ADD memo varchar(255) NOT NULL DEFAULT '',
+  ADD hook_status_code integer DEFAULT NULL,
+  ADD hook_attempts integer NOT NULL DEFAULT 0;
+
+CREATE INDEX receivedpayment_memo ON ReceivedPayment (memo);
+
+-- +migrate Down
+DROP INDEX receivedpayment_memo;
+
+ALTER TABLE ReceivedPayment
+  DROP transaction_hash,
+  DROP from_account,
+  DROP amount,
+  DROP asset_code,
+  DROP asset_issuer,
+  DROP memo_type,
+  DROP memo,
+  DROP hook_status_code,
+  DROP hook_attempts;
@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE ReceivedPayment ADD account_id varchar(56) NOT NULL DEFAULT '';
+
+CREATE INDEX receivedpayment_account_id ON ReceivedPayment (account_id, id);
+
+-- +migrate Down
+DROP INDEX receivedpayment_account_id;
+
+ALTER TABLE ReceivedPayment DROP account_id;
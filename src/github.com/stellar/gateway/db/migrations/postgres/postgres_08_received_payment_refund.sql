@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE ReceivedPayment
+  ADD refund_status varchar(10) DEFAULT NULL,
+  ADD refund_transaction_hash varchar(64) DEFAULT NULL;
+
+-- +migrate Down
+ALTER TABLE ReceivedPayment
+  DROP refund_status,
+  DROP refund_transaction_hash;
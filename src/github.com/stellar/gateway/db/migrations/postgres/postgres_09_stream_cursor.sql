@@ -0,0 +1,13 @@
+-- +migrate Up
+CREATE TABLE StreamCursor (
+  id serial,
+  name varchar(100) NOT NULL,
+  paging_token varchar(50) NOT NULL,
+  updated_at timestamp NOT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX streamcursor_name ON StreamCursor (name);
+
+-- +migrate Down
+DROP TABLE StreamCursor;
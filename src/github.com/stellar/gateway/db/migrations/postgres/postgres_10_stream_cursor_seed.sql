@@ -0,0 +1,10 @@
+-- +migrate Up
+-- Start payment streams from the newest payment saved for every receiving
+-- account. Payments saved without account ID are handled by the listener.
+INSERT INTO StreamCursor (name, paging_token, updated_at)
+  SELECT 'payments:' || account_id, CAST(MAX(CAST(paging_token AS bigint)) AS varchar), NOW() AT TIME ZONE 'UTC'
+  FROM ReceivedPayment
+  WHERE account_id != '' AND 'payments:' || account_id NOT IN (SELECT name FROM StreamCursor)
+  GROUP BY account_id;
+
+-- +migrate Down
@@ -0,0 +1,28 @@
+-- +migrate Up
+CREATE TABLE TrustlineAuthorization (
+  id serial,
+  operation_id varchar(255) NOT NULL,
+  account_id varchar(56) NOT NULL,
+  asset_code varchar(12) NOT NULL,
+  status varchar(10) NOT NULL,
+  reason varchar(255) DEFAULT NULL,
+  transaction_hash varchar(64) DEFAULT NULL,
+  created_at timestamp NOT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX trustlineauthorization_operation_id ON TrustlineAuthorization (operation_id);
+CREATE INDEX trustlineauthorization_account_id ON TrustlineAuthorization (account_id, id);
+
+CREATE TABLE TrustlineAllowlist (
+  id serial,
+  account_id varchar(56) NOT NULL,
+  asset_code varchar(12) NOT NULL DEFAULT '',
+  PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX trustlineallowlist_account_id_asset_code ON TrustlineAllowlist (account_id, asset_code);
+
+-- +migrate Down
+DROP TABLE TrustlineAuthorization;
+DROP TABLE TrustlineAllowlist;
@@ -0,0 +1,17 @@
+-- +migrate Up
+ALTER TABLE ReceivedPayment ADD refund_transaction_id integer DEFAULT NULL;
+
+CREATE INDEX receivedpayment_refund_transaction_id ON ReceivedPayment (refund_transaction_id);
+
+ALTER TABLE TrustlineAuthorization ADD transaction_id integer DEFAULT NULL;
+
+CREATE INDEX trustlineauthorization_transaction_id ON TrustlineAuthorization (transaction_id);
+
+-- +migrate Down
+DROP INDEX trustlineauthorization_transaction_id;
+
+ALTER TABLE TrustlineAuthorization DROP transaction_id;
+
+DROP INDEX receivedpayment_refund_transaction_id;
+
+ALTER TABLE ReceivedPayment DROP refund_transaction_id;
@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE SentTransaction ADD retry_of integer DEFAULT NULL;
+
+CREATE INDEX senttransaction_retry_of ON SentTransaction (retry_of);
+
+-- +migrate Down
+DROP INDEX senttransaction_retry_of;
+
+ALTER TABLE SentTransaction DROP retry_of;
@@ -0,0 +1,82 @@
+-- +migrate Up
+-- SQLite schema is created at once, it's equal to postgres and mysql schema
+-- after postgres_10_stream_cursor_seed.sql migration.
+CREATE TABLE ReceivedPayment (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  operation_id varchar(255) NOT NULL,
+  processed_at timestamp NOT NULL,
+  paging_token varchar(255) NOT NULL,
+  status varchar(255) NOT NULL,
+  transaction_hash varchar(64) NOT NULL DEFAULT '',
+  from_account varchar(56) NOT NULL DEFAULT '',
+  amount varchar(255) NOT NULL DEFAULT '',
+  asset_code varchar(12) NOT NULL DEFAULT '',
+  asset_issuer varchar(56) NOT NULL DEFAULT '',
+  memo_type varchar(10) NOT NULL DEFAULT '',
+  memo varchar(255) NOT NULL DEFAULT '',
+  hook_status_code integer DEFAULT NULL,
+  hook_attempts integer NOT NULL DEFAULT 0,
+  account_id varchar(56) NOT NULL DEFAULT '',
+  refund_status varchar(10) DEFAULT NULL,
+  refund_transaction_hash varchar(64) DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX receivedpayment_operation_id ON ReceivedPayment (operation_id);
+CREATE INDEX receivedpayment_memo ON ReceivedPayment (memo);
+CREATE INDEX receivedpayment_account_id ON ReceivedPayment (account_id, id);
+
+CREATE TABLE SentTransaction (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  status varchar(10) NOT NULL,
+  source varchar(56) NOT NULL,
+  submitted_at timestamp NOT NULL,
+  succeeded_at timestamp DEFAULT NULL,
+  ledger bigint DEFAULT NULL,
+  envelope_xdr text NOT NULL,
+  result_xdr varchar(255) DEFAULT NULL,
+  horizon_url varchar(255) DEFAULT NULL
+);
+
+CREATE TABLE IdempotencyKey (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  idempotency_key varchar(255) NOT NULL,
+  request_hash varchar(64) NOT NULL,
+  status varchar(10) NOT NULL,
+  response_code integer DEFAULT NULL,
+  response_body text DEFAULT NULL,
+  created_at timestamp NOT NULL,
+  completed_at timestamp DEFAULT NULL,
+  UNIQUE (idempotency_key)
+);
+
+CREATE TABLE HookDelivery (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  hook varchar(20) NOT NULL,
+  url varchar(255) NOT NULL,
+  payload text NOT NULL,
+  operation_id varchar(255) NOT NULL,
+  status varchar(10) NOT NULL,
+  attempts integer NOT NULL,
+  next_attempt_at timestamp NOT NULL,
+  last_error text DEFAULT NULL,
+  created_at timestamp NOT NULL,
+  delivered_at timestamp DEFAULT NULL
+);
+
+CREATE INDEX hookdelivery_status_next_attempt_at ON HookDelivery (status, next_attempt_at);
+
+CREATE TABLE StreamCursor (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  name varchar(100) NOT NULL,
+  paging_token varchar(50) NOT NULL,
+  updated_at timestamp NOT NULL
+);
+
+CREATE UNIQUE INDEX streamcursor_name ON StreamCursor (name);
+
+-- +migrate Down
+DROP TABLE ReceivedPayment;
+DROP TABLE SentTransaction;
+DROP TABLE IdempotencyKey;
+DROP TABLE HookDelivery;
+DROP TABLE StreamCursor;
@@ -0,0 +1,5 @@
+-- +migrate Up
+CREATE INDEX hookdelivery_operation_id ON HookDelivery (operation_id);
+
+-- +migrate Down
+DROP INDEX hookdelivery_operation_id;
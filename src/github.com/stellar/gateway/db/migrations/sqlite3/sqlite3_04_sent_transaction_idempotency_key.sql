@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE SentTransaction ADD idempotency_key varchar(255) DEFAULT NULL;
+
+CREATE INDEX senttransaction_idempotency_key ON SentTransaction (idempotency_key);
+
+-- +migrate Down
+DROP INDEX senttransaction_idempotency_key;
+
+ALTER TABLE SentTransaction DROP idempotency_key;
@@ -1,6 +1,10 @@
 package db
 
 import (
+	"database/sql"
+	"strings"
+	"time"
+
 	"github.com/Sirupsen/logrus"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
@@ -9,7 +13,72 @@ import (
 )
 
 type RepositoryInterface interface {
-	GetLastCursorValue() (cursor *string, err error)
+	GetLastCursorValue(accountId string) (cursor *string, err error)
+	GetStreamCursor(name string) (cursor *StreamCursor, err error)
+	GetReceivedPaymentByOperationId(operationId string) (payment *ReceivedPayment, err error)
+	GetReceivedPayments(filter ReceivedPaymentsFilter) (payments []ReceivedPayment, err error)
+	GetSendingTransactions(submittedBefore time.Time) (transactions []SentTransaction, err error)
+	GetIdempotencyKey(key string) (idempotencyKey *IdempotencyKey, err error)
+	GetSentTransactionById(id int64) (transaction *SentTransaction, err error)
+	GetSentTransactions(filter SentTransactionsFilter) (transactions []SentTransaction, err error)
+	GetPendingHookDeliveries(before time.Time, limit int) (deliveries []HookDelivery, err error)
+	GetHookDeliveryById(id int64) (delivery *HookDelivery, err error)
+	GetHookDeliveries(filter HookDeliveriesFilter) (deliveries []HookDelivery, err error)
+	GetTrustlineAuthorizationByOperationId(operationId string) (authorization *TrustlineAuthorization, err error)
+	GetTrustlineAuthorizations(filter TrustlineAuthorizationsFilter) (authorizations []TrustlineAuthorization, err error)
+	IsTrustlineAllowed(accountId, assetCode string) (allowed bool, err error)
+}
+
+// ReceivedPaymentsFilter contains conditions used by GetReceivedPayments.
+// Empty fields are ignored.
+type ReceivedPaymentsFilter struct {
+	AccountId   string
+	Memo        string
+	AssetCode   string
+	AssetIssuer string
+	From        string
+	// RefundTransactionId is an id of SentTransaction of the refund
+	RefundTransactionId int64
+	// Since and Until limit processed_at (Until is exclusive)
+	Since *time.Time
+	Until *time.Time
+	// Cursor is an id of the last payment on the previous page
+	Cursor int64
+	Limit  int
+}
+
+// SentTransactionsFilter contains conditions used by GetSentTransactions.
+// Empty fields are ignored.
+type SentTransactionsFilter struct {
+	Status         string
+	Source         string
+	IdempotencyKey string
+	Since          *time.Time
+	// Cursor is an id of the last transaction on the previous page
+	Cursor int64
+	Limit  int
+}
+
+// HookDeliveriesFilter contains conditions used by GetHookDeliveries.
+// Empty fields are ignored.
+type HookDeliveriesFilter struct {
+	Status      string
+	OperationId string
+	// Cursor is an id of the last delivery on the previous page
+	Cursor int64
+	Limit  int
+}
+
+// TrustlineAuthorizationsFilter contains conditions used by
+// GetTrustlineAuthorizations. Empty fields are ignored.
+type TrustlineAuthorizationsFilter struct {
+	AccountId string
+	Status    string
+	// TransactionId is an id of SentTransaction of allow_trust
+	TransactionId int64
+	// Cursor is an id of the last authorization on the previous page
+	Cursor int64
+	Limit  int
 }
 
 type Repository struct {
@@ -25,9 +94,14 @@ func NewRepository(dbType string, url string) (r Repository, err error) {
 	return
 }
 
-func (r Repository) GetLastCursorValue() (cursor *string, err error) {
+// GetLastCursorValue returns paging token of the last payment saved for
+// a given receiving account or nil if there are no payments. Payment streams
+// are restarted from StreamCursor, this is only used for payments saved
+// before StreamCursor was added.
+func (r Repository) GetLastCursorValue(accountId string) (cursor *string, err error) {
 	var receivedPayment ReceivedPayment
-	err = r.db.Get(&receivedPayment, "SELECT * FROM ReceivedPayment ORDER BY id DESC LIMIT 1")
+	query := r.db.Rebind("SELECT * FROM ReceivedPayment WHERE account_id = ? ORDER BY id DESC LIMIT 1")
+	err = r.db.Get(&receivedPayment, query, accountId)
 	if err != nil {
 		if err.Error() == "sql: no rows in result set" {
 			return nil, nil
@@ -37,3 +111,243 @@ func (r Repository) GetLastCursorValue() (cursor *string, err error) {
 	}
 	return &receivedPayment.PagingToken, nil
 }
+
+// GetStreamCursor returns StreamCursor with a given name or nil if it does
+// not exist.
+func (r Repository) GetStreamCursor(name string) (cursor *StreamCursor, err error) {
+	cursor = &StreamCursor{}
+	err = r.db.Get(cursor, r.db.Rebind("SELECT * FROM StreamCursor WHERE name = ?"), name)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// GetReceivedPaymentByOperationId returns ReceivedPayment with a given
+// operation id or nil if it does not exist.
+func (r Repository) GetReceivedPaymentByOperationId(operationId string) (payment *ReceivedPayment, err error) {
+	payment = &ReceivedPayment{}
+	err = r.db.Get(payment, r.db.Rebind("SELECT * FROM ReceivedPayment WHERE operation_id = ?"), operationId)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// GetReceivedPayments returns ReceivedPayments matching filter ordered by id.
+func (r Repository) GetReceivedPayments(filter ReceivedPaymentsFilter) (payments []ReceivedPayment, err error) {
+	conditions := []string{"id > ?"}
+	args := []interface{}{filter.Cursor}
+
+	if filter.AccountId != "" {
+		conditions = append(conditions, "account_id = ?")
+		args = append(args, filter.AccountId)
+	}
+
+	if filter.Memo != "" {
+		conditions = append(conditions, "memo = ?")
+		args = append(args, filter.Memo)
+	}
+
+	if filter.AssetCode != "" {
+		conditions = append(conditions, "asset_code = ?")
+		args = append(args, filter.AssetCode)
+	}
+
+	if filter.AssetIssuer != "" {
+		conditions = append(conditions, "asset_issuer = ?")
+		args = append(args, filter.AssetIssuer)
+	}
+
+	if filter.From != "" {
+		conditions = append(conditions, "from_account = ?")
+		args = append(args, filter.From)
+	}
+
+	if filter.RefundTransactionId != 0 {
+		conditions = append(conditions, "refund_transaction_id = ?")
+		args = append(args, filter.RefundTransactionId)
+	}
+
+	if filter.Since != nil {
+		conditions = append(conditions, "processed_at >= ?")
+		args = append(args, *filter.Since)
+	}
+
+	if filter.Until != nil {
+		conditions = append(conditions, "processed_at < ?")
+		args = append(args, *filter.Until)
+	}
+
+	query := "SELECT * FROM ReceivedPayment WHERE " + strings.Join(conditions, " AND ") + " ORDER BY id ASC LIMIT ?"
+	args = append(args, filter.Limit)
+
+	err = r.db.Select(&payments, r.db.Rebind(query), args...)
+	return
+}
+
+// GetSendingTransactions returns transactions that are still in "sending" state
+// and were submitted before submittedBefore, oldest first.
+func (r Repository) GetSendingTransactions(submittedBefore time.Time) (transactions []SentTransaction, err error) {
+	query := r.db.Rebind("SELECT * FROM SentTransaction WHERE status = 'sending' AND submitted_at < ? ORDER BY id ASC")
+	err = r.db.Select(&transactions, query, submittedBefore)
+	return
+}
+
+// GetIdempotencyKey returns IdempotencyKey with a given key or nil if it does not exist.
+func (r Repository) GetIdempotencyKey(key string) (idempotencyKey *IdempotencyKey, err error) {
+	idempotencyKey = &IdempotencyKey{}
+	err = r.db.Get(idempotencyKey, r.db.Rebind("SELECT * FROM IdempotencyKey WHERE idempotency_key = ?"), key)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// GetSentTransactionById returns SentTransaction with a given id or nil if it does not exist.
+func (r Repository) GetSentTransactionById(id int64) (transaction *SentTransaction, err error) {
+	transaction = &SentTransaction{}
+	err = r.db.Get(transaction, r.db.Rebind("SELECT * FROM SentTransaction WHERE id = ?"), id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// GetSentTransactions returns SentTransactions matching filter ordered by id.
+func (r Repository) GetSentTransactions(filter SentTransactionsFilter) (transactions []SentTransaction, err error) {
+	conditions := []string{"id > ?"}
+	args := []interface{}{filter.Cursor}
+
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+
+	if filter.Source != "" {
+		conditions = append(conditions, "source = ?")
+		args = append(args, filter.Source)
+	}
+
+	if filter.IdempotencyKey != "" {
+		conditions = append(conditions, "idempotency_key = ?")
+		args = append(args, filter.IdempotencyKey)
+	}
+
+	if filter.Since != nil {
+		conditions = append(conditions, "submitted_at >= ?")
+		args = append(args, *filter.Since)
+	}
+
+	query := "SELECT * FROM SentTransaction WHERE " + strings.Join(conditions, " AND ") + " ORDER BY id ASC LIMIT ?"
+	args = append(args, filter.Limit)
+
+	err = r.db.Select(&transactions, r.db.Rebind(query), args...)
+	return
+}
+
+// GetPendingHookDeliveries returns pending HookDeliveries that should be
+// sent before a given time ordered by id.
+func (r Repository) GetPendingHookDeliveries(before time.Time, limit int) (deliveries []HookDelivery, err error) {
+	query := r.db.Rebind("SELECT * FROM HookDelivery WHERE status = 'pending' AND next_attempt_at <= ? ORDER BY id ASC LIMIT ?")
+	err = r.db.Select(&deliveries, query, before, limit)
+	return
+}
+
+// GetHookDeliveryById returns HookDelivery with a given id or nil if it does not exist.
+func (r Repository) GetHookDeliveryById(id int64) (delivery *HookDelivery, err error) {
+	delivery = &HookDelivery{}
+	err = r.db.Get(delivery, r.db.Rebind("SELECT * FROM HookDelivery WHERE id = ?"), id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// GetHookDeliveries returns HookDeliveries matching filter ordered by id.
+func (r Repository) GetHookDeliveries(filter HookDeliveriesFilter) (deliveries []HookDelivery, err error) {
+	conditions := []string{"id > ?"}
+	args := []interface{}{filter.Cursor}
+
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+
+	if filter.OperationId != "" {
+		conditions = append(conditions, "operation_id = ?")
+		args = append(args, filter.OperationId)
+	}
+
+	query := "SELECT * FROM HookDelivery WHERE " + strings.Join(conditions, " AND ") + " ORDER BY id ASC LIMIT ?"
+	args = append(args, filter.Limit)
+
+	err = r.db.Select(&deliveries, r.db.Rebind(query), args...)
+	return
+}
+
+// GetTrustlineAuthorizationByOperationId returns TrustlineAuthorization of
+// a given change_trust operation or nil if it does not exist.
+func (r Repository) GetTrustlineAuthorizationByOperationId(operationId string) (authorization *TrustlineAuthorization, err error) {
+	authorization = &TrustlineAuthorization{}
+	err = r.db.Get(authorization, r.db.Rebind("SELECT * FROM TrustlineAuthorization WHERE operation_id = ?"), operationId)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// GetTrustlineAuthorizations returns TrustlineAuthorizations matching filter
+// ordered by id.
+func (r Repository) GetTrustlineAuthorizations(filter TrustlineAuthorizationsFilter) (authorizations []TrustlineAuthorization, err error) {
+	conditions := []string{"id > ?"}
+	args := []interface{}{filter.Cursor}
+
+	if filter.AccountId != "" {
+		conditions = append(conditions, "account_id = ?")
+		args = append(args, filter.AccountId)
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+	if filter.TransactionId != 0 {
+		conditions = append(conditions, "transaction_id = ?")
+		args = append(args, filter.TransactionId)
+	}
+
+	query := "SELECT * FROM TrustlineAuthorization WHERE " + strings.Join(conditions, " AND ") + " ORDER BY id ASC LIMIT ?"
+	args = append(args, filter.Limit)
+
+	err = r.db.Select(&authorizations, r.db.Rebind(query), args...)
+	return
+}
+
+// IsTrustlineAllowed returns true when TrustlineAllowlist contains a given
+// account with a given asset code (or with empty asset code matching all
+// assets).
+func (r Repository) IsTrustlineAllowed(accountId, assetCode string) (allowed bool, err error) {
+	var count int
+	query := "SELECT COUNT(*) FROM TrustlineAllowlist WHERE account_id = ? AND (asset_code = ? OR asset_code = '')"
+	err = r.db.Get(&count, r.db.Rebind(query), accountId, assetCode)
+	allowed = count > 0
+	return
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	log "github.com/Sirupsen/logrus"
+	"net/http"
+	"strings"
+
+	"github.com/stellar/gateway/config"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/submitter"
+	"github.com/stellar/go-stellar-base/amount"
+	b "github.com/stellar/go-stellar-base/build"
+	"github.com/stellar/go-stellar-base/strkey"
+	"github.com/stellar/go-stellar-base/xdr"
+)
+
+// isDryRun returns true when transaction should be built and signed but not
+// submitted (`dry_run=true` param or `dry_run` config param).
+func (rh *RequestHandler) isDryRun(r *http.Request) bool {
+	return isDryRunRequest(rh.Config, r)
+}
+
+// isDryRunRequest is isDryRun for code without RequestHandler (middlewares).
+func isDryRunRequest(config *config.Config, r *http.Request) bool {
+	return config.DryRun || r.PostFormValue("dry_run") == "true"
+}
+
+// dryRun signs transaction using TransactionSubmitter without submitting it.
+func (rh *RequestHandler) dryRun(w http.ResponseWriter, r *http.Request, source string, operation, memo interface{}) {
+	txeB64, err := rh.TransactionSubmitter.SignTransaction(source, operation, memo)
+	if err != nil {
+		log.Print("Error signing transaction ", err)
+		errorSubmittingTransaction(w, err)
+		return
+	}
+
+	rh.writeDryRunResponse(w, r, txeB64)
+}
+
+// writeDryRunResponse checks if destination accounts exist and trust sent
+// assets and writes a summary of the signed transaction.
+func (rh *RequestHandler) writeDryRunResponse(w http.ResponseWriter, r *http.Request, txeB64 string) {
+	var envelope xdr.TransactionEnvelope
+	err := xdr.SafeUnmarshalBase64(txeB64, &envelope)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Cannot decode transaction envelope")
+		errorServerError(w)
+		return
+	}
+
+	hash, err := submitter.TransactionHash(&envelope.Tx, b.Network{rh.Config.NetworkPassphrase})
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Cannot compute transaction hash")
+		errorServerError(w)
+		return
+	}
+
+	response := DryRunResponse{
+		TransactionEnvelope: txeB64,
+		Hash:                hash,
+		Fee:                 uint32(envelope.Tx.Fee),
+		Operations:          []OperationSummary{},
+	}
+
+	transactionSource := accountIdAddress(envelope.Tx.SourceAccount)
+	for _, operation := range envelope.Tx.Operations {
+		summary := summarizeOperation(transactionSource, operation)
+
+		errorString, err := rh.checkOperation(r.Context(), summary)
+		if err != nil {
+			log.WithFields(log.Fields{"err": err}).Error("Error checking operation")
+			errorServerError(w)
+			return
+		}
+		if errorString != "" {
+			errorBadRequest(w, errorString)
+			return
+		}
+
+		response.Operations = append(response.Operations, summary)
+	}
+
+	json, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		errorServerError(w)
+		return
+	}
+
+	w.Write(json)
+}
+
+// checkOperation returns error response string when the operation would fail
+// because destination account does not exist or does not trust the asset.
+func (rh *RequestHandler) checkOperation(ctx context.Context, summary OperationSummary) (errorString string, err error) {
+	switch summary.Type {
+	case "payment":
+		destination, err := rh.Horizon.LoadAccount(ctx, summary.Destination)
+		if err == horizon.ErrNotFound {
+			return errorResponseString("payment_no_destination", "Destination account does not exist."), nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if summary.AssetCode != "" && summary.Destination != summary.AssetIssuer &&
+			!destination.HasTrustline(summary.AssetCode, summary.AssetIssuer) {
+			return errorResponseString("payment_no_trust", "Destination missing a trust line for asset."), nil
+		}
+	case "allow_trust":
+		trustor, err := rh.Horizon.LoadAccount(ctx, summary.Trustor)
+		if err != nil && err != horizon.ErrNotFound {
+			return "", err
+		}
+		if err == horizon.ErrNotFound || !trustor.HasTrustline(summary.AssetCode, summary.Source) {
+			return errorResponseString("allow_trust_not_trustline", "Trustor does not have a trustline yet."), nil
+		}
+	}
+	return
+}
+
+func summarizeOperation(transactionSource string, operation xdr.Operation) (summary OperationSummary) {
+	summary.Source = transactionSource
+	if operation.SourceAccount != nil {
+		summary.Source = accountIdAddress(*operation.SourceAccount)
+	}
+
+	switch operation.Body.Type {
+	case xdr.OperationTypeCreateAccount:
+		op := operation.Body.MustCreateAccountOp()
+		summary.Type = "create_account"
+		summary.Destination = accountIdAddress(op.Destination)
+		summary.Amount = amount.String(op.StartingBalance)
+	case xdr.OperationTypePayment:
+		op := operation.Body.MustPaymentOp()
+		summary.Type = "payment"
+		summary.Destination = accountIdAddress(op.Destination)
+		summary.Amount = amount.String(op.Amount)
+		if op.Asset.Type != xdr.AssetTypeAssetTypeNative {
+			var assetType string
+			op.Asset.Extract(&assetType, &summary.AssetCode, &summary.AssetIssuer)
+		}
+	case xdr.OperationTypeAllowTrust:
+		op := operation.Body.MustAllowTrustOp()
+		summary.Type = "allow_trust"
+		summary.Trustor = accountIdAddress(op.Trustor)
+		authorize := op.Authorize
+		summary.Authorize = &authorize
+		if code, ok := op.Asset.GetAssetCode4(); ok {
+			summary.AssetCode = strings.TrimRight(string(code[:]), "\x00")
+		} else if code, ok := op.Asset.GetAssetCode12(); ok {
+			summary.AssetCode = strings.TrimRight(string(code[:]), "\x00")
+		}
+	default:
+		summary.Type = "unknown"
+	}
+	return
+}
+
+func accountIdAddress(accountId xdr.AccountId) string {
+	if accountId.Ed25519 == nil {
+		return ""
+	}
+	address, _ := strkey.Encode(strkey.VersionByteAccountID, accountId.Ed25519[:])
+	return address
+}
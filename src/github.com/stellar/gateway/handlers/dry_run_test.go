@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stellar/gateway/config"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRequestHandlerSendDryRun(t *testing.T) {
+	mockHorizon := new(mocks.MockHorizon)
+	mockTransactionSubmitter := new(mocks.MockTransactionSubmitter)
+
+	issuingAccountId := "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR"
+	destination := "GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632"
+
+	config := config.Config{
+		NetworkPassphrase: "Test SDF Network ; September 2015",
+		Assets:            []string{"USD", "EUR"},
+		Accounts: &config.Accounts{
+			IssuingAccountId: &issuingAccountId,
+		},
+	}
+
+	requestHandler := RequestHandler{
+		Config:               &config,
+		Horizon:              mockHorizon,
+		TransactionSubmitter: mockTransactionSubmitter,
+	}
+	testServer := httptest.NewServer(http.HandlerFunc(requestHandler.Send))
+	defer testServer.Close()
+
+	// 20 USD payment from GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR with sequence 101
+	envelopeXdr := "AAAAAPiPgLJeTTGvfJwXZLmaadaEOIofYnPmlu8OSIVyRFVHAAAAZAAAAAAAAABlAAAAAAAAAAAAAAABAAAAAAAAAAEAAAAA5IVbm6A8mbgc/apAizxmBf4zZmqbedR3Ke+MTa7pjVYAAAABVVNEAAAAAAD4j4CyXk0xr3ycF2S5mmnWhDiKH2Jz5pbvDkiFckRVRwAAAAAL68IAAAAAAAAAAAFyRFVHAAAAQMeX1GjreSNuVpZ9bWz7W4opM6M7gZTCMmK+y1QbcV1qt37NiT8duLcEct/5Wkv3A1A+Kfnd749vQWg1O/TAugM="
+
+	params := url.Values{
+		"destination": {destination},
+		"asset_code":  {"USD"},
+		"amount":      {"20"},
+		"dry_run":     {"true"},
+	}
+
+	Convey("Given send request with dry_run=true", t, func() {
+		mockTransactionSubmitter.On("SignTransaction", issuingAccountId, mock.Anything, nil).Return(envelopeXdr, nil).Once()
+
+		Convey("When destination trusts the asset", func() {
+			mockHorizon.On("LoadAccount", destination).Return(
+				horizon.AccountResponse{
+					AccountId: destination,
+					Balances: []horizon.AccountBalance{
+						{AssetType: "credit_alphanum4", AssetCode: "USD", AssetIssuer: issuingAccountId},
+					},
+				},
+				nil,
+			).Once()
+
+			Convey("it should return transaction summary without submitting it", func() {
+				statusCode, response := getResponse(testServer, params)
+				assert.Equal(t, 200, statusCode)
+
+				var dryRunResponse DryRunResponse
+				err := json.Unmarshal(response, &dryRunResponse)
+				assert.Nil(t, err)
+				assert.Equal(t, envelopeXdr, dryRunResponse.TransactionEnvelope)
+				assert.Equal(t, "743bf531b73efe71886606222fc072a4dd9a89ee6c963ecde99d5220c1490c06", dryRunResponse.Hash)
+				assert.Equal(t, uint32(100), dryRunResponse.Fee)
+				assert.Equal(t, []OperationSummary{
+					{
+						Type:        "payment",
+						Source:      issuingAccountId,
+						Destination: destination,
+						AssetCode:   "USD",
+						AssetIssuer: issuingAccountId,
+						Amount:      "20.0000000",
+					},
+				}, dryRunResponse.Operations)
+				mockHorizon.AssertExpectations(t)
+				mockTransactionSubmitter.AssertExpectations(t)
+			})
+		})
+
+		Convey("When destination does not trust the asset", func() {
+			mockHorizon.On("LoadAccount", destination).Return(horizon.AccountResponse{AccountId: destination}, nil).Once()
+
+			Convey("it should return error", func() {
+				statusCode, response := getResponse(testServer, params)
+				responseString := strings.TrimSpace(string(response))
+				assert.Equal(t, 400, statusCode)
+				assert.Equal(t, errorResponseString("payment_no_trust", "Destination missing a trust line for asset."), responseString)
+				mockHorizon.AssertExpectations(t)
+			})
+		})
+
+		Convey("When destination does not exist", func() {
+			mockHorizon.On("LoadAccount", destination).Return(horizon.AccountResponse{}, horizon.ErrNotFound).Once()
+
+			Convey("it should return error", func() {
+				statusCode, response := getResponse(testServer, params)
+				responseString := strings.TrimSpace(string(response))
+				assert.Equal(t, 400, statusCode)
+				assert.Equal(t, errorResponseString("payment_no_destination", "Destination account does not exist."), responseString)
+				mockHorizon.AssertExpectations(t)
+			})
+		})
+	})
+}
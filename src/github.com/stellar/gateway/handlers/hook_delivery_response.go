@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"time"
+)
+
+type HookDeliveryResponse struct {
+	Id            int64      `json:"id"`
+	Hook          string     `json:"hook"`
+	Url           string     `json:"url"`
+	Payload       string     `json:"payload"`
+	OperationId   string     `json:"operation_id"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	LastError     *string    `json:"last_error"`
+	CreatedAt     time.Time  `json:"created_at"`
+	DeliveredAt   *time.Time `json:"delivered_at"`
+}
+
+type HookDeliveriesPageResponse struct {
+	Deliveries []HookDeliveryResponse `json:"deliveries"`
+	// Cursor to use to get the next page
+	Cursor string `json:"cursor"`
+}
@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/stellar/gateway/config"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/submitter"
+	"github.com/stellar/go-stellar-base/build"
+	"github.com/stellar/go-stellar-base/xdr"
+)
+
+// IdempotencyProcessingTimeout is the time after which a key left in
+// "processing" state (ex. when the server was restarted during the request)
+// is resolved using transactions submitted by the request.
+var IdempotencyProcessingTimeout = 5 * time.Minute
+
+// IdempotencyMiddleware makes requests containing `idempotency_key` parameter
+// idempotent. The response of the first request with a given key is saved and
+// returned for every following request with the same key and parameters.
+func IdempotencyMiddleware(
+	config *config.Config,
+	repository db.RepositoryInterface,
+	entityManager db.EntityManagerInterface,
+	now func() time.Time,
+) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			key := r.PostFormValue("idempotency_key")
+			// Dry run responses are not saved so they can't be returned for real requests
+			if key == "" || isDryRunRequest(config, r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			requestHash := getRequestHash(r)
+
+			idempotencyKey, err := repository.GetIdempotencyKey(key)
+			if err != nil {
+				log.WithFields(log.Fields{"err": err}).Error("Error loading idempotency key")
+				errorServerError(w)
+				return
+			}
+
+			if idempotencyKey != nil {
+				if idempotencyKey.Status == "processing" &&
+					idempotencyKey.RequestHash == requestHash &&
+					now().Sub(idempotencyKey.CreatedAt) >= IdempotencyProcessingTimeout {
+					err = resolveIdempotencyKey(config, repository, entityManager, idempotencyKey)
+					if err != nil {
+						log.WithFields(log.Fields{"err": err, "key": key}).Error("Error resolving idempotency key")
+						errorServerError(w)
+						return
+					}
+				}
+				writeIdempotentResponse(w, idempotencyKey, requestHash)
+				return
+			}
+
+			idempotencyKey = &db.IdempotencyKey{
+				Key:         key,
+				RequestHash: requestHash,
+				Status:      "processing",
+				CreatedAt:   now(),
+			}
+			err = entityManager.Persist(idempotencyKey)
+			if err != nil {
+				// Concurrent request with the same key could have been saved in the meantime
+				var existing *db.IdempotencyKey
+				existing, err = repository.GetIdempotencyKey(key)
+				if err != nil || existing == nil {
+					log.WithFields(log.Fields{"err": err}).Error("Error saving idempotency key")
+					errorServerError(w)
+					return
+				}
+				writeIdempotentResponse(w, existing, requestHash)
+				return
+			}
+
+			recorder := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+			// SentTransactions are linked to the key so it can be resolved
+			// when the response is not saved
+			next.ServeHTTP(recorder, r.WithContext(submitter.WithIdempotencyKey(r.Context(), key)))
+
+			if recorder.statusCode >= http.StatusInternalServerError {
+				// Transaction may still be applied (ex. after horizon timeout).
+				// Key stays in "processing" state and it's resolved from the
+				// transaction once TransactionRecoverer settles it.
+				transaction, err := getLastSentTransaction(repository, key)
+				if err != nil {
+					log.WithFields(log.Fields{"err": err, "key": key}).Error("Error loading transactions of idempotency key")
+					return
+				}
+				if transaction != nil && transaction.Status == "sending" {
+					log.WithFields(log.Fields{"key": key}).Info("Transaction is still sending, idempotent response not saved")
+					return
+				}
+			}
+
+			idempotencyKey.MarkCompleted(recorder.statusCode, recorder.body.String())
+			err = entityManager.Persist(idempotencyKey)
+			if err != nil {
+				log.WithFields(log.Fields{"err": err, "key": key}).Error("Error saving idempotent response")
+			}
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// resolveIdempotencyKey completes idempotencyKey left in "processing" state
+// with a response built from the last SentTransaction linked to it. Key stays
+// in "processing" state while the transaction is "sending" (it's resolved by
+// TransactionRecoverer).
+func resolveIdempotencyKey(
+	config *config.Config,
+	repository db.RepositoryInterface,
+	entityManager db.EntityManagerInterface,
+	idempotencyKey *db.IdempotencyKey,
+) (err error) {
+	transaction, err := getLastSentTransaction(repository, idempotencyKey.Key)
+	if err != nil {
+		return
+	}
+
+	if transaction == nil {
+		// Transaction is saved before submission so nothing was submitted
+		idempotencyKey.MarkCompleted(http.StatusInternalServerError, errorResponseString("request_failed", "Request failed before submitting a transaction. Send it again with a new idempotency_key."))
+		return entityManager.Persist(idempotencyKey)
+	}
+
+	switch transaction.Status {
+	case "success":
+		var envelope xdr.TransactionEnvelope
+		err = xdr.SafeUnmarshalBase64(transaction.EnvelopeXdr, &envelope)
+		if err != nil {
+			return
+		}
+
+		response := horizon.SubmitTransactionResponse{Ledger: transaction.Ledger}
+		response.Hash, err = submitter.TransactionHash(&envelope.Tx, build.Network{config.NetworkPassphrase})
+		if err != nil {
+			return
+		}
+
+		var body []byte
+		body, err = json.MarshalIndent(response, "", "  ")
+		if err != nil {
+			return
+		}
+		idempotencyKey.MarkCompleted(http.StatusOK, string(body))
+	case "failure":
+		// Result is not saved when the sequence number was consumed by
+		// another transaction
+		errors := &horizon.SubmitTransactionResponseError{TransactionErrorCode: "transaction_bad_seq"}
+		if transaction.ResultXdr != nil {
+			errors, err = horizon.DecodeTransactionResult(*transaction.ResultXdr)
+			if err != nil {
+				return
+			}
+		}
+		statusCode, responseString := transactionFailedResponse(errors)
+		idempotencyKey.MarkCompleted(statusCode, responseString+"\n")
+	default:
+		return
+	}
+
+	return entityManager.Persist(idempotencyKey)
+}
+
+// getLastSentTransaction returns the last SentTransaction linked to the
+// idempotency key or nil if none was saved. Previous transactions are
+// transaction_bad_seq retries.
+func getLastSentTransaction(repository db.RepositoryInterface, key string) (transaction *db.SentTransaction, err error) {
+	transactions, err := repository.GetSentTransactions(db.SentTransactionsFilter{
+		IdempotencyKey: key,
+		Limit:          100,
+	})
+	if err != nil || len(transactions) == 0 {
+		return
+	}
+	return &transactions[len(transactions)-1], nil
+}
+
+func writeIdempotentResponse(w http.ResponseWriter, idempotencyKey *db.IdempotencyKey, requestHash string) {
+	if idempotencyKey.RequestHash != requestHash {
+		errorBadRequest(w, errorResponseString("idempotency_key_reused", "idempotency_key has already been used with different parameters"))
+		return
+	}
+
+	if idempotencyKey.Status != "completed" || idempotencyKey.ResponseCode == nil || idempotencyKey.ResponseBody == nil {
+		http.Error(w, errorResponseString("request_in_progress", "Request with this idempotency_key is still being processed"), http.StatusConflict)
+		return
+	}
+
+	w.WriteHeader(*idempotencyKey.ResponseCode)
+	w.Write([]byte(*idempotencyKey.ResponseBody))
+}
+
+// getRequestHash returns a hash of request path and all POST params except
+// `idempotency_key` and API key.
+func getRequestHash(r *http.Request) string {
+	params := url.Values{}
+	for key, values := range r.PostForm {
+		if key == "idempotency_key" || key == "apiKey" {
+			continue
+		}
+		params[key] = values
+	}
+
+	// Encode sorts params by key
+	hash := sha256.Sum256([]byte(r.URL.Path + "?" + params.Encode()))
+	return hex.EncodeToString(hash[:])
+}
+
+// responseRecorder passes the response to the wrapped http.ResponseWriter and
+// records it so it can be saved.
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (rr *responseRecorder) WriteHeader(statusCode int) {
+	rr.statusCode = statusCode
+	rr.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rr *responseRecorder) Write(data []byte) (int, error) {
+	rr.body.Write(data)
+	return rr.ResponseWriter.Write(data)
+}
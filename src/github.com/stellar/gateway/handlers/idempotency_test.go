@@ -0,0 +1,304 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stellar/gateway/config"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/gateway/submitter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	mockRepository := new(mocks.MockRepository)
+	mockEntityManager := new(mocks.MockEntityManager)
+
+	var handlerCalls int
+	var handlerKey *string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerCalls++
+		handlerKey = submitter.IdempotencyKeyFromContext(r.Context())
+		w.Write([]byte("response"))
+	})
+
+	mocks.PredefinedTime = time.Now()
+	c := &config.Config{NetworkPassphrase: "Test SDF Network ; September 2015"}
+	middleware := IdempotencyMiddleware(c, mockRepository, mockEntityManager, mocks.Now)
+	testServer := httptest.NewServer(middleware(handler))
+	defer testServer.Close()
+
+	Convey("Given idempotent request", t, func() {
+		handlerCalls = 0
+		params := url.Values{
+			"asset_code":      {"USD"},
+			"amount":          {"20"},
+			"idempotency_key": {"key1"},
+		}
+
+		request, _ := http.NewRequest("POST", "/", strings.NewReader(params.Encode()))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		request.ParseForm()
+		requestHash := getRequestHash(request)
+
+		Convey("When idempotency_key is not sent", func() {
+			statusCode, response := getResponse(testServer, url.Values{"asset_code": {"USD"}})
+
+			Convey("it should call the handler", func() {
+				assert.Equal(t, 200, statusCode)
+				assert.Equal(t, "response", string(response))
+				assert.Equal(t, 1, handlerCalls)
+			})
+		})
+
+		Convey("When dry_run param is set", func() {
+			params.Set("dry_run", "true")
+			repositoryCalls := len(mockRepository.Calls)
+			statusCode, response := getResponse(testServer, params)
+
+			Convey("it should call the handler without saving the response", func() {
+				assert.Equal(t, 200, statusCode)
+				assert.Equal(t, "response", string(response))
+				assert.Equal(t, 1, handlerCalls)
+				assert.Equal(t, repositoryCalls, len(mockRepository.Calls))
+			})
+		})
+
+		Convey("When dry_run config param is set", func() {
+			c.DryRun = true
+			repositoryCalls := len(mockRepository.Calls)
+			statusCode, response := getResponse(testServer, params)
+			c.DryRun = false
+
+			Convey("it should call the handler without saving the response", func() {
+				assert.Equal(t, 200, statusCode)
+				assert.Equal(t, "response", string(response))
+				assert.Equal(t, 1, handlerCalls)
+				assert.Equal(t, repositoryCalls, len(mockRepository.Calls))
+			})
+		})
+
+		Convey("When idempotency_key is new", func() {
+			mockRepository.On("GetIdempotencyKey", "key1").Return((*db.IdempotencyKey)(nil), nil).Once()
+			mockEntityManager.On("Persist", mock.AnythingOfType("*db.IdempotencyKey")).Return(nil).Twice()
+
+			Convey("it should call the handler and save the response", func() {
+				statusCode, response := getResponse(testServer, params)
+				assert.Equal(t, 200, statusCode)
+				assert.Equal(t, "response", string(response))
+				assert.Equal(t, 1, handlerCalls)
+				// Transactions submitted by the handler are linked to the key
+				assert.Equal(t, "key1", *handlerKey)
+				mockRepository.AssertExpectations(t)
+				mockEntityManager.AssertExpectations(t)
+			})
+		})
+
+		Convey("When horizon times out and the request is retried after recovery", func() {
+			timeoutServer := httptest.NewServer(middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				handlerCalls++
+				errorServerError(w)
+			})))
+			defer timeoutServer.Close()
+
+			var savedKey *db.IdempotencyKey
+			filter := db.SentTransactionsFilter{IdempotencyKey: "key1", Limit: 100}
+			mockRepository.On("GetIdempotencyKey", "key1").Return((*db.IdempotencyKey)(nil), nil).Once()
+			mockEntityManager.On("Persist", mock.AnythingOfType("*db.IdempotencyKey")).Run(func(args mock.Arguments) {
+				savedKey = args.Get(0).(*db.IdempotencyKey)
+			}).Return(nil).Once()
+			mockRepository.On("GetSentTransactions", filter).Return([]db.SentTransaction{
+				{Status: "sending"},
+			}, nil).Once()
+
+			statusCode, _ := getResponse(timeoutServer, params)
+
+			Convey("it should leave the key processing and return the recovered transaction", func() {
+				assert.Equal(t, 500, statusCode)
+				assert.Equal(t, 1, handlerCalls)
+				assert.Equal(t, "processing", savedKey.Status)
+				mockEntityManager.AssertExpectations(t)
+
+				ledger := uint64(100)
+				envelopeXdr := "AAAAAIu7VxM5f9eQ3va0bpvKprxnSHB4zyEnY4D/VzT8Jio3AAAAZAAAAAAAAABlAAAAAAAAAAAAAAABAAAAAAAAAAEAAAAA5IVbm6A8mbgc/apAizxmBf4zZmqbedR3Ke+MTa7pjVYAAAAAAAAAAAvrwgAAAAAAAAAAAfwmKjcAAABAh3M6y9LXiWD0GB1KCkgNS5H1Lnyr1wS1BsfzoM1/v0muzobwNkJinV+RcWyC8VfeKqOjKBOANJnEusl+sHkcAg=="
+				mockRepository.On("GetIdempotencyKey", "key1").Return(savedKey, nil).Once()
+				mockRepository.On("GetSentTransactions", filter).Return([]db.SentTransaction{
+					{Status: "success", EnvelopeXdr: envelopeXdr, Ledger: &ledger},
+				}, nil).Once()
+				mockEntityManager.On("Persist", savedKey).Return(nil).Once()
+
+				createdAt := mocks.PredefinedTime
+				mocks.PredefinedTime = createdAt.Add(IdempotencyProcessingTimeout)
+				statusCode, response := getResponse(timeoutServer, params)
+				mocks.PredefinedTime = createdAt
+
+				assert.Equal(t, 200, statusCode)
+				var submitResponse horizon.SubmitTransactionResponse
+				err := json.Unmarshal(response, &submitResponse)
+				assert.Nil(t, err)
+				assert.Equal(t, "6a0049b44e0d0341bd52f131c74383e6ccd2b74b92c829c990994d24bbfcfa7a", submitResponse.Hash)
+				assert.Equal(t, "completed", savedKey.Status)
+				assert.Equal(t, 1, handlerCalls)
+				mockRepository.AssertExpectations(t)
+				mockEntityManager.AssertExpectations(t)
+			})
+		})
+
+		Convey("When idempotency_key was used with the same params", func() {
+			responseCode := 400
+			responseBody := "saved response"
+			mockRepository.On("GetIdempotencyKey", "key1").Return(
+				&db.IdempotencyKey{
+					Key:          "key1",
+					RequestHash:  requestHash,
+					Status:       "completed",
+					ResponseCode: &responseCode,
+					ResponseBody: &responseBody,
+				},
+				nil,
+			).Once()
+
+			Convey("it should return the saved response", func() {
+				statusCode, response := getResponse(testServer, params)
+				assert.Equal(t, 400, statusCode)
+				assert.Equal(t, "saved response", string(response))
+				assert.Equal(t, 0, handlerCalls)
+				mockRepository.AssertExpectations(t)
+			})
+		})
+
+		Convey("When request with the same idempotency_key is in progress", func() {
+			mockRepository.On("GetIdempotencyKey", "key1").Return(
+				&db.IdempotencyKey{
+					Key:         "key1",
+					RequestHash: requestHash,
+					Status:      "processing",
+					CreatedAt:   mocks.PredefinedTime,
+				},
+				nil,
+			).Once()
+
+			Convey("it should return error", func() {
+				statusCode, response := getResponse(testServer, params)
+				responseString := strings.TrimSpace(string(response))
+				assert.Equal(t, 409, statusCode)
+				assert.Equal(t, errorResponseString("request_in_progress", "Request with this idempotency_key is still being processed"), responseString)
+				assert.Equal(t, 0, handlerCalls)
+				mockRepository.AssertExpectations(t)
+			})
+		})
+
+		Convey("When request with the same idempotency_key has been processing for too long", func() {
+			processingKey := &db.IdempotencyKey{
+				Key:         "key1",
+				RequestHash: requestHash,
+				Status:      "processing",
+				CreatedAt:   mocks.PredefinedTime.Add(-IdempotencyProcessingTimeout),
+			}
+			mockRepository.On("GetIdempotencyKey", "key1").Return(processingKey, nil).Once()
+			filter := db.SentTransactionsFilter{IdempotencyKey: "key1", Limit: 100}
+
+			Convey("it should return error when no transaction has been submitted", func() {
+				mockRepository.On("GetSentTransactions", filter).Return([]db.SentTransaction{}, nil).Once()
+				mockEntityManager.On("Persist", processingKey).Return(nil).Once()
+
+				statusCode, response := getResponse(testServer, params)
+				responseString := strings.TrimSpace(string(response))
+				assert.Equal(t, 500, statusCode)
+				assert.Equal(t, errorResponseString("request_failed", "Request failed before submitting a transaction. Send it again with a new idempotency_key."), responseString)
+				assert.Equal(t, "completed", processingKey.Status)
+				assert.Equal(t, 0, handlerCalls)
+				mockRepository.AssertExpectations(t)
+				mockEntityManager.AssertExpectations(t)
+			})
+
+			Convey("it should return the result of the last transaction when it succeeded", func() {
+				ledger := uint64(100)
+				envelopeXdr := "AAAAAIu7VxM5f9eQ3va0bpvKprxnSHB4zyEnY4D/VzT8Jio3AAAAZAAAAAAAAABlAAAAAAAAAAAAAAABAAAAAAAAAAEAAAAA5IVbm6A8mbgc/apAizxmBf4zZmqbedR3Ke+MTa7pjVYAAAAAAAAAAAvrwgAAAAAAAAAAAfwmKjcAAABAh3M6y9LXiWD0GB1KCkgNS5H1Lnyr1wS1BsfzoM1/v0muzobwNkJinV+RcWyC8VfeKqOjKBOANJnEusl+sHkcAg=="
+				mockRepository.On("GetSentTransactions", filter).Return([]db.SentTransaction{
+					{Status: "failure", EnvelopeXdr: envelopeXdr},
+					{Status: "success", EnvelopeXdr: envelopeXdr, Ledger: &ledger},
+				}, nil).Once()
+				mockEntityManager.On("Persist", processingKey).Return(nil).Once()
+
+				statusCode, response := getResponse(testServer, params)
+				assert.Equal(t, 200, statusCode)
+
+				var submitResponse horizon.SubmitTransactionResponse
+				err := json.Unmarshal(response, &submitResponse)
+				assert.Nil(t, err)
+				assert.Equal(t, "6a0049b44e0d0341bd52f131c74383e6ccd2b74b92c829c990994d24bbfcfa7a", submitResponse.Hash)
+				assert.Equal(t, ledger, *submitResponse.Ledger)
+				assert.Equal(t, 0, handlerCalls)
+				mockEntityManager.AssertExpectations(t)
+			})
+
+			Convey("it should return transaction error when sequence number was consumed", func() {
+				mockRepository.On("GetSentTransactions", filter).Return([]db.SentTransaction{
+					{Status: "failure"},
+				}, nil).Once()
+				mockEntityManager.On("Persist", processingKey).Return(nil).Once()
+
+				statusCode, response := getResponse(testServer, params)
+				responseString := strings.TrimSpace(string(response))
+				assert.Equal(t, 400, statusCode)
+				assert.Equal(t, errorResponseString("transaction_bad_seq", "Bad Sequence. Please, try again."), responseString)
+				mockEntityManager.AssertExpectations(t)
+			})
+
+			Convey("it should return error when transaction is still sending", func() {
+				mockRepository.On("GetSentTransactions", filter).Return([]db.SentTransaction{
+					{Status: "sending"},
+				}, nil).Once()
+				persistCalls := len(mockEntityManager.Calls)
+
+				statusCode, _ := getResponse(testServer, params)
+				assert.Equal(t, 409, statusCode)
+				assert.Equal(t, "processing", processingKey.Status)
+				assert.Equal(t, persistCalls, len(mockEntityManager.Calls))
+				mockRepository.AssertExpectations(t)
+			})
+		})
+
+		Convey("When idempotency_key was used with different params", func() {
+			mockRepository.On("GetIdempotencyKey", "key1").Return(
+				&db.IdempotencyKey{
+					Key:         "key1",
+					RequestHash: "different",
+					Status:      "completed",
+				},
+				nil,
+			).Once()
+
+			Convey("it should return error", func() {
+				statusCode, response := getResponse(testServer, params)
+				responseString := strings.TrimSpace(string(response))
+				assert.Equal(t, 400, statusCode)
+				assert.Equal(t, errorResponseString("idempotency_key_reused", "idempotency_key has already been used with different parameters"), responseString)
+				assert.Equal(t, 0, handlerCalls)
+				mockRepository.AssertExpectations(t)
+			})
+		})
+
+		Convey("When loading idempotency_key fails", func() {
+			mockRepository.On("GetIdempotencyKey", "key1").Return((*db.IdempotencyKey)(nil), errors.New("DB error")).Once()
+
+			Convey("it should return server error", func() {
+				statusCode, _ := getResponse(testServer, params)
+				assert.Equal(t, 500, statusCode)
+				assert.Equal(t, 0, handlerCalls)
+				mockRepository.AssertExpectations(t)
+			})
+		})
+	})
+}
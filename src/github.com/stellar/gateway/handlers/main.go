@@ -7,7 +7,9 @@ import (
 	"net/url"
 
 	"github.com/stellar/gateway/config"
+	"github.com/stellar/gateway/db"
 	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/signer"
 	"github.com/stellar/gateway/submitter"
 )
 
@@ -15,6 +17,11 @@ type RequestHandler struct {
 	Config               *config.Config
 	Horizon              horizon.HorizonInterface
 	TransactionSubmitter submitter.TransactionSubmitterInterface
+	Repository           db.RepositoryInterface
+	EntityManager        db.EntityManagerInterface
+	// Signers is used to sign /payment transactions when `source` param is an
+	// account ID instead of a secret seed
+	Signers *signer.Registry
 	AddressResolver
 }
 
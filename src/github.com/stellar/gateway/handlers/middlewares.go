@@ -33,10 +33,16 @@ func HeadersMiddleware() func(next http.Handler) http.Handler {
 	}
 }
 
+// ApiKeyMiddleware checks `apiKey` param of POST requests or `X-Api-Key`
+// header. The key is never read from the query string so it does not end up
+// in access logs.
 func ApiKeyMiddleware(apiKey string) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		fn := func(w http.ResponseWriter, r *http.Request) {
 			k := r.PostFormValue("apiKey")
+			if k == "" {
+				k = r.Header.Get("X-Api-Key")
+			}
 			if k != apiKey {
 				errorForbidden(w, errorResponseString("forbidden", "Access denied."))
 				return
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApiKeyMiddleware(t *testing.T) {
+	apiKey := "secret-api-key-value"
+	handler := ApiKeyMiddleware(apiKey)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+
+	serve := func(r *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	postRequest := func(target string, params url.Values) *http.Request {
+		r := httptest.NewRequest("POST", target, strings.NewReader(params.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return r
+	}
+
+	Convey("ApiKeyMiddleware", t, func() {
+		Convey("it should accept apiKey param of POST request", func() {
+			w := serve(postRequest("/send", url.Values{"apiKey": {apiKey}}))
+			assert.Equal(t, 200, w.Code)
+		})
+
+		Convey("it should accept X-Api-Key header", func() {
+			r := httptest.NewRequest("GET", "/transactions/1", nil)
+			r.Header.Set("X-Api-Key", apiKey)
+			w := serve(r)
+			assert.Equal(t, 200, w.Code)
+		})
+
+		Convey("it should not accept apiKey in the query string", func() {
+			w := serve(httptest.NewRequest("GET", "/transactions/1?apiKey="+apiKey, nil))
+			assert.Equal(t, 403, w.Code)
+			assert.Equal(t, errorResponseString("forbidden", "Access denied."), strings.TrimSpace(w.Body.String()))
+
+			w = serve(postRequest("/send?apiKey="+apiKey, url.Values{}))
+			assert.Equal(t, 403, w.Code)
+		})
+
+		Convey("it should reject invalid key", func() {
+			r := postRequest("/send", url.Values{"apiKey": {"invalid"}})
+			r.Header.Set("X-Api-Key", "invalid")
+			w := serve(r)
+			assert.Equal(t, 403, w.Code)
+		})
+	})
+}
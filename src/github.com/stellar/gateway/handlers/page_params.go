@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	pageDefaultLimit = 10
+	pageMaxLimit     = 200
+)
+
+// parsePageParams parses `cursor` and `limit` parameters of list endpoints.
+// When a parameter is invalid the error response is written and ok is false.
+func parsePageParams(w http.ResponseWriter, query url.Values) (cursor int64, limit int, ok bool) {
+	limit = pageDefaultLimit
+
+	if value := query.Get("cursor"); value != "" {
+		var err error
+		cursor, err = strconv.ParseInt(value, 10, 64)
+		if err != nil || cursor < 0 {
+			errorBadRequest(w, errorResponseString("invalid_cursor", "cursor parameter is invalid"))
+			return
+		}
+	}
+
+	if value := query.Get("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > pageMaxLimit {
+			errorBadRequest(w, errorResponseString("invalid_limit", "limit parameter must be between 1 and 200"))
+			return
+		}
+	}
+
+	ok = true
+	return
+}
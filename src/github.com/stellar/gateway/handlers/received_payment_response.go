@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"time"
+)
+
+type ReceivedPaymentResponse struct {
+	Id              int64     `json:"id"`
+	OperationId     string    `json:"operation_id"`
+	AccountId       string    `json:"account_id"`
+	TransactionHash string    `json:"transaction_hash"`
+	PagingToken     string    `json:"paging_token"`
+	From            string    `json:"from"`
+	Amount          string    `json:"amount"`
+	AssetCode       string    `json:"asset_code"`
+	AssetIssuer     string    `json:"asset_issuer"`
+	MemoType        string    `json:"memo_type"`
+	Memo            string    `json:"memo"`
+	Status          string    `json:"status"`
+	ProcessedAt     time.Time `json:"processed_at"`
+	HookStatusCode  *int      `json:"hook_status_code"`
+	HookAttempts    int       `json:"hook_attempts"`
+	RefundStatus    *string   `json:"refund_status"`
+	RefundTxHash    *string   `json:"refund_transaction_hash"`
+}
+
+type ReceivedPaymentsPageResponse struct {
+	Payments []ReceivedPaymentResponse `json:"payments"`
+	// Cursor to use to get the next page
+	Cursor string `json:"cursor"`
+}
@@ -10,81 +10,30 @@ import (
 )
 
 func (rh *RequestHandler) Authorize(w http.ResponseWriter, r *http.Request) {
-	accountId := r.PostFormValue("account_id")
-	assetCode := r.PostFormValue("asset_code")
-
-	_, err := keypair.Parse(accountId)
-	if err != nil {
-		log.Print("Invalid accountId parameter: ", accountId)
-		errorBadRequest(w, errorResponseString("invalid_account_id", "accountId parameter is invalid"))
+	operationMutator, ok := rh.authorizeOperation(w, r)
+	if !ok {
 		return
 	}
 
-	if !rh.isAssetAllowed(assetCode) {
-		log.Print("Asset code not allowed: ", assetCode)
-		errorBadRequest(w, errorResponseString("invalid_asset_code", "Given assetCode not allowed"))
+	if rh.isDryRun(r) {
+		rh.dryRun(w, r, rh.Config.Accounts.GetAuthorizingAccountId(), operationMutator, nil)
 		return
 	}
 
-	operationMutator := b.AllowTrust(
-		b.Trustor{accountId},
-		b.Authorize{true},
-		b.AllowTrustAsset{assetCode},
-	)
-
 	submitResponse, err := rh.TransactionSubmitter.SubmitTransaction(
-		*rh.Config.Accounts.AuthorizingSeed,
+		r.Context(),
+		rh.Config.Accounts.GetAuthorizingAccountId(),
 		operationMutator,
 		nil,
 	)
 	if err != nil {
 		log.Print("Error submitting transaction ", err)
-		errorServerError(w)
+		errorSubmittingTransaction(w, err)
 		return
 	}
 
 	if submitResponse.Errors != nil {
-		var errorString string
-		if submitResponse.Errors.OperationErrorCode != "" {
-			switch submitResponse.Errors.OperationErrorCode {
-			case "allow_trust_malformed":
-				errorString = errorResponseString(
-					"allow_trust_malformed",
-					"Asset name is malformed.",
-				)
-			case "allow_trust_not_trustline":
-				errorString = errorResponseString(
-					"allow_trust_not_trustline",
-					"Trustor does not have a trustline yet.",
-				)
-			case "allow_trust_trust_not_required":
-				errorString = errorResponseString(
-					"allow_trust_trust_not_required",
-					"Authorizing account does not require allowing trust. Set AUTH_REQUIRED_FLAG on your account to use this feature.",
-				)
-			case "allow_trust_trust_cant_revoke":
-				errorString = errorResponseString(
-					"allow_trust_trust_cant_revoke",
-					"Authorizing account has AUTH_REVOCABLE_FLAG set. Can't revoke the trustline.",
-				)
-			default:
-				errorServerError(w)
-				return
-			}
-		} else if submitResponse.Errors.TransactionErrorCode != "" {
-			switch submitResponse.Errors.TransactionErrorCode {
-			case "transaction_bad_seq":
-				errorString = errorResponseString(
-					"transaction_bad_seq",
-					"Bad Sequence. Please, try again.",
-				)
-			default:
-				errorServerError(w)
-				return
-			}
-		}
-
-		errorBadRequest(w, errorString)
+		errorTransactionFailed(w, submitResponse.Errors)
 		return
 	}
 
@@ -97,3 +46,31 @@ func (rh *RequestHandler) Authorize(w http.ResponseWriter, r *http.Request) {
 
 	w.Write(json)
 }
+
+// authorizeOperation builds allow_trust operation from /authorize request
+// params. When params are invalid, error response is written and ok is false.
+func (rh *RequestHandler) authorizeOperation(w http.ResponseWriter, r *http.Request) (operation interface{}, ok bool) {
+	accountId := r.PostFormValue("account_id")
+	assetCode := r.PostFormValue("asset_code")
+
+	_, err := keypair.Parse(accountId)
+	if err != nil {
+		log.Print("Invalid accountId parameter: ", accountId)
+		errorBadRequest(w, errorResponseString("invalid_account_id", "accountId parameter is invalid"))
+		return
+	}
+
+	if !rh.isAssetAllowed(assetCode) {
+		log.Print("Asset code not allowed: ", assetCode)
+		errorBadRequest(w, errorResponseString("invalid_asset_code", "Given assetCode not allowed"))
+		return
+	}
+
+	operation = b.AllowTrust(
+		b.Trustor{accountId},
+		b.Authorize{true},
+		b.AllowTrustAsset{assetCode},
+	)
+	ok = true
+	return
+}
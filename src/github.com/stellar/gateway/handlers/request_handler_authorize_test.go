@@ -13,6 +13,7 @@ import (
 	"github.com/stellar/gateway/config"
 	"github.com/stellar/gateway/horizon"
 	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/gateway/submitter"
 	b "github.com/stellar/go-stellar-base/build"
 	"github.com/stretchr/testify/assert"
 )
@@ -75,7 +76,7 @@ func TestRequestHandlerAuthorize(t *testing.T) {
 			Convey("transaction fails", func() {
 				mockTransactionSubmitter.On(
 					"SubmitTransaction",
-					*config.Accounts.AuthorizingSeed,
+					config.Accounts.GetAuthorizingAccountId(),
 					operation,
 					nil,
 				).Return(
@@ -92,6 +93,26 @@ func TestRequestHandlerAuthorize(t *testing.T) {
 				})
 			})
 
+			Convey("signers cannot meet the threshold", func() {
+				mockTransactionSubmitter.On(
+					"SubmitTransaction",
+					config.Accounts.GetAuthorizingAccountId(),
+					operation,
+					nil,
+				).Return(
+					horizon.SubmitTransactionResponse{},
+					submitter.ErrInsufficientSigners,
+				).Once()
+
+				Convey("it should return insufficient_signers error", func() {
+					statusCode, response := getResponse(testServer, url.Values{"account_id": {accountId}, "asset_code": {assetCode}})
+					responseString := strings.TrimSpace(string(response))
+					assert.Equal(t, 400, statusCode)
+					assert.Equal(t, errorResponseString("insufficient_signers", "Available signers cannot meet the threshold of the source account."), responseString)
+					mockTransactionSubmitter.AssertExpectations(t)
+				})
+			})
+
 			Convey("transaction succeeds", func() {
 				var ledger uint64
 				ledger = 100
@@ -101,7 +122,7 @@ func TestRequestHandlerAuthorize(t *testing.T) {
 
 				mockTransactionSubmitter.On(
 					"SubmitTransaction",
-					*config.Accounts.AuthorizingSeed,
+					config.Accounts.GetAuthorizingAccountId(),
 					operation,
 					nil,
 				).Return(expectedSubmitResponse, nil).Once()
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"encoding/json"
+	log "github.com/Sirupsen/logrus"
+	"net/http"
+	"strconv"
+
+	b "github.com/stellar/go-stellar-base/build"
+	"github.com/stellar/go-stellar-base/keypair"
+)
+
+// BuildPayment builds /payment transaction without signing it.
+// `source` param must be an ID of the source account.
+func (rh *RequestHandler) BuildPayment(w http.ResponseWriter, r *http.Request) {
+	source := r.PostFormValue("source")
+	sourceKeypair, err := keypair.Parse(source)
+	if err != nil {
+		log.WithFields(log.Fields{"source": source}).Print("Invalid source parameter")
+		errorBadRequest(w, errorResponseString("invalid_source", "source parameter is invalid"))
+		return
+	}
+
+	tx, ok := rh.paymentTransaction(w, r, sourceKeypair.Address())
+	if !ok {
+		return
+	}
+
+	rh.writeBuiltTransaction(w, tx)
+}
+
+// BuildSend builds /send transaction without signing it.
+func (rh *RequestHandler) BuildSend(w http.ResponseWriter, r *http.Request) {
+	operationMutator, memoMutator, ok := rh.sendOperation(w, r)
+	if !ok {
+		return
+	}
+
+	tx, ok := rh.buildTransaction(w, r, rh.Config.Accounts.GetIssuingAccountId(), operationMutator, memoMutator)
+	if !ok {
+		return
+	}
+
+	rh.writeBuiltTransaction(w, tx)
+}
+
+// BuildAuthorize builds /authorize transaction without signing it.
+func (rh *RequestHandler) BuildAuthorize(w http.ResponseWriter, r *http.Request) {
+	operationMutator, ok := rh.authorizeOperation(w, r)
+	if !ok {
+		return
+	}
+
+	tx, ok := rh.buildTransaction(w, r, rh.Config.Accounts.GetAuthorizingAccountId(), operationMutator, nil)
+	if !ok {
+		return
+	}
+
+	rh.writeBuiltTransaction(w, tx)
+}
+
+// buildTransaction builds transaction with the next sequence number of source account.
+func (rh *RequestHandler) buildTransaction(w http.ResponseWriter, r *http.Request, source string, operation, memo interface{}) (tx *b.TransactionBuilder, ok bool) {
+	accountResponse, err := rh.Horizon.LoadAccount(r.Context(), source)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Cannot load source account")
+		errorServerError(w)
+		return
+	}
+
+	sequenceNumber, err := strconv.ParseUint(accountResponse.SequenceNumber, 10, 64)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Cannot convert SequenceNumber")
+		errorServerError(w)
+		return
+	}
+
+	transactionMutators := []b.TransactionMutator{
+		b.SourceAccount{source},
+		b.Sequence{sequenceNumber + 1},
+		b.Network{rh.Config.NetworkPassphrase},
+		operation.(b.TransactionMutator),
+	}
+
+	if memo != nil {
+		transactionMutators = append(transactionMutators, memo.(b.TransactionMutator))
+	}
+
+	tx = b.Transaction(transactionMutators...)
+	if tx.Err != nil {
+		log.WithFields(log.Fields{"err": tx.Err}).Print("Transaction builder error")
+		errorServerError(w)
+		return
+	}
+
+	ok = true
+	return
+}
+
+func (rh *RequestHandler) writeBuiltTransaction(w http.ResponseWriter, tx *b.TransactionBuilder) {
+	hash, err := tx.HashHex()
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Cannot compute transaction hash")
+		errorServerError(w)
+		return
+	}
+
+	txe := tx.Sign()
+	txeB64, err := txe.Base64()
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Cannot encode transaction envelope")
+		errorServerError(w)
+		return
+	}
+
+	json, err := json.MarshalIndent(BuildTransactionResponse{
+		TransactionEnvelope: txeB64,
+		Hash:                hash,
+	}, "", "  ")
+	if err != nil {
+		errorServerError(w)
+		return
+	}
+
+	w.Write(json)
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stellar/gateway/config"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/gateway/submitter"
+	b "github.com/stellar/go-stellar-base/build"
+	"github.com/stellar/go-stellar-base/xdr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestHandlerBuildAuthorize(t *testing.T) {
+	mockHorizon := new(mocks.MockHorizon)
+
+	authorizingAccountId := "GBQXA3ABGQGTCLEVZIUTDRWWJOQD5LSAEDZAG7GMOGD2HBLWONGUVO4I"
+
+	config := config.Config{
+		NetworkPassphrase: "Test SDF Network ; September 2015",
+		Assets:            []string{"USD", "EUR"},
+		Accounts: &config.Accounts{
+			AuthorizingAccountId: &authorizingAccountId,
+		},
+	}
+
+	requestHandler := RequestHandler{Config: &config, Horizon: mockHorizon}
+	testServer := httptest.NewServer(http.HandlerFunc(requestHandler.BuildAuthorize))
+	defer testServer.Close()
+
+	Convey("Given build authorize request", t, func() {
+		Convey("When params are valid", func() {
+			mockHorizon.On("LoadAccount", authorizingAccountId).Return(
+				horizon.AccountResponse{AccountId: authorizingAccountId, SequenceNumber: "100"},
+				nil,
+			).Once()
+
+			Convey("it should return unsigned transaction with next sequence number", func() {
+				statusCode, response := getResponse(testServer, url.Values{
+					"account_id": {"GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632"},
+					"asset_code": {"USD"},
+				})
+				assert.Equal(t, 200, statusCode)
+
+				var buildResponse BuildTransactionResponse
+				err := json.Unmarshal(response, &buildResponse)
+				assert.Nil(t, err)
+
+				var envelope xdr.TransactionEnvelope
+				err = xdr.SafeUnmarshalBase64(buildResponse.TransactionEnvelope, &envelope)
+				assert.Nil(t, err)
+				assert.Equal(t, 0, len(envelope.Signatures))
+				assert.Equal(t, xdr.SequenceNumber(101), envelope.Tx.SeqNum)
+				assert.Equal(t, xdr.OperationTypeAllowTrust, envelope.Tx.Operations[0].Body.Type)
+
+				hash, err := submitter.TransactionHash(&envelope.Tx, b.Network{config.NetworkPassphrase})
+				assert.Nil(t, err)
+				assert.Equal(t, hash, buildResponse.Hash)
+				mockHorizon.AssertExpectations(t)
+			})
+		})
+	})
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"encoding/json"
+	log "github.com/Sirupsen/logrus"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/stellar/gateway/db"
+	"github.com/zenazn/goji/web"
+)
+
+// HookDeliveries lists hook deliveries (ex. dead-lettered ones with
+// status=failed).
+func (rh *RequestHandler) HookDeliveries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := db.HookDeliveriesFilter{
+		Status: query.Get("status"),
+	}
+
+	switch filter.Status {
+	case "", "pending", "delivered", "failed":
+		break
+	default:
+		errorBadRequest(w, errorResponseString("invalid_status", "status parameter is invalid"))
+		return
+	}
+
+	var ok bool
+	filter.Cursor, filter.Limit, ok = parsePageParams(w, query)
+	if !ok {
+		return
+	}
+
+	deliveries, err := rh.Repository.GetHookDeliveries(filter)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error loading hook deliveries")
+		errorServerError(w)
+		return
+	}
+
+	page := HookDeliveriesPageResponse{
+		Deliveries: []HookDeliveryResponse{},
+		Cursor:     strconv.FormatInt(filter.Cursor, 10),
+	}
+	for _, delivery := range deliveries {
+		page.Deliveries = append(page.Deliveries, newHookDeliveryResponse(delivery))
+		page.Cursor = strconv.FormatInt(*delivery.Id, 10)
+	}
+
+	json, err := json.MarshalIndent(page, "", "  ")
+	if err != nil {
+		errorServerError(w)
+		return
+	}
+
+	w.Write(json)
+}
+
+// RedeliverHook schedules delivery with a given id to be sent again.
+func (rh *RequestHandler) RedeliverHook(c web.C, w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(c.URLParams["id"], 10, 64)
+	if err != nil {
+		errorBadRequest(w, errorResponseString("invalid_id", "id parameter is invalid"))
+		return
+	}
+
+	delivery, err := rh.Repository.GetHookDeliveryById(id)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error loading hook delivery")
+		errorServerError(w)
+		return
+	}
+
+	if delivery == nil {
+		errorNotFound(w, errorResponseString("not_found", "Hook delivery not found"))
+		return
+	}
+
+	if delivery.Status == "pending" {
+		errorBadRequest(w, errorResponseString("delivery_pending", "Hook delivery is already pending"))
+		return
+	}
+
+	delivery.MarkPending(time.Now())
+	err = rh.EntityManager.Persist(delivery)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error saving hook delivery")
+		errorServerError(w)
+		return
+	}
+
+	json, err := json.MarshalIndent(newHookDeliveryResponse(*delivery), "", "  ")
+	if err != nil {
+		errorServerError(w)
+		return
+	}
+
+	w.Write(json)
+}
+
+func newHookDeliveryResponse(delivery db.HookDelivery) HookDeliveryResponse {
+	return HookDeliveryResponse{
+		Id:            *delivery.Id,
+		Hook:          delivery.Hook,
+		Url:           delivery.Url,
+		Payload:       delivery.Payload,
+		OperationId:   delivery.OperationId,
+		Status:        delivery.Status,
+		Attempts:      delivery.Attempts,
+		NextAttemptAt: delivery.NextAttemptAt,
+		LastError:     delivery.LastError,
+		CreatedAt:     delivery.CreatedAt,
+		DeliveredAt:   delivery.DeliveredAt,
+	}
+}
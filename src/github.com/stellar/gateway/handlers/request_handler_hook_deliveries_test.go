@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zenazn/goji/web"
+)
+
+func TestRequestHandlerHookDeliveries(t *testing.T) {
+	mockRepository := new(mocks.MockRepository)
+	mockEntityManager := new(mocks.MockEntityManager)
+
+	requestHandler := RequestHandler{
+		Repository:    mockRepository,
+		EntityManager: mockEntityManager,
+	}
+
+	mux := web.New()
+	mux.Get("/admin/hook_deliveries", requestHandler.HookDeliveries)
+	mux.Post("/admin/hook_deliveries/:id/redeliver", requestHandler.RedeliverHook)
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	redeliver := func(id string) (int, []byte) {
+		res, err := http.PostForm(testServer.URL+"/admin/hook_deliveries/"+id+"/redeliver", url.Values{})
+		if err != nil {
+			panic(err)
+		}
+		response, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			panic(err)
+		}
+		return res.StatusCode, response
+	}
+
+	id := int64(3)
+	lastError := "Error response from receive hook: 503"
+	delivery := db.HookDelivery{
+		Id:            &id,
+		Hook:          "receive",
+		Url:           "http://receive.example.com/hook",
+		Payload:       "amount=200&id=1",
+		OperationId:   "1",
+		Status:        "failed",
+		Attempts:      10,
+		NextAttemptAt: time.Now(),
+		LastError:     &lastError,
+		CreatedAt:     time.Now(),
+	}
+
+	Convey("Given hook deliveries request", t, func() {
+		Convey("When status is invalid", func() {
+			Convey("it should return error", func() {
+				statusCode, response := getRequest(testServer, "/admin/hook_deliveries?status=unknown")
+				responseString := strings.TrimSpace(string(response))
+				assert.Equal(t, 400, statusCode)
+				assert.Equal(t, errorResponseString("invalid_status", "status parameter is invalid"), responseString)
+			})
+		})
+
+		Convey("When params are valid", func() {
+			filter := db.HookDeliveriesFilter{Status: "failed", Cursor: 2, Limit: 10}
+
+			Convey("it should return deliveries page", func() {
+				mockRepository.On("GetHookDeliveries", filter).Return([]db.HookDelivery{delivery}, nil).Once()
+
+				statusCode, response := getRequest(testServer, "/admin/hook_deliveries?status=failed&cursor=2")
+				assert.Equal(t, 200, statusCode)
+
+				var page HookDeliveriesPageResponse
+				err := json.Unmarshal(response, &page)
+				assert.Nil(t, err)
+				assert.Equal(t, 1, len(page.Deliveries))
+				assert.Equal(t, "failed", page.Deliveries[0].Status)
+				assert.Equal(t, lastError, *page.Deliveries[0].LastError)
+				assert.Equal(t, "3", page.Cursor)
+				mockRepository.AssertExpectations(t)
+			})
+
+			Convey("it should return server error when repository fails", func() {
+				mockRepository.On("GetHookDeliveries", filter).Return([]db.HookDelivery{}, errors.New("DB error")).Once()
+
+				statusCode, _ := getRequest(testServer, "/admin/hook_deliveries?status=failed&cursor=2")
+				assert.Equal(t, 500, statusCode)
+				mockRepository.AssertExpectations(t)
+			})
+		})
+	})
+
+	Convey("Given redeliver request", t, func() {
+		Convey("When delivery does not exist", func() {
+			mockRepository.On("GetHookDeliveryById", int64(4)).Return((*db.HookDelivery)(nil), nil).Once()
+
+			Convey("it should return error", func() {
+				statusCode, response := redeliver("4")
+				responseString := strings.TrimSpace(string(response))
+				assert.Equal(t, 404, statusCode)
+				assert.Equal(t, errorResponseString("not_found", "Hook delivery not found"), responseString)
+				mockRepository.AssertExpectations(t)
+			})
+		})
+
+		Convey("When delivery is pending", func() {
+			pendingDelivery := delivery
+			pendingDelivery.Status = "pending"
+			mockRepository.On("GetHookDeliveryById", int64(3)).Return(&pendingDelivery, nil).Once()
+
+			Convey("it should return error", func() {
+				statusCode, response := redeliver("3")
+				responseString := strings.TrimSpace(string(response))
+				assert.Equal(t, 400, statusCode)
+				assert.Equal(t, errorResponseString("delivery_pending", "Hook delivery is already pending"), responseString)
+				mockRepository.AssertExpectations(t)
+			})
+		})
+
+		Convey("When delivery failed", func() {
+			failedDelivery := delivery
+			mockRepository.On("GetHookDeliveryById", int64(3)).Return(&failedDelivery, nil).Once()
+			mockEntityManager.On("Persist", mock.AnythingOfType("*db.HookDelivery")).Return(nil).Once()
+
+			Convey("it should schedule it again", func() {
+				statusCode, response := redeliver("3")
+				assert.Equal(t, 200, statusCode)
+
+				var deliveryResponse HookDeliveryResponse
+				err := json.Unmarshal(response, &deliveryResponse)
+				assert.Nil(t, err)
+				assert.Equal(t, "pending", deliveryResponse.Status)
+				assert.Equal(t, 0, deliveryResponse.Attempts)
+				assert.Equal(t, "pending", failedDelivery.Status)
+				mockRepository.AssertExpectations(t)
+				mockEntityManager.AssertExpectations(t)
+			})
+		})
+	})
+}
@@ -1,25 +1,57 @@
 package handlers
 
 import (
-	"encoding/json"
 	log "github.com/Sirupsen/logrus"
 	"net/http"
 	"strconv"
 	"strings"
 
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/signer"
 	b "github.com/stellar/go-stellar-base/build"
 	"github.com/stellar/go-stellar-base/keypair"
 )
 
 func (rh *RequestHandler) Payment(w http.ResponseWriter, r *http.Request) {
 	source := r.PostFormValue("source")
-	sourceKeypair, err := keypair.Parse(source)
+	sourceSigner, err := rh.getSourceSigner(source)
 	if err != nil {
-		log.WithFields(log.Fields{"source": source}).Print("Invalid source parameter")
+		log.WithFields(log.Fields{"err": err}).Print("Invalid source parameter")
 		errorBadRequest(w, errorResponseString("invalid_source", "source parameter is invalid"))
 		return
 	}
 
+	tx, ok := rh.paymentTransaction(w, r, sourceSigner.Address())
+	if !ok {
+		return
+	}
+
+	txe, err := signer.SignTransaction(tx, sourceSigner)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Cannot sign transaction")
+		errorServerError(w)
+		return
+	}
+
+	txeB64, err := txe.Base64()
+
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Cannot encode transaction envelope")
+		errorServerError(w)
+		return
+	}
+
+	if rh.isDryRun(r) {
+		rh.writeDryRunResponse(w, r, txeB64)
+		return
+	}
+
+	rh.submitTransaction(w, r, sourceSigner.Address(), txeB64)
+}
+
+// paymentTransaction builds unsigned /payment transaction with a given source
+// account. When params are invalid, error response is written and ok is false.
+func (rh *RequestHandler) paymentTransaction(w http.ResponseWriter, r *http.Request, source string) (tx *b.TransactionBuilder, ok bool) {
 	destination := r.PostFormValue("destination")
 	destinationObject, err := rh.AddressResolver.Resolve(destination)
 	if err != nil {
@@ -60,11 +92,15 @@ func (rh *RequestHandler) Payment(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// Check if destination account exist
-		_, err = rh.Horizon.LoadAccount(destinationObject.AccountId)
-		if err != nil {
-			log.WithFields(log.Fields{"error": err}).Error("Error loading account")
+		_, err = rh.Horizon.LoadAccount(r.Context(), destinationObject.AccountId)
+		switch {
+		case err == horizon.ErrNotFound:
 			operationBuilder = b.CreateAccount(mutators...)
-		} else {
+		case err != nil:
+			log.WithFields(log.Fields{"error": err}).Error("Error loading destination account")
+			errorServerError(w)
+			return
+		default:
 			operationBuilder = b.Payment(mutators...)
 		}
 	} else {
@@ -113,10 +149,14 @@ func (rh *RequestHandler) Payment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	accountResponse, err := rh.Horizon.LoadAccount(sourceKeypair.Address())
+	accountResponse, err := rh.Horizon.LoadAccount(r.Context(), source)
+	if err == horizon.ErrNotFound {
+		errorBadRequest(w, errorResponseString("source_not_exist", "source account does not exist"))
+		return
+	}
 	if err != nil {
 		log.WithFields(log.Fields{"error": err}).Error("Cannot load source account")
-		errorBadRequest(w, errorResponseString("source_not_exist", "source account does not exist"))
+		errorServerError(w)
 		return
 	}
 
@@ -138,7 +178,7 @@ func (rh *RequestHandler) Payment(w http.ResponseWriter, r *http.Request) {
 		transactionMutators = append(transactionMutators, memoMutator.(b.TransactionMutator))
 	}
 
-	tx := b.Transaction(transactionMutators...)
+	tx = b.Transaction(transactionMutators...)
 
 	if tx.Err != nil {
 		log.WithFields(log.Fields{"err": tx.Err}).Print("Transaction builder error")
@@ -156,33 +196,27 @@ func (rh *RequestHandler) Payment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	txe := tx.Sign(source)
-	txeB64, err := txe.Base64()
-
-	if err != nil {
-		log.WithFields(log.Fields{"error": err}).Error("Cannot encode transaction envelope")
-		errorServerError(w)
-		return
-	}
+	ok = true
+	return
+}
 
-	submitResponse, err := rh.Horizon.SubmitTransaction(txeB64)
+// getSourceSigner returns Signer for `source` param. source can be a secret
+// seed or an ID of account listed in `signers.payment_sources`. Keys of other
+// accounts held by the server (issuing, authorizing, keystore) are never used
+// for /payment.
+func (rh *RequestHandler) getSourceSigner(source string) (signer.Signer, error) {
+	kp, err := keypair.Parse(source)
 	if err != nil {
-		log.WithFields(log.Fields{"error": err}).Error("Error submitting transaction")
-		errorServerError(w)
-		return
+		return nil, err
 	}
 
-	response, err := json.MarshalIndent(submitResponse, "", "  ")
-	if err != nil {
-		log.WithFields(log.Fields{"error": err}).Error("Cannot Marshal submitResponse")
-		errorServerError(w)
-		return
+	if _, ok := kp.(*keypair.Full); ok {
+		return signer.NewSeedSigner(source)
 	}
 
-	if submitResponse.Ledger != nil {
-		w.Write(response)
-	} else {
-		errorBadRequest(w, string(response))
+	if rh.Signers == nil || !rh.Config.Signers.IsPaymentSource(kp.Address()) {
+		return nil, keypair.ErrCannotSign
 	}
 
+	return rh.Signers.Get(kp.Address())
 }
@@ -11,14 +11,19 @@ import (
 
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/stellar/gateway/config"
+	"github.com/stellar/gateway/db"
 	"github.com/stellar/gateway/horizon"
 	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/gateway/signer"
+	"github.com/stellar/go-stellar-base/keypair"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
 func TestRequestHandlerPayment(t *testing.T) {
 	mockHorizon := new(mocks.MockHorizon)
+	mockEntityManager := new(mocks.MockEntityManager)
+	mockEntityManager.On("Persist", mock.AnythingOfType("*db.SentTransaction")).Return(nil)
 
 	mockAddressResolverHelper := new(MockAddressResolverHelper)
 	addressResolver := AddressResolver{mockAddressResolverHelper}
@@ -28,6 +33,7 @@ func TestRequestHandlerPayment(t *testing.T) {
 			NetworkPassphrase: "Test SDF Network ; September 2015",
 		},
 		Horizon:         mockHorizon,
+		EntityManager:   mockEntityManager,
 		AddressResolver: addressResolver,
 	}
 
@@ -167,7 +173,7 @@ func TestRequestHandlerPayment(t *testing.T) {
 
 				var ledger uint64
 				ledger = 1988728
-				horizonResponse := horizon.SubmitTransactionResponse{&ledger, nil, nil}
+				horizonResponse := horizon.SubmitTransactionResponse{Ledger: &ledger}
 
 				mockHorizon.On(
 					"SubmitTransaction",
@@ -185,6 +191,11 @@ func TestRequestHandlerPayment(t *testing.T) {
 
 					assert.Equal(t, 200, statusCode)
 					assert.Equal(t, string(expectedResponse), responseString)
+
+					// Saved before and after submission
+					sentTransaction := mockEntityManager.Calls[len(mockEntityManager.Calls)-1].Arguments.Get(0).(*db.SentTransaction)
+					assert.Equal(t, "success", sentTransaction.Status)
+					assert.Equal(t, "GCF3WVYTHF75PEG6622G5G6KU26GOSDQPDHSCJ3DQD7VONH4EYVDOGKJ", sentTransaction.Source)
 				})
 			})
 
@@ -240,7 +251,7 @@ func TestRequestHandlerPayment(t *testing.T) {
 
 				var ledger uint64
 				ledger = 1988728
-				horizonResponse := horizon.SubmitTransactionResponse{&ledger, nil, nil}
+				horizonResponse := horizon.SubmitTransactionResponse{Ledger: &ledger}
 
 				mockHorizon.On(
 					"SubmitTransaction",
@@ -403,7 +414,7 @@ func TestRequestHandlerPayment(t *testing.T) {
 
 					var ledger uint64
 					ledger = 1988727
-					horizonResponse := horizon.SubmitTransactionResponse{&ledger, nil, nil}
+					horizonResponse := horizon.SubmitTransactionResponse{Ledger: &ledger}
 
 					mockHorizon.On(
 						"SubmitTransaction",
@@ -429,7 +440,7 @@ func TestRequestHandlerPayment(t *testing.T) {
 				mockHorizon.On(
 					"LoadAccount",
 					"GCF3WVYTHF75PEG6622G5G6KU26GOSDQPDHSCJ3DQD7VONH4EYVDOGKJ",
-				).Return(horizon.AccountResponse{}, errors.New("Not found")).Once()
+				).Return(horizon.AccountResponse{}, horizon.ErrNotFound).Once()
 
 				Convey("it should return error", func() {
 					statusCode, response := getResponse(testServer, validParams)
@@ -439,6 +450,20 @@ func TestRequestHandlerPayment(t *testing.T) {
 				})
 			})
 
+			Convey("horizon is not available", func() {
+				mockHorizon.On(
+					"LoadAccount",
+					"GCF3WVYTHF75PEG6622G5G6KU26GOSDQPDHSCJ3DQD7VONH4EYVDOGKJ",
+				).Return(horizon.AccountResponse{}, horizon.ErrTimeout).Once()
+
+				Convey("it should return server error", func() {
+					statusCode, response := getResponse(testServer, validParams)
+					responseString := strings.TrimSpace(string(response))
+					assert.Equal(t, 500, statusCode)
+					assert.Equal(t, getServerErrorResponseString(), responseString)
+				})
+			})
+
 			Convey("transaction failed in horizon", func() {
 				mockHorizon.On(
 					"LoadAccount",
@@ -451,11 +476,10 @@ func TestRequestHandlerPayment(t *testing.T) {
 				).Once()
 
 				horizonResponse := horizon.SubmitTransactionResponse{
-					nil,
-					&horizon.SubmitTransactionResponseError{
+					Errors: &horizon.SubmitTransactionResponseError{
 						TransactionErrorCode: "transaction_failed",
 					},
-					&horizon.SubmitTransactionResponseExtras{
+					Extras: &horizon.SubmitTransactionResponseExtras{
 						EnvelopeXdr: "envelope",
 						ResultXdr:   "result",
 					},
@@ -469,14 +493,8 @@ func TestRequestHandlerPayment(t *testing.T) {
 				Convey("it should return error", func() {
 					statusCode, response := getResponse(testServer, validParams)
 					responseString := strings.TrimSpace(string(response))
-
-					expectedResponse, err := json.MarshalIndent(horizonResponse, "", "  ")
-					if err != nil {
-						panic(err)
-					}
-
 					assert.Equal(t, 400, statusCode)
-					assert.Equal(t, string(expectedResponse), responseString)
+					assert.Equal(t, errorResponseString("transaction_failed", "One of the operations failed."), responseString)
 				})
 			})
 
@@ -507,7 +525,7 @@ func TestRequestHandlerPayment(t *testing.T) {
 
 				var ledger uint64
 				ledger = 1988727
-				horizonResponse := horizon.SubmitTransactionResponse{&ledger, nil, nil}
+				horizonResponse := horizon.SubmitTransactionResponse{Ledger: &ledger}
 
 				mockHorizon.On(
 					"SubmitTransaction",
@@ -541,7 +559,7 @@ func TestRequestHandlerPayment(t *testing.T) {
 
 				var ledger uint64
 				ledger = 1988727
-				horizonResponse := horizon.SubmitTransactionResponse{&ledger, nil, nil}
+				horizonResponse := horizon.SubmitTransactionResponse{Ledger: &ledger}
 
 				mockHorizon.On(
 					"SubmitTransaction",
@@ -564,3 +582,51 @@ func TestRequestHandlerPayment(t *testing.T) {
 		})
 	})
 }
+
+func TestRequestHandlerGetSourceSigner(t *testing.T) {
+	// GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR
+	issuingSigner, err := signer.NewSeedSigner("SC34WILLHVADXMP6ACPMIRA6TRAWJMVCLPFNW7S6MUMXJVLAZUC4EWHP")
+	assert.Nil(t, err)
+	// GCOGCYU77DLEVYCXDQM7F32M5PCKES6VU3Z5GURF6U6OA5LFOVTRYPOX
+	sourceSigner, err := signer.NewSeedSigner("SCLRUYW3QOMS63AU2IMAEXLCSK73RRL35SY5MYSFV6I63S7BFKJ4KBYF")
+	assert.Nil(t, err)
+
+	signers := signer.NewRegistry("", "")
+	signers.Add(issuingSigner)
+	signers.Add(sourceSigner)
+
+	requestHandler := RequestHandler{
+		Config: &config.Config{
+			Signers: &config.Signers{
+				PaymentSources: []string{sourceSigner.Address()},
+			},
+		},
+		Signers: signers,
+	}
+
+	Convey("Given source of payment", t, func() {
+		Convey("When source is a secret seed", func() {
+			seed := "SDRAS7XIQNX25UDCCX725R4EYGBFYGJE4HJ2A3DFCWJIHMRSMS7CXX42"
+			result, err := requestHandler.getSourceSigner(seed)
+			assert.Nil(t, err)
+			assert.Equal(t, keypair.MustParse(seed).Address(), result.Address())
+		})
+
+		Convey("When source is an account listed in signers.payment_sources", func() {
+			result, err := requestHandler.getSourceSigner(sourceSigner.Address())
+			assert.Nil(t, err)
+			assert.Equal(t, sourceSigner, result)
+		})
+
+		Convey("When source is other account held by the server", func() {
+			_, err := requestHandler.getSourceSigner(issuingSigner.Address())
+			assert.Equal(t, keypair.ErrCannotSign, err)
+		})
+
+		Convey("When signers.payment_sources is not set", func() {
+			requestHandler.Config.Signers = nil
+			_, err := requestHandler.getSourceSigner(sourceSigner.Address())
+			assert.Equal(t, keypair.ErrCannotSign, err)
+		})
+	})
+}
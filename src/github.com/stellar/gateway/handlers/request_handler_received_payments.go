@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"encoding/json"
+	log "github.com/Sirupsen/logrus"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/go-stellar-base/keypair"
+)
+
+// ReceivedPayments lists payments saved by PaymentListener.
+func (rh *RequestHandler) ReceivedPayments(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := db.ReceivedPaymentsFilter{
+		AccountId:   query.Get("account_id"),
+		Memo:        query.Get("memo"),
+		AssetCode:   query.Get("asset_code"),
+		AssetIssuer: query.Get("asset_issuer"),
+		From:        query.Get("from"),
+	}
+
+	if filter.AccountId != "" {
+		_, err := keypair.Parse(filter.AccountId)
+		if err != nil {
+			errorBadRequest(w, errorResponseString("invalid_account_id", "account_id parameter is invalid"))
+			return
+		}
+	}
+
+	if filter.AssetIssuer != "" {
+		_, err := keypair.Parse(filter.AssetIssuer)
+		if err != nil {
+			errorBadRequest(w, errorResponseString("invalid_asset_issuer", "asset_issuer parameter is invalid"))
+			return
+		}
+	}
+
+	if filter.From != "" {
+		_, err := keypair.Parse(filter.From)
+		if err != nil {
+			errorBadRequest(w, errorResponseString("invalid_from", "from parameter is invalid"))
+			return
+		}
+	}
+
+	if since := query.Get("since"); since != "" {
+		sinceTime, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			errorBadRequest(w, errorResponseString("invalid_since", "since parameter must be a RFC 3339 date"))
+			return
+		}
+		filter.Since = &sinceTime
+	}
+
+	if until := query.Get("until"); until != "" {
+		untilTime, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			errorBadRequest(w, errorResponseString("invalid_until", "until parameter must be a RFC 3339 date"))
+			return
+		}
+		filter.Until = &untilTime
+	}
+
+	var ok bool
+	filter.Cursor, filter.Limit, ok = parsePageParams(w, query)
+	if !ok {
+		return
+	}
+
+	payments, err := rh.Repository.GetReceivedPayments(filter)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error loading received payments")
+		errorServerError(w)
+		return
+	}
+
+	page := ReceivedPaymentsPageResponse{
+		Payments: []ReceivedPaymentResponse{},
+		Cursor:   strconv.FormatInt(filter.Cursor, 10),
+	}
+	for _, payment := range payments {
+		page.Payments = append(page.Payments, newReceivedPaymentResponse(payment))
+		page.Cursor = strconv.FormatInt(*payment.Id, 10)
+	}
+
+	json, err := json.MarshalIndent(page, "", "  ")
+	if err != nil {
+		errorServerError(w)
+		return
+	}
+
+	w.Write(json)
+}
+
+func newReceivedPaymentResponse(payment db.ReceivedPayment) ReceivedPaymentResponse {
+	return ReceivedPaymentResponse{
+		Id:              *payment.Id,
+		OperationId:     payment.OperationId,
+		AccountId:       payment.AccountId,
+		TransactionHash: payment.TransactionHash,
+		PagingToken:     payment.PagingToken,
+		From:            payment.From,
+		Amount:          payment.Amount,
+		AssetCode:       payment.AssetCode,
+		AssetIssuer:     payment.AssetIssuer,
+		MemoType:        payment.MemoType,
+		Memo:            payment.Memo,
+		Status:          payment.Status,
+		ProcessedAt:     payment.ProcessedAt,
+		HookStatusCode:  payment.HookStatusCode,
+		HookAttempts:    payment.HookAttempts,
+		RefundStatus:    payment.RefundStatus,
+		RefundTxHash:    payment.RefundTransactionHash,
+	}
+}
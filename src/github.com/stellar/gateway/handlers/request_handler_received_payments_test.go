@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/zenazn/goji/web"
+)
+
+func TestRequestHandlerReceivedPayments(t *testing.T) {
+	mockRepository := new(mocks.MockRepository)
+
+	requestHandler := RequestHandler{
+		Repository: mockRepository,
+	}
+
+	mux := web.New()
+	mux.Get("/payments/received", requestHandler.ReceivedPayments)
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	id := int64(7)
+	hookStatusCode := 200
+	payment := db.ReceivedPayment{
+		Id:              &id,
+		OperationId:     "3",
+		AccountId:       "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB",
+		ProcessedAt:     time.Now(),
+		PagingToken:     "3",
+		Status:          "Success",
+		TransactionHash: "6391dd190f15f7d1665ba53c63842e368f485651a53d8d852ed442a446d1c69a",
+		From:            "GBIHSMPXC2KJ3NJVHEYTG3KCHYEUQRT45X6AWYWXMAXZOAX4F5LFZYYQ",
+		Amount:          "200",
+		AssetCode:       "USD",
+		AssetIssuer:     "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR",
+		MemoType:        "text",
+		Memo:            "testing",
+		HookStatusCode:  &hookStatusCode,
+		HookAttempts:    1,
+	}
+
+	Convey("Given received payments request", t, func() {
+		Convey("When from is invalid", func() {
+			Convey("it should return error", func() {
+				statusCode, response := getRequest(testServer, "/payments/received?from=GBIHSMPX")
+				responseString := strings.TrimSpace(string(response))
+				assert.Equal(t, 400, statusCode)
+				assert.Equal(t, errorResponseString("invalid_from", "from parameter is invalid"), responseString)
+			})
+		})
+
+		Convey("When until is invalid", func() {
+			Convey("it should return error", func() {
+				statusCode, response := getRequest(testServer, "/payments/received?until=tomorrow")
+				responseString := strings.TrimSpace(string(response))
+				assert.Equal(t, 400, statusCode)
+				assert.Equal(t, errorResponseString("invalid_until", "until parameter must be a RFC 3339 date"), responseString)
+			})
+		})
+
+		Convey("When params are valid", func() {
+			since, _ := time.Parse(time.RFC3339, "2016-01-02T15:04:05Z")
+			until, _ := time.Parse(time.RFC3339, "2016-02-02T15:04:05Z")
+			filter := db.ReceivedPaymentsFilter{
+				AccountId:   "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB",
+				Memo:        "testing",
+				AssetCode:   "USD",
+				AssetIssuer: "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR",
+				From:        "GBIHSMPXC2KJ3NJVHEYTG3KCHYEUQRT45X6AWYWXMAXZOAX4F5LFZYYQ",
+				Since:       &since,
+				Until:       &until,
+				Cursor:      4,
+				Limit:       20,
+			}
+			path := "/payments/received?account_id=GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB&memo=testing&asset_code=USD&asset_issuer=GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR&from=GBIHSMPXC2KJ3NJVHEYTG3KCHYEUQRT45X6AWYWXMAXZOAX4F5LFZYYQ&since=2016-01-02T15:04:05Z&until=2016-02-02T15:04:05Z&cursor=4&limit=20"
+
+			Convey("it should return payments page", func() {
+				mockRepository.On("GetReceivedPayments", filter).Return([]db.ReceivedPayment{payment}, nil).Once()
+
+				statusCode, response := getRequest(testServer, path)
+				assert.Equal(t, 200, statusCode)
+
+				var page ReceivedPaymentsPageResponse
+				err := json.Unmarshal(response, &page)
+				assert.Nil(t, err)
+				assert.Equal(t, 1, len(page.Payments))
+				assert.Equal(t, "7", page.Cursor)
+				assert.Equal(t, "testing", page.Payments[0].Memo)
+				assert.Equal(t, 200, *page.Payments[0].HookStatusCode)
+				mockRepository.AssertExpectations(t)
+			})
+
+			Convey("it should return server error when repository fails", func() {
+				mockRepository.On("GetReceivedPayments", filter).Return([]db.ReceivedPayment{}, errors.New("DB error")).Once()
+
+				statusCode, _ := getRequest(testServer, path)
+				assert.Equal(t, 500, statusCode)
+				mockRepository.AssertExpectations(t)
+			})
+		})
+	})
+}
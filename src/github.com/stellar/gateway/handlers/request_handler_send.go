@@ -11,6 +11,46 @@ import (
 )
 
 func (rh *RequestHandler) Send(w http.ResponseWriter, r *http.Request) {
+	operationMutator, memoMutator, ok := rh.sendOperation(w, r)
+	if !ok {
+		return
+	}
+
+	if rh.isDryRun(r) {
+		rh.dryRun(w, r, rh.Config.Accounts.GetIssuingAccountId(), operationMutator, memoMutator)
+		return
+	}
+
+	submitResponse, err := rh.TransactionSubmitter.SubmitTransaction(
+		r.Context(),
+		rh.Config.Accounts.GetIssuingAccountId(),
+		operationMutator,
+		memoMutator,
+	)
+	if err != nil {
+		log.Print("Error submitting transaction ", err)
+		errorSubmittingTransaction(w, err)
+		return
+	}
+
+	if submitResponse.Errors != nil {
+		errorTransactionFailed(w, submitResponse.Errors)
+		return
+	}
+
+	json, err := json.MarshalIndent(submitResponse, "", "  ")
+
+	if err != nil {
+		errorServerError(w)
+		return
+	}
+
+	w.Write(json)
+}
+
+// sendOperation builds payment operation and memo from /send request params.
+// When params are invalid, error response is written and ok is false.
+func (rh *RequestHandler) sendOperation(w http.ResponseWriter, r *http.Request) (operation, memoMutator interface{}, ok bool) {
 	destination := r.PostFormValue("destination")
 	assetCode := r.PostFormValue("asset_code")
 	amount := r.PostFormValue("amount")
@@ -35,16 +75,11 @@ func (rh *RequestHandler) Send(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	issuingKeypair, err := keypair.Parse(*rh.Config.Accounts.IssuingSeed)
-	if err != nil {
-		log.Print("Invalid issuingSeed")
-		errorServerError(w)
-		return
-	}
+	issuingAccountId := rh.Config.Accounts.GetIssuingAccountId()
 
 	operationMutator := b.Payment(
 		b.Destination{destinationObject.AccountId},
-		b.CreditAmount{assetCode, issuingKeypair.Address(), amount},
+		b.CreditAmount{assetCode, issuingAccountId, amount},
 	)
 	if operationMutator.Err != nil {
 		log.Print("Error creating operationMutator ", operationMutator.Err)
@@ -72,7 +107,6 @@ func (rh *RequestHandler) Send(w http.ResponseWriter, r *http.Request) {
 		memo = *destinationObject.Memo
 	}
 
-	var memoMutator interface{}
 	switch {
 	case memoType == "":
 		break
@@ -92,93 +126,7 @@ func (rh *RequestHandler) Send(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	submitResponse, err := rh.TransactionSubmitter.SubmitTransaction(
-		*rh.Config.Accounts.IssuingSeed,
-		operationMutator,
-		memoMutator,
-	)
-	if err != nil {
-		log.Print("Error submitting transaction ", err)
-		errorServerError(w)
-		return
-	}
-
-	if submitResponse.Errors != nil {
-		var errorString string
-		if submitResponse.Errors.OperationErrorCode != "" {
-			switch submitResponse.Errors.OperationErrorCode {
-			case "payment_malformed":
-				errorString = errorResponseString(
-					"payment_malformed",
-					"Operation is malformed.",
-				)
-			case "payment_underfunded":
-				errorString = errorResponseString(
-					"payment_underfunded",
-					"Not enough funds to send this transaction.",
-				)
-			case "payment_src_no_trust":
-				errorString = errorResponseString(
-					"payment_src_no_trust",
-					"No trustline on source account.",
-				)
-			case "payment_src_not_authorized":
-				errorString = errorResponseString(
-					"payment_src_not_authorized",
-					"Source not authorized to transfer.",
-				)
-			case "payment_no_destination":
-				errorString = errorResponseString(
-					"payment_no_destination",
-					"Destination account does not exist.",
-				)
-			case "payment_no_trust":
-				errorString = errorResponseString(
-					"payment_no_trust",
-					"Destination missing a trust line for asset.",
-				)
-			case "payment_not_authorized":
-				errorString = errorResponseString(
-					"payment_not_authorized",
-					"Destination not authorized to trust asset. It needs to be allowed first by using /authorize endpoint.",
-				)
-			case "payment_line_full":
-				errorString = errorResponseString(
-					"payment_line_full",
-					"Sending this payment would make a destination go above their limit.",
-				)
-			case "payment_no_issuer":
-				errorString = errorResponseString(
-					"payment_no_issuer",
-					"Missing issuer on asset.",
-				)
-			default:
-				errorServerError(w)
-				return
-			}
-		} else if submitResponse.Errors.TransactionErrorCode != "" {
-			switch submitResponse.Errors.TransactionErrorCode {
-			case "transaction_bad_seq":
-				errorString = errorResponseString(
-					"transaction_bad_seq",
-					"Bad Sequence. Please, try again.",
-				)
-			default:
-				errorServerError(w)
-				return
-			}
-		}
-
-		errorBadRequest(w, errorString)
-		return
-	}
-
-	json, err := json.MarshalIndent(submitResponse, "", "  ")
-
-	if err != nil {
-		errorServerError(w)
-		return
-	}
-
-	w.Write(json)
+	operation = operationMutator
+	ok = true
+	return
 }
@@ -161,7 +161,7 @@ func TestRequestHandlerSend(t *testing.T) {
 
 				var ledger uint64
 				ledger = 1988728
-				expectedSubmitResponse := horizon.SubmitTransactionResponse{&ledger, nil, nil}
+				expectedSubmitResponse := horizon.SubmitTransactionResponse{Ledger: &ledger}
 
 				operation := b.Payment(
 					b.Destination{"GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632"},
@@ -174,7 +174,7 @@ func TestRequestHandlerSend(t *testing.T) {
 
 				mockTransactionSubmitter.On(
 					"SubmitTransaction",
-					*config.Accounts.IssuingSeed,
+					config.Accounts.GetIssuingAccountId(),
 					operation,
 					nil,
 				).Return(expectedSubmitResponse, nil).Once()
@@ -214,7 +214,7 @@ func TestRequestHandlerSend(t *testing.T) {
 				Convey("transaction fails", func() {
 					mockTransactionSubmitter.On(
 						"SubmitTransaction",
-						*config.Accounts.IssuingSeed,
+						config.Accounts.GetIssuingAccountId(),
 						operation,
 						nil,
 					).Return(
@@ -231,6 +231,80 @@ func TestRequestHandlerSend(t *testing.T) {
 					})
 				})
 
+				Convey("transaction fails in horizon", func() {
+					mockTransactionSubmitter.On(
+						"SubmitTransaction",
+						config.Accounts.GetIssuingAccountId(),
+						operation,
+						nil,
+					).Return(
+						horizon.SubmitTransactionResponse{
+							Errors: &horizon.SubmitTransactionResponseError{
+								TransactionErrorCode: "transaction_insufficient_fee",
+							},
+						},
+						nil,
+					).Once()
+
+					Convey("it should return error code", func() {
+						statusCode, response := getResponse(testServer, params)
+						responseString := strings.TrimSpace(string(response))
+						assert.Equal(t, 400, statusCode)
+						assert.Equal(t, errorResponseString("transaction_insufficient_fee", "Transaction fee is too small."), responseString)
+						mockTransactionSubmitter.AssertExpectations(t)
+					})
+				})
+
+				Convey("operation fails in horizon", func() {
+					mockTransactionSubmitter.On(
+						"SubmitTransaction",
+						config.Accounts.GetIssuingAccountId(),
+						operation,
+						nil,
+					).Return(
+						horizon.SubmitTransactionResponse{
+							Errors: &horizon.SubmitTransactionResponseError{
+								TransactionErrorCode: "transaction_failed",
+								OperationErrorCode:   "payment_no_trust",
+								OperationErrorCodes:  []string{"payment_no_trust"},
+							},
+						},
+						nil,
+					).Once()
+
+					Convey("it should return operation error code", func() {
+						statusCode, response := getResponse(testServer, params)
+						responseString := strings.TrimSpace(string(response))
+						assert.Equal(t, 400, statusCode)
+						assert.Equal(t, errorResponseString("payment_no_trust", "Destination missing a trust line for asset."), responseString)
+						mockTransactionSubmitter.AssertExpectations(t)
+					})
+				})
+
+				Convey("transaction fails with internal error", func() {
+					mockTransactionSubmitter.On(
+						"SubmitTransaction",
+						config.Accounts.GetIssuingAccountId(),
+						operation,
+						nil,
+					).Return(
+						horizon.SubmitTransactionResponse{
+							Errors: &horizon.SubmitTransactionResponseError{
+								TransactionErrorCode: "transaction_internal_error",
+							},
+						},
+						nil,
+					).Once()
+
+					Convey("it should return server error", func() {
+						statusCode, response := getResponse(testServer, params)
+						responseString := strings.TrimSpace(string(response))
+						assert.Equal(t, 500, statusCode)
+						assert.Equal(t, getServerErrorResponseString(), responseString)
+						mockTransactionSubmitter.AssertExpectations(t)
+					})
+				})
+
 				Convey("transaction succeeds (no memo)", func() {
 					var ledger uint64
 					ledger = 100
@@ -240,7 +314,7 @@ func TestRequestHandlerSend(t *testing.T) {
 
 					mockTransactionSubmitter.On(
 						"SubmitTransaction",
-						*config.Accounts.IssuingSeed,
+						config.Accounts.GetIssuingAccountId(),
 						operation,
 						nil,
 					).Return(expectedSubmitResponse, nil).Once()
@@ -269,7 +343,7 @@ func TestRequestHandlerSend(t *testing.T) {
 
 					mockTransactionSubmitter.On(
 						"SubmitTransaction",
-						*config.Accounts.IssuingSeed,
+						config.Accounts.GetIssuingAccountId(),
 						operation,
 						memo,
 					).Return(expectedSubmitResponse, nil).Once()
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	log "github.com/Sirupsen/logrus"
+	"net/http"
+	"time"
+
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/submitter"
+	b "github.com/stellar/go-stellar-base/build"
+	"github.com/stellar/go-stellar-base/keypair"
+	"github.com/stellar/go-stellar-base/strkey"
+	"github.com/stellar/go-stellar-base/xdr"
+)
+
+// Submit submits transaction envelope signed outside of gateway server
+// (ex. built using one of /build/* endpoints).
+func (rh *RequestHandler) Submit(w http.ResponseWriter, r *http.Request) {
+	txeB64 := r.PostFormValue("transaction_envelope")
+
+	var envelope xdr.TransactionEnvelope
+	err := xdr.SafeUnmarshalBase64(txeB64, &envelope)
+	if err != nil || envelope.Tx.SourceAccount.Ed25519 == nil {
+		log.WithFields(log.Fields{"err": err}).Print("Cannot decode transaction envelope")
+		errorBadRequest(w, errorResponseString("invalid_transaction_envelope", "transaction_envelope parameter is invalid"))
+		return
+	}
+
+	if len(envelope.Signatures) == 0 {
+		errorBadRequest(w, errorResponseString("transaction_not_signed", "Transaction is not signed"))
+		return
+	}
+
+	hashHex, err := submitter.TransactionHash(&envelope.Tx, b.Network{rh.Config.NetworkPassphrase})
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Cannot compute transaction hash")
+		errorServerError(w)
+		return
+	}
+	hash, _ := hex.DecodeString(hashHex)
+
+	source, err := strkey.Encode(strkey.VersionByteAccountID, envelope.Tx.SourceAccount.Ed25519[:])
+	if err != nil {
+		errorBadRequest(w, errorResponseString("invalid_transaction_envelope", "transaction_envelope parameter is invalid"))
+		return
+	}
+
+	accountResponse, err := rh.Horizon.LoadAccount(r.Context(), source)
+	if err == horizon.ErrNotFound {
+		errorBadRequest(w, errorResponseString("source_not_exist", "source account does not exist"))
+		return
+	}
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Cannot load source account")
+		errorServerError(w)
+		return
+	}
+
+	// Signature of any source account signer must be valid for a hash computed
+	// with configured network passphrase.
+	keys := []string{source}
+	for _, accountSigner := range accountResponse.Signers {
+		keys = append(keys, accountSigner.GetPublicKey())
+	}
+
+	if !hasValidSignature(envelope.Signatures, hash, keys) {
+		errorBadRequest(w, errorResponseString("invalid_signature", "Transaction is not signed by source account signers for the configured network"))
+		return
+	}
+
+	rh.submitTransaction(w, r, source, txeB64)
+}
+
+// submitTransaction submits signed transaction envelope of /payment and
+// /submit and writes the response. Transaction is saved before submitting so
+// TransactionRecoverer can resolve it when the result is unknown.
+func (rh *RequestHandler) submitTransaction(w http.ResponseWriter, r *http.Request, source, txeB64 string) {
+	sentTransaction := &db.SentTransaction{
+		Status:         "sending",
+		Source:         source,
+		SubmittedAt:    time.Now(),
+		EnvelopeXdr:    txeB64,
+		IdempotencyKey: submitter.IdempotencyKeyFromContext(r.Context()),
+	}
+	err := rh.EntityManager.Persist(sentTransaction)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Cannot save transaction")
+		errorServerError(w)
+		return
+	}
+
+	// Transaction is already saved so submission is not cancelled when client disconnects
+	submitResponse, err := rh.Horizon.SubmitTransaction(context.Background(), txeB64)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "horizon": submitResponse.ServerUrl}).Error("Error submitting transaction")
+		errorServerError(w)
+		return
+	}
+
+	sentTransaction.SetHorizonUrl(submitResponse.ServerUrl)
+
+	if submitResponse.Ledger != nil {
+		sentTransaction.MarkSucceeded(*submitResponse.Ledger)
+	} else if submitResponse.Extras != nil {
+		sentTransaction.MarkFailed(submitResponse.Extras.ResultXdr)
+	} else {
+		// Rejected without a transaction result (ex. malformed envelope)
+		sentTransaction.Status = "failure"
+	}
+	err = rh.EntityManager.Persist(sentTransaction)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Cannot save transaction")
+	}
+
+	if submitResponse.Ledger == nil {
+		if submitResponse.Errors == nil {
+			errorBadRequest(w, errorResponseString("transaction_rejected", "Transaction was rejected by horizon."))
+			return
+		}
+		errorTransactionFailed(w, submitResponse.Errors)
+		return
+	}
+
+	response, err := json.MarshalIndent(submitResponse, "", "  ")
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Cannot Marshal submitResponse")
+		errorServerError(w)
+		return
+	}
+
+	w.Write(response)
+}
+
+func hasValidSignature(signatures []xdr.DecoratedSignature, hash []byte, keys []string) bool {
+	for _, key := range keys {
+		kp, err := keypair.Parse(key)
+		if err != nil {
+			continue
+		}
+
+		hint := kp.Hint()
+		for _, signature := range signatures {
+			if signature.Hint != xdr.SignatureHint(hint) {
+				continue
+			}
+			if kp.Verify(hash, signature.Signature) == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
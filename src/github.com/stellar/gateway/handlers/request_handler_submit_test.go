@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stellar/gateway/config"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/go-stellar-base/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRequestHandlerSubmit(t *testing.T) {
+	mockHorizon := new(mocks.MockHorizon)
+	mockEntityManager := new(mocks.MockEntityManager)
+
+	config := config.Config{
+		NetworkPassphrase: "Test SDF Network ; September 2015",
+	}
+
+	requestHandler := RequestHandler{Config: &config, Horizon: mockHorizon, EntityManager: mockEntityManager}
+	testServer := httptest.NewServer(http.HandlerFunc(requestHandler.Submit))
+	defer testServer.Close()
+
+	source := "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR"
+	// Payment from GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR with sequence 101
+	envelopeXdr := "AAAAAPiPgLJeTTGvfJwXZLmaadaEOIofYnPmlu8OSIVyRFVHAAAAZAAAAAAAAABlAAAAAAAAAAAAAAABAAAAAAAAAAEAAAAA5IVbm6A8mbgc/apAizxmBf4zZmqbedR3Ke+MTa7pjVYAAAAAAAAAADuaygAAAAAAAAAAAXJEVUcAAABAIac0GpNfvtwTDm2Xws6UNdfpWML2EDwUBi/GvghAKhgUx6Ww7uRH8yzHuwfPcAGxXfAnHDjcEJQ1pBr4PHJRDg=="
+
+	Convey("Given submit request", t, func() {
+		config.NetworkPassphrase = "Test SDF Network ; September 2015"
+
+		Convey("When transaction_envelope is invalid", func() {
+			Convey("it should return error", func() {
+				statusCode, response := getResponse(testServer, url.Values{"transaction_envelope": {"AAAA"}})
+				responseString := strings.TrimSpace(string(response))
+				assert.Equal(t, 400, statusCode)
+				assert.Equal(t, errorResponseString("invalid_transaction_envelope", "transaction_envelope parameter is invalid"), responseString)
+			})
+		})
+
+		Convey("When transaction is not signed", func() {
+			var envelope xdr.TransactionEnvelope
+			err := xdr.SafeUnmarshalBase64(envelopeXdr, &envelope)
+			assert.Nil(t, err)
+			envelope.Signatures = nil
+			unsignedXdr, err := xdr.MarshalBase64(envelope)
+			assert.Nil(t, err)
+
+			Convey("it should return error", func() {
+				statusCode, response := getResponse(testServer, url.Values{"transaction_envelope": {unsignedXdr}})
+				responseString := strings.TrimSpace(string(response))
+				assert.Equal(t, 400, statusCode)
+				assert.Equal(t, errorResponseString("transaction_not_signed", "Transaction is not signed"), responseString)
+			})
+		})
+
+		Convey("When transaction is signed for a different network", func() {
+			config.NetworkPassphrase = "Public Global Stellar Network ; September 2015"
+			mockHorizon.On("LoadAccount", source).Return(
+				horizon.AccountResponse{AccountId: source, SequenceNumber: "100"},
+				nil,
+			).Once()
+
+			Convey("it should return error", func() {
+				statusCode, response := getResponse(testServer, url.Values{"transaction_envelope": {envelopeXdr}})
+				responseString := strings.TrimSpace(string(response))
+				assert.Equal(t, 400, statusCode)
+				assert.Equal(t, errorResponseString("invalid_signature", "Transaction is not signed by source account signers for the configured network"), responseString)
+				mockHorizon.AssertExpectations(t)
+			})
+		})
+
+		Convey("When transaction is valid", func() {
+			mockHorizon.On("LoadAccount", source).Return(
+				horizon.AccountResponse{AccountId: source, SequenceNumber: "100"},
+				nil,
+			).Once()
+			mockEntityManager.On("Persist", mock.AnythingOfType("*db.SentTransaction")).Return(nil).Twice()
+
+			Convey("it should submit transaction and save it", func() {
+				ledger := uint64(1234)
+				mockHorizon.On("SubmitTransaction", envelopeXdr).Return(
+					horizon.SubmitTransactionResponse{Ledger: &ledger},
+					nil,
+				).Once()
+
+				statusCode, _ := getResponse(testServer, url.Values{"transaction_envelope": {envelopeXdr}})
+				assert.Equal(t, 200, statusCode)
+				mockHorizon.AssertExpectations(t)
+				mockEntityManager.AssertExpectations(t)
+			})
+
+			Convey("it should return error code when transaction fails", func() {
+				mockHorizon.On("SubmitTransaction", envelopeXdr).Return(
+					horizon.SubmitTransactionResponse{
+						Errors: &horizon.SubmitTransactionResponseError{
+							TransactionErrorCode: "transaction_failed",
+							OperationErrorCode:   "payment_underfunded",
+						},
+						Extras: &horizon.SubmitTransactionResponseExtras{ResultXdr: "AAAAAAAAAAD////4AAAAAA=="},
+					},
+					nil,
+				).Once()
+
+				statusCode, response := getResponse(testServer, url.Values{"transaction_envelope": {envelopeXdr}})
+				responseString := strings.TrimSpace(string(response))
+				assert.Equal(t, 400, statusCode)
+				assert.Equal(t, errorResponseString("payment_underfunded", "Not enough funds to send this transaction."), responseString)
+				mockHorizon.AssertExpectations(t)
+				mockEntityManager.AssertExpectations(t)
+			})
+
+			Convey("it should return error and mark transaction failed when horizon rejects it without result", func() {
+				mockEntityManager.ExpectedCalls = nil
+				var sentTransaction *db.SentTransaction
+				mockEntityManager.On("Persist", mock.AnythingOfType("*db.SentTransaction")).Run(func(args mock.Arguments) {
+					sentTransaction = args.Get(0).(*db.SentTransaction)
+				}).Return(nil).Twice()
+				mockHorizon.On("SubmitTransaction", envelopeXdr).Return(
+					horizon.SubmitTransactionResponse{},
+					nil,
+				).Once()
+
+				statusCode, response := getResponse(testServer, url.Values{"transaction_envelope": {envelopeXdr}})
+				responseString := strings.TrimSpace(string(response))
+				assert.Equal(t, 400, statusCode)
+				assert.Equal(t, errorResponseString("transaction_rejected", "Transaction was rejected by horizon."), responseString)
+				assert.Equal(t, "failure", sentTransaction.Status)
+				mockHorizon.AssertExpectations(t)
+				mockEntityManager.AssertExpectations(t)
+			})
+		})
+	})
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"encoding/json"
+	log "github.com/Sirupsen/logrus"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/submitter"
+	b "github.com/stellar/go-stellar-base/build"
+	"github.com/stellar/go-stellar-base/keypair"
+	"github.com/stellar/go-stellar-base/xdr"
+	"github.com/zenazn/goji/web"
+)
+
+func (rh *RequestHandler) Transaction(c web.C, w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(c.URLParams["id"], 10, 64)
+	if err != nil {
+		errorBadRequest(w, errorResponseString("invalid_id", "id parameter is invalid"))
+		return
+	}
+
+	transaction, err := rh.Repository.GetSentTransactionById(id)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error loading transaction")
+		errorServerError(w)
+		return
+	}
+
+	if transaction == nil {
+		errorNotFound(w, errorResponseString("not_found", "Transaction not found"))
+		return
+	}
+
+	json, err := json.MarshalIndent(rh.newTransactionResponse(*transaction), "", "  ")
+	if err != nil {
+		errorServerError(w)
+		return
+	}
+
+	w.Write(json)
+}
+
+func (rh *RequestHandler) Transactions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := db.SentTransactionsFilter{
+		Status: query.Get("status"),
+		Source: query.Get("source"),
+	}
+
+	switch filter.Status {
+	case "", "sending", "success", "failure":
+		break
+	default:
+		errorBadRequest(w, errorResponseString("invalid_status", "status parameter is invalid"))
+		return
+	}
+
+	if filter.Source != "" {
+		_, err := keypair.Parse(filter.Source)
+		if err != nil {
+			errorBadRequest(w, errorResponseString("invalid_source", "source parameter is invalid"))
+			return
+		}
+	}
+
+	if since := query.Get("since"); since != "" {
+		sinceTime, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			errorBadRequest(w, errorResponseString("invalid_since", "since parameter must be a RFC 3339 date"))
+			return
+		}
+		filter.Since = &sinceTime
+	}
+
+	var ok bool
+	filter.Cursor, filter.Limit, ok = parsePageParams(w, query)
+	if !ok {
+		return
+	}
+
+	transactions, err := rh.Repository.GetSentTransactions(filter)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error loading transactions")
+		errorServerError(w)
+		return
+	}
+
+	page := TransactionsPageResponse{
+		Transactions: []TransactionResponse{},
+		Cursor:       strconv.FormatInt(filter.Cursor, 10),
+	}
+	for _, transaction := range transactions {
+		page.Transactions = append(page.Transactions, rh.newTransactionResponse(transaction))
+		page.Cursor = strconv.FormatInt(*transaction.Id, 10)
+	}
+
+	json, err := json.MarshalIndent(page, "", "  ")
+	if err != nil {
+		errorServerError(w)
+		return
+	}
+
+	w.Write(json)
+}
+
+func (rh *RequestHandler) newTransactionResponse(transaction db.SentTransaction) (response TransactionResponse) {
+	response = TransactionResponse{
+		Id:          *transaction.Id,
+		Status:      transaction.Status,
+		Source:      transaction.Source,
+		SubmittedAt: transaction.SubmittedAt,
+		SucceededAt: transaction.SucceededAt,
+		Ledger:      transaction.Ledger,
+		EnvelopeXdr: transaction.EnvelopeXdr,
+		ResultXdr:   transaction.ResultXdr,
+		HorizonUrl:  transaction.HorizonUrl,
+		RetryOf:     transaction.RetryOf,
+	}
+
+	var envelope xdr.TransactionEnvelope
+	err := xdr.SafeUnmarshalBase64(transaction.EnvelopeXdr, &envelope)
+	if err == nil {
+		response.Hash, err = submitter.TransactionHash(&envelope.Tx, b.Network{rh.Config.NetworkPassphrase})
+	}
+	if err != nil {
+		log.WithFields(log.Fields{"id": *transaction.Id, "err": err}).Warn("Cannot compute transaction hash")
+	}
+
+	if transaction.Status == "failure" && transaction.ResultXdr != nil && *transaction.ResultXdr != "" {
+		response.Errors, err = horizon.DecodeTransactionResult(*transaction.ResultXdr)
+		if err != nil {
+			log.WithFields(log.Fields{"id": *transaction.Id, "err": err}).Warn("Cannot decode transaction result")
+		}
+	}
+	return
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stellar/gateway/config"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/zenazn/goji/web"
+)
+
+func getRequest(testServer *httptest.Server, path string) (int, []byte) {
+	res, err := http.Get(testServer.URL + path)
+	if err != nil {
+		panic(err)
+	}
+	response, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		panic(err)
+	}
+	return res.StatusCode, response
+}
+
+func TestRequestHandlerTransactions(t *testing.T) {
+	mockRepository := new(mocks.MockRepository)
+
+	config := config.Config{
+		NetworkPassphrase: "Test SDF Network ; September 2015",
+	}
+
+	requestHandler := RequestHandler{
+		Config:     &config,
+		Repository: mockRepository,
+	}
+
+	mux := web.New()
+	mux.Get("/transactions", requestHandler.Transactions)
+	mux.Get("/transactions/:id", requestHandler.Transaction)
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	id := int64(5)
+	ledger := uint64(1234)
+	// Payment from GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR with sequence 101
+	envelopeXdr := "AAAAAPiPgLJeTTGvfJwXZLmaadaEOIofYnPmlu8OSIVyRFVHAAAAZAAAAAAAAABlAAAAAAAAAAAAAAABAAAAAAAAAAEAAAAA5IVbm6A8mbgc/apAizxmBf4zZmqbedR3Ke+MTa7pjVYAAAAAAAAAADuaygAAAAAAAAAAAXJEVUcAAABAIac0GpNfvtwTDm2Xws6UNdfpWML2EDwUBi/GvghAKhgUx6Ww7uRH8yzHuwfPcAGxXfAnHDjcEJQ1pBr4PHJRDg=="
+	transaction := db.SentTransaction{
+		Id:          &id,
+		Status:      "success",
+		Source:      "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR",
+		SubmittedAt: time.Now(),
+		Ledger:      &ledger,
+		EnvelopeXdr: envelopeXdr,
+	}
+
+	Convey("Given transaction request", t, func() {
+		Convey("When id is invalid", func() {
+			Convey("it should return error", func() {
+				statusCode, response := getRequest(testServer, "/transactions/abc")
+				responseString := strings.TrimSpace(string(response))
+				assert.Equal(t, 400, statusCode)
+				assert.Equal(t, errorResponseString("invalid_id", "id parameter is invalid"), responseString)
+			})
+		})
+
+		Convey("When transaction does not exist", func() {
+			mockRepository.On("GetSentTransactionById", int64(6)).Return((*db.SentTransaction)(nil), nil).Once()
+
+			Convey("it should return error", func() {
+				statusCode, response := getRequest(testServer, "/transactions/6")
+				responseString := strings.TrimSpace(string(response))
+				assert.Equal(t, 404, statusCode)
+				assert.Equal(t, errorResponseString("not_found", "Transaction not found"), responseString)
+				mockRepository.AssertExpectations(t)
+			})
+		})
+
+		Convey("When transaction exists", func() {
+			mockRepository.On("GetSentTransactionById", int64(5)).Return(&transaction, nil).Once()
+
+			Convey("it should return transaction", func() {
+				statusCode, response := getRequest(testServer, "/transactions/5")
+				assert.Equal(t, 200, statusCode)
+
+				var transactionResponse TransactionResponse
+				err := json.Unmarshal(response, &transactionResponse)
+				assert.Nil(t, err)
+				assert.Equal(t, int64(5), transactionResponse.Id)
+				assert.Equal(t, "success", transactionResponse.Status)
+				assert.Equal(t, ledger, *transactionResponse.Ledger)
+				assert.Equal(t, "588e1987755d2d97225e34636fc3971da8ffa0b8be86eaf64899fd2ce36bcd29", transactionResponse.Hash)
+				assert.Nil(t, transactionResponse.Errors)
+				mockRepository.AssertExpectations(t)
+			})
+		})
+	})
+
+	Convey("Given transactions request", t, func() {
+		Convey("When status is invalid", func() {
+			Convey("it should return error", func() {
+				statusCode, response := getRequest(testServer, "/transactions?status=unknown")
+				responseString := strings.TrimSpace(string(response))
+				assert.Equal(t, 400, statusCode)
+				assert.Equal(t, errorResponseString("invalid_status", "status parameter is invalid"), responseString)
+			})
+		})
+
+		Convey("When since is invalid", func() {
+			Convey("it should return error", func() {
+				statusCode, response := getRequest(testServer, "/transactions?since=yesterday")
+				responseString := strings.TrimSpace(string(response))
+				assert.Equal(t, 400, statusCode)
+				assert.Equal(t, errorResponseString("invalid_since", "since parameter must be a RFC 3339 date"), responseString)
+			})
+		})
+
+		Convey("When params are valid", func() {
+			since, _ := time.Parse(time.RFC3339, "2016-01-02T15:04:05Z")
+			filter := db.SentTransactionsFilter{
+				Status: "success",
+				Source: "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR",
+				Since:  &since,
+				Cursor: 4,
+				Limit:  20,
+			}
+
+			Convey("it should return transactions page", func() {
+				mockRepository.On("GetSentTransactions", filter).Return([]db.SentTransaction{transaction}, nil).Once()
+
+				statusCode, response := getRequest(testServer, "/transactions?status=success&source=GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR&since=2016-01-02T15:04:05Z&cursor=4&limit=20")
+				assert.Equal(t, 200, statusCode)
+
+				var page TransactionsPageResponse
+				err := json.Unmarshal(response, &page)
+				assert.Nil(t, err)
+				assert.Equal(t, 1, len(page.Transactions))
+				assert.Equal(t, "5", page.Cursor)
+				mockRepository.AssertExpectations(t)
+			})
+
+			Convey("it should return server error when repository fails", func() {
+				mockRepository.On("GetSentTransactions", filter).Return([]db.SentTransaction{}, errors.New("DB error")).Once()
+
+				statusCode, _ := getRequest(testServer, "/transactions?status=success&source=GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR&since=2016-01-02T15:04:05Z&cursor=4&limit=20")
+				assert.Equal(t, 500, statusCode)
+				mockRepository.AssertExpectations(t)
+			})
+		})
+	})
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"encoding/json"
+	log "github.com/Sirupsen/logrus"
+	"net/http"
+	"strconv"
+
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/go-stellar-base/keypair"
+)
+
+// TrustlineAuthorizations lists decisions about trustlines made by the
+// trustline watcher.
+func (rh *RequestHandler) TrustlineAuthorizations(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := db.TrustlineAuthorizationsFilter{
+		AccountId: query.Get("account_id"),
+		Status:    query.Get("status"),
+	}
+
+	if filter.AccountId != "" {
+		_, err := keypair.Parse(filter.AccountId)
+		if err != nil {
+			errorBadRequest(w, errorResponseString("invalid_account_id", "account_id parameter is invalid"))
+			return
+		}
+	}
+
+	switch filter.Status {
+	case "", "authorized", "rejected", "pending", "failed":
+		break
+	default:
+		errorBadRequest(w, errorResponseString("invalid_status", "status parameter is invalid"))
+		return
+	}
+
+	var ok bool
+	filter.Cursor, filter.Limit, ok = parsePageParams(w, query)
+	if !ok {
+		return
+	}
+
+	authorizations, err := rh.Repository.GetTrustlineAuthorizations(filter)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error loading trustline authorizations")
+		errorServerError(w)
+		return
+	}
+
+	page := TrustlineAuthorizationsPageResponse{
+		Authorizations: []TrustlineAuthorizationResponse{},
+		Cursor:         strconv.FormatInt(filter.Cursor, 10),
+	}
+	for _, authorization := range authorizations {
+		page.Authorizations = append(page.Authorizations, TrustlineAuthorizationResponse{
+			Id:              *authorization.Id,
+			OperationId:     authorization.OperationId,
+			AccountId:       authorization.AccountId,
+			AssetCode:       authorization.AssetCode,
+			Status:          authorization.Status,
+			Reason:          authorization.Reason,
+			TransactionHash: authorization.TransactionHash,
+			CreatedAt:       authorization.CreatedAt,
+		})
+		page.Cursor = strconv.FormatInt(*authorization.Id, 10)
+	}
+
+	json, err := json.MarshalIndent(page, "", "  ")
+	if err != nil {
+		errorServerError(w)
+		return
+	}
+
+	w.Write(json)
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/zenazn/goji/web"
+)
+
+func TestRequestHandlerTrustlineAuthorizations(t *testing.T) {
+	mockRepository := new(mocks.MockRepository)
+
+	requestHandler := RequestHandler{Repository: mockRepository}
+
+	mux := web.New()
+	mux.Get("/admin/trustline_authorizations", requestHandler.TrustlineAuthorizations)
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	id := int64(5)
+	reason := "account_not_allowed"
+	authorization := db.TrustlineAuthorization{
+		Id:          &id,
+		OperationId: "1",
+		AccountId:   "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB",
+		AssetCode:   "USD",
+		Status:      "rejected",
+		Reason:      &reason,
+		CreatedAt:   time.Now(),
+	}
+
+	Convey("Given trustline authorizations request", t, func() {
+		Convey("When account_id is invalid", func() {
+			Convey("it should return error", func() {
+				statusCode, response := getRequest(testServer, "/admin/trustline_authorizations?account_id=GBAD")
+				responseString := strings.TrimSpace(string(response))
+				assert.Equal(t, 400, statusCode)
+				assert.Equal(t, errorResponseString("invalid_account_id", "account_id parameter is invalid"), responseString)
+			})
+		})
+
+		Convey("When status is invalid", func() {
+			Convey("it should return error", func() {
+				statusCode, response := getRequest(testServer, "/admin/trustline_authorizations?status=unknown")
+				responseString := strings.TrimSpace(string(response))
+				assert.Equal(t, 400, statusCode)
+				assert.Equal(t, errorResponseString("invalid_status", "status parameter is invalid"), responseString)
+			})
+		})
+
+		Convey("When params are valid", func() {
+			filter := db.TrustlineAuthorizationsFilter{
+				AccountId: "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB",
+				Status:    "rejected",
+				Cursor:    4,
+				Limit:     20,
+			}
+
+			Convey("it should return authorizations page", func() {
+				mockRepository.On("GetTrustlineAuthorizations", filter).Return([]db.TrustlineAuthorization{authorization}, nil).Once()
+
+				statusCode, response := getRequest(testServer, "/admin/trustline_authorizations?account_id=GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB&status=rejected&cursor=4&limit=20")
+				assert.Equal(t, 200, statusCode)
+
+				var page TrustlineAuthorizationsPageResponse
+				err := json.Unmarshal(response, &page)
+				assert.Nil(t, err)
+				if assert.Equal(t, 1, len(page.Authorizations)) {
+					assert.Equal(t, "rejected", page.Authorizations[0].Status)
+					assert.Equal(t, reason, *page.Authorizations[0].Reason)
+				}
+				assert.Equal(t, "5", page.Cursor)
+				mockRepository.AssertExpectations(t)
+			})
+		})
+	})
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/submitter"
+)
+
+// transactionErrorMessages contains messages of transaction and operation
+// error codes decoded by horizon.DecodeTransactionResult. Codes that are not
+// here (ex. transaction_internal_error) are returned as server errors.
+var transactionErrorMessages = map[string]string{
+	"transaction_failed":               "One of the operations failed.",
+	"transaction_too_early":            "Transaction submitted before its time bounds.",
+	"transaction_too_late":             "Transaction submitted after its time bounds.",
+	"transaction_missing_operation":    "Transaction has no operations.",
+	"transaction_bad_seq":              "Bad Sequence. Please, try again.",
+	"transaction_bad_auth":             "Transaction is not signed by enough signers of the source account.",
+	"transaction_insufficient_balance": "Source account does not have enough funds to pay the fee.",
+	"transaction_no_account":           "Source account does not exist.",
+	"transaction_insufficient_fee":     "Transaction fee is too small.",
+	"transaction_bad_auth_extra":       "Transaction has unused signatures.",
+
+	"operation_bad_auth":   "Operation is not signed by enough signers of its source account.",
+	"operation_no_account": "Operation source account does not exist.",
+
+	"create_account_malformed":     "Operation is malformed.",
+	"create_account_underfunded":   "Not enough funds to create an account.",
+	"create_account_low_reserve":   "Starting balance is below the minimum account balance.",
+	"create_account_already_exist": "Destination account already exists.",
+
+	"payment_malformed":          "Operation is malformed.",
+	"payment_underfunded":        "Not enough funds to send this transaction.",
+	"payment_src_no_trust":       "No trustline on source account.",
+	"payment_src_not_authorized": "Source not authorized to transfer.",
+	"payment_no_destination":     "Destination account does not exist.",
+	"payment_no_trust":           "Destination missing a trust line for asset.",
+	"payment_not_authorized":     "Destination not authorized to trust asset. It needs to be allowed first by using /authorize endpoint.",
+	"payment_line_full":          "Sending this payment would make a destination go above their limit.",
+	"payment_no_issuer":          "Missing issuer on asset.",
+
+	"path_payment_malformed":          "Operation is malformed.",
+	"path_payment_underfunded":        "Not enough funds to send this transaction.",
+	"path_payment_src_no_trust":       "No trustline on source account.",
+	"path_payment_src_not_authorized": "Source not authorized to transfer.",
+	"path_payment_no_destination":     "Destination account does not exist.",
+	"path_payment_no_trust":           "Destination missing a trust line for asset.",
+	"path_payment_not_authorized":     "Destination not authorized to trust asset.",
+	"path_payment_line_full":          "Sending this payment would make a destination go above their limit.",
+	"path_payment_no_issuer":          "Missing issuer on one of the assets.",
+	"path_payment_too_few_offers":     "Not enough offers to satisfy the path.",
+	"path_payment_offer_cross_self":   "Path would cross one of the source account offers.",
+	"path_payment_over_sendmax":       "Path payment would exceed send max.",
+
+	"manage_offer_malformed":           "Operation is malformed.",
+	"manage_offer_sell_no_trust":       "No trustline for the selling asset.",
+	"manage_offer_buy_no_trust":        "No trustline for the buying asset.",
+	"manage_offer_sell_not_authorized": "Not authorized to sell the asset.",
+	"manage_offer_buy_not_authorized":  "Not authorized to buy the asset.",
+	"manage_offer_line_full":           "Buying would make the account go above its trustline limit.",
+	"manage_offer_underfunded":         "Not enough funds to create the offer.",
+	"manage_offer_cross_self":          "Offer would cross another offer of the same account.",
+	"manage_offer_sell_no_issuer":      "Missing issuer of the selling asset.",
+	"manage_offer_buy_no_issuer":       "Missing issuer of the buying asset.",
+	"manage_offer_not_found":           "Offer does not exist.",
+	"manage_offer_low_reserve":         "Not enough funds to meet the minimum account balance.",
+
+	"set_options_low_reserve":            "Not enough funds to meet the minimum account balance.",
+	"set_options_too_many_signers":       "Maximum number of signers reached.",
+	"set_options_bad_flags":              "Flags are invalid.",
+	"set_options_invalid_inflation":      "Inflation destination does not exist.",
+	"set_options_cant_change":            "Account options cannot be changed.",
+	"set_options_unknown_flag":           "Unknown flag.",
+	"set_options_threshold_out_of_range": "Threshold or weight is out of range.",
+	"set_options_bad_signer":             "Signer is invalid.",
+	"set_options_invalid_home_domain":    "Home domain is invalid.",
+
+	"change_trust_malformed":     "Operation is malformed.",
+	"change_trust_no_issuer":     "Asset issuer does not exist.",
+	"change_trust_invalid_limit": "Limit is below the current balance.",
+	"change_trust_low_reserve":   "Not enough funds to meet the minimum account balance.",
+
+	"allow_trust_malformed":          "Asset name is malformed.",
+	"allow_trust_not_trustline":      "Trustor does not have a trustline yet.",
+	"allow_trust_trust_not_required": "Authorizing account does not require allowing trust. Set AUTH_REQUIRED_FLAG on your account to use this feature.",
+	"allow_trust_trust_cant_revoke":  "Authorizing account has AUTH_REVOCABLE_FLAG set. Can't revoke the trustline.",
+
+	"account_merge_malformed":       "Cannot merge an account into itself.",
+	"account_merge_no_account":      "Destination account does not exist.",
+	"account_merge_immutable_set":   "Source account has AUTH_IMMUTABLE_FLAG set.",
+	"account_merge_has_sub_entries": "Source account has trustlines or offers.",
+
+	"inflation_not_time": "Inflation cannot be run yet.",
+}
+
+// errorTransactionFailed writes an error response with a code of a failed
+// operation (or transaction when no operation failed). Unknown codes are
+// returned as server errors.
+func errorTransactionFailed(w http.ResponseWriter, errors *horizon.SubmitTransactionResponseError) {
+	statusCode, responseString := transactionFailedResponse(errors)
+	http.Error(w, responseString, statusCode)
+}
+
+// transactionFailedResponse returns status code and body of the response
+// written by errorTransactionFailed.
+func transactionFailedResponse(errors *horizon.SubmitTransactionResponseError) (statusCode int, responseString string) {
+	code := errors.OperationErrorCode
+	if code == "" {
+		code = errors.TransactionErrorCode
+	}
+
+	message, ok := transactionErrorMessages[code]
+	if !ok {
+		return http.StatusInternalServerError, getServerErrorResponseString()
+	}
+
+	return http.StatusBadRequest, errorResponseString(code, message)
+}
+
+// errorSubmittingTransaction writes an error response for an error returned
+// by TransactionSubmitter. Transactions that cannot be signed by enough
+// signers are rejected with insufficient_signers, other errors are server
+// errors.
+func errorSubmittingTransaction(w http.ResponseWriter, err error) {
+	if err == submitter.ErrInsufficientSigners {
+		errorBadRequest(w, errorResponseString("insufficient_signers", "Available signers cannot meet the threshold of the source account."))
+		return
+	}
+	errorServerError(w)
+}
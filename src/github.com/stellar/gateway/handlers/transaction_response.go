@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/stellar/gateway/horizon"
+)
+
+type TransactionResponse struct {
+	Id          int64                                   `json:"id"`
+	Status      string                                  `json:"status"`
+	Source      string                                  `json:"source"`
+	SubmittedAt time.Time                               `json:"submitted_at"`
+	SucceededAt *time.Time                              `json:"succeeded_at"`
+	Ledger      *uint64                                 `json:"ledger"`
+	Hash        string                                  `json:"hash"`
+	EnvelopeXdr string                                  `json:"envelope_xdr"`
+	ResultXdr   *string                                 `json:"result_xdr"`
+	Errors      *horizon.SubmitTransactionResponseError `json:"errors"`
+	HorizonUrl  *string                                 `json:"horizon_url"`
+	// RetryOf is an id of the first attempt when the transaction was
+	// resubmitted after transaction_bad_seq
+	RetryOf *int64 `json:"retry_of"`
+}
+
+type TransactionsPageResponse struct {
+	Transactions []TransactionResponse `json:"transactions"`
+	// Cursor to use to get the next page
+	Cursor string `json:"cursor"`
+}
+
+// BuildTransactionResponse is returned by /build/* endpoints
+type BuildTransactionResponse struct {
+	// Base64 encoded transaction envelope without signatures
+	TransactionEnvelope string `json:"transaction_envelope"`
+	// Hex encoded transaction hash that needs to be signed
+	Hash string `json:"hash"`
+}
+
+// DryRunResponse is returned instead of submitting a transaction when dry run
+// mode is enabled
+type DryRunResponse struct {
+	TransactionEnvelope string             `json:"transaction_envelope"`
+	Hash                string             `json:"hash"`
+	Fee                 uint32             `json:"fee"`
+	Operations          []OperationSummary `json:"operations"`
+}
+
+type OperationSummary struct {
+	Type        string `json:"type"`
+	Source      string `json:"source"`
+	Destination string `json:"destination,omitempty"`
+	AssetCode   string `json:"asset_code,omitempty"`
+	AssetIssuer string `json:"asset_issuer,omitempty"`
+	Amount      string `json:"amount,omitempty"`
+	Trustor     string `json:"trustor,omitempty"`
+	Authorize   *bool  `json:"authorize,omitempty"`
+}
@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"time"
+)
+
+type TrustlineAuthorizationResponse struct {
+	Id              int64     `json:"id"`
+	OperationId     string    `json:"operation_id"`
+	AccountId       string    `json:"account_id"`
+	AssetCode       string    `json:"asset_code"`
+	Status          string    `json:"status"`
+	Reason          *string   `json:"reason"`
+	TransactionHash *string   `json:"transaction_hash"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type TrustlineAuthorizationsPageResponse struct {
+	Authorizations []TrustlineAuthorizationResponse `json:"authorizations"`
+	// Cursor to use to get the next page
+	Cursor string `json:"cursor"`
+}
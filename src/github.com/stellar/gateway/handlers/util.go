@@ -13,6 +13,10 @@ func errorForbidden(w http.ResponseWriter, responseString string) {
 	http.Error(w, responseString, http.StatusForbidden)
 }
 
+func errorNotFound(w http.ResponseWriter, responseString string) {
+	http.Error(w, responseString, http.StatusNotFound)
+}
+
 func errorBadRequest(w http.ResponseWriter, responseString string) {
 	http.Error(w, responseString, http.StatusBadRequest)
 }
@@ -0,0 +1,248 @@
+package hooks
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/hooks/signature"
+)
+
+// Deliverer sends pending HookDeliveries saved by PaymentListener. Failed
+// deliveries are retried with exponential backoff and marked as failed after
+// MaxAttempts attempts.
+type Deliverer struct {
+	EntityManager db.EntityManagerInterface
+	Repository    db.RepositoryInterface
+	Client        *http.Client
+	// MaxAttempts is a number of attempts after which delivery is marked as failed
+	MaxAttempts int
+	// RetryBackoff is a delay before the second attempt. It's doubled before
+	// every next attempt up to MaxBackoff.
+	RetryBackoff time.Duration
+	MaxBackoff   time.Duration
+	// PollInterval is a delay between checks for pending deliveries
+	PollInterval time.Duration
+	// Secrets are used to sign requests. See signature package.
+	Secrets []string
+	// ErrorHooks are URLs of error hooks (by receiving account ID) notified
+	// when receive hook delivery fails permanently.
+	ErrorHooks map[string]string
+	now        func() time.Time
+	log        *logrus.Entry
+}
+
+const pendingDeliveriesLimit = 100
+
+// Reasons sent to the error hook in `reason` param
+const (
+	ReasonAssetNotAllowed   = "asset_not_allowed"
+	ReasonMemoMissing       = "memo_missing"
+	ReasonReceiveHookFailed = "receive_hook_failed"
+)
+
+// NewDelivery creates a pending HookDelivery of a request with given params.
+func NewDelivery(hook, hookUrl string, params url.Values, operationId string, now time.Time) *db.HookDelivery {
+	return &db.HookDelivery{
+		Hook:          hook,
+		Url:           hookUrl,
+		Payload:       params.Encode(),
+		OperationId:   operationId,
+		Status:        "pending",
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+}
+
+func NewDeliverer(
+	entityManager db.EntityManagerInterface,
+	repository db.RepositoryInterface,
+	maxAttempts int,
+	now func() time.Time,
+) (deliverer *Deliverer) {
+	if maxAttempts <= 0 {
+		maxAttempts = 10
+	}
+	deliverer = &Deliverer{
+		EntityManager: entityManager,
+		Repository:    repository,
+		Client:        &http.Client{Timeout: 10 * time.Second},
+		MaxAttempts:   maxAttempts,
+		RetryBackoff:  10 * time.Second,
+		MaxBackoff:    time.Hour,
+		PollInterval:  time.Second,
+		now:           now,
+		log: logrus.WithFields(logrus.Fields{
+			"service": "HookDeliverer",
+		}),
+	}
+	return
+}
+
+// Start starts sending pending deliveries in a background goroutine.
+func (d *Deliverer) Start() {
+	go func() {
+		for {
+			err := d.DeliverPending()
+			if err != nil {
+				d.log.Error("Error delivering hooks: ", err)
+			}
+			time.Sleep(d.PollInterval)
+		}
+	}()
+}
+
+// DeliverPending sends deliveries that are due.
+func (d *Deliverer) DeliverPending() (err error) {
+	deliveries, err := d.Repository.GetPendingHookDeliveries(d.now(), pendingDeliveriesLimit)
+	if err != nil {
+		return
+	}
+
+	for i := range deliveries {
+		err = d.Deliver(&deliveries[i])
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Deliver sends a single delivery and saves its new state. Returned error
+// means that the state cannot be saved, failed requests are recorded in
+// the delivery.
+func (d *Deliverer) Deliver(delivery *db.HookDelivery) (err error) {
+	log := d.log.WithFields(logrus.Fields{
+		"id":           *delivery.Id,
+		"hook":         delivery.Hook,
+		"operation_id": delivery.OperationId,
+	})
+
+	statusCode, deliveryErr := d.send(delivery)
+	if delivery.Hook == "receive" {
+		d.updateReceivedPayment(delivery, statusCode)
+	}
+
+	if deliveryErr == nil {
+		log.Info("Hook delivered")
+		delivery.MarkDelivered(d.now())
+		return d.EntityManager.Persist(delivery)
+	}
+
+	if delivery.Attempts+1 >= d.MaxAttempts {
+		log.WithFields(logrus.Fields{"err": deliveryErr}).Error("Hook delivery failed, no attempts left")
+		delivery.MarkAttemptFailed(deliveryErr.Error(), nil)
+		// Error hook delivery is saved first so it's not lost when the
+		// receive hook delivery is already marked as failed
+		err = d.notifyReceiveHookFailed(delivery)
+		if err != nil {
+			return
+		}
+		return d.EntityManager.Persist(delivery)
+	}
+
+	nextAttemptAt := d.now().Add(d.backoff(delivery.Attempts + 1))
+	log.WithFields(logrus.Fields{
+		"err":             deliveryErr,
+		"next_attempt_at": nextAttemptAt,
+	}).Warn("Hook delivery failed")
+	delivery.MarkAttemptFailed(deliveryErr.Error(), &nextAttemptAt)
+	return d.EntityManager.Persist(delivery)
+}
+
+// updateReceivedPayment saves the receive hook response status code (0 when
+// no response was received) and a number of attempts in ReceivedPayment.
+// Errors are only logged as they do not affect the delivery.
+func (d *Deliverer) updateReceivedPayment(delivery *db.HookDelivery, statusCode int) {
+	log := d.log.WithFields(logrus.Fields{"operation_id": delivery.OperationId})
+
+	payment, err := d.Repository.GetReceivedPaymentByOperationId(delivery.OperationId)
+	if err != nil {
+		log.WithFields(logrus.Fields{"err": err}).Error("Error loading received payment")
+		return
+	}
+	if payment == nil {
+		// Delivery is saved before the payment so the listener may not have
+		// saved it yet
+		log.Warn("Received payment not found")
+		return
+	}
+
+	if statusCode != 0 {
+		payment.HookStatusCode = &statusCode
+	}
+	payment.HookAttempts++
+
+	err = d.EntityManager.Persist(payment)
+	if err != nil {
+		log.WithFields(logrus.Fields{"err": err}).Error("Error saving received payment")
+	}
+}
+
+// notifyReceiveHookFailed saves error hook delivery for a failed receive
+// hook delivery. Payload contains all params of the receive hook request.
+func (d *Deliverer) notifyReceiveHookFailed(delivery *db.HookDelivery) (err error) {
+	if delivery.Hook != "receive" {
+		return
+	}
+
+	params, err := url.ParseQuery(delivery.Payload)
+	if err != nil {
+		return
+	}
+
+	errorHook, ok := d.ErrorHooks[params.Get("account_id")]
+	if !ok {
+		return
+	}
+
+	params.Set("reason", ReasonReceiveHookFailed)
+	params.Set("error", *delivery.LastError)
+
+	return d.EntityManager.Persist(NewDelivery("error", errorHook, params, delivery.OperationId, d.now()))
+}
+
+// backoff returns a delay after a given number of failed attempts.
+func (d *Deliverer) backoff(attempts int) time.Duration {
+	backoff := d.RetryBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= d.MaxBackoff {
+			return d.MaxBackoff
+		}
+	}
+	return backoff
+}
+
+func (d *Deliverer) send(delivery *db.HookDelivery) (statusCode int, err error) {
+	req, err := http.NewRequest("POST", delivery.Url, strings.NewReader(delivery.Payload))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if len(d.Secrets) > 0 {
+		// Signed at every attempt so the timestamp is always fresh
+		signature.SetHeaders(req.Header, d.Secrets, d.now(), []byte(delivery.Payload))
+	}
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	statusCode = resp.StatusCode
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		message := fmt.Sprintf("Error response from %s hook: %d %s", delivery.Hook, resp.StatusCode, body)
+		err = errors.New(strings.TrimSpace(message))
+	}
+	return
+}
@@ -0,0 +1,214 @@
+package hooks
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/hooks/signature"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDeliverer(t *testing.T) {
+	mockEntityManager := new(mocks.MockEntityManager)
+	mockRepository := new(mocks.MockRepository)
+
+	var hookStatusCode int
+	var hookHeader http.Header
+	hookServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hookHeader = r.Header
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "1", r.FormValue("id"))
+		assert.Equal(t, "200", r.FormValue("amount"))
+		assert.Equal(t, "testing", r.FormValue("memo"))
+		w.WriteHeader(hookStatusCode)
+	}))
+	defer hookServer.Close()
+
+	mocks.PredefinedTime = time.Now()
+	deliverer := NewDeliverer(mockEntityManager, mockRepository, 3, mocks.Now)
+
+	// Payments are not in the DB unless a test uses a different operation ID
+	mockRepository.On("GetReceivedPaymentByOperationId", "1").Return((*db.ReceivedPayment)(nil), nil)
+
+	Convey("Given pending hook delivery", t, func() {
+		id := int64(1)
+		delivery := db.HookDelivery{
+			Id:            &id,
+			Hook:          "receive",
+			Url:           hookServer.URL,
+			Payload:       "amount=200&id=1&memo=testing",
+			OperationId:   "1",
+			Status:        "pending",
+			NextAttemptAt: mocks.PredefinedTime,
+		}
+
+		Convey("When hook returns success", func() {
+			hookStatusCode = 200
+			mockEntityManager.On("Persist", &delivery).Return(nil).Once()
+
+			Convey("it should mark delivery as delivered", func() {
+				err := deliverer.Deliver(&delivery)
+				assert.Nil(t, err)
+				assert.Equal(t, "delivered", delivery.Status)
+				assert.Equal(t, 1, delivery.Attempts)
+				assert.Equal(t, mocks.PredefinedTime, *delivery.DeliveredAt)
+				mockEntityManager.AssertExpectations(t)
+			})
+		})
+
+		Convey("When received payment is saved", func() {
+			delivery.OperationId = "2"
+			paymentId := int64(5)
+			payment := &db.ReceivedPayment{Id: &paymentId, OperationId: "2", HookAttempts: 1}
+			mockRepository.On("GetReceivedPaymentByOperationId", "2").Return(payment, nil).Once()
+
+			Convey("it should save hook status code and attempts", func() {
+				hookStatusCode = 503
+				mockEntityManager.On("Persist", payment).Return(nil).Once()
+				mockEntityManager.On("Persist", &delivery).Return(nil).Once()
+
+				err := deliverer.Deliver(&delivery)
+				assert.Nil(t, err)
+				assert.Equal(t, 503, *payment.HookStatusCode)
+				assert.Equal(t, 2, payment.HookAttempts)
+				mockRepository.AssertExpectations(t)
+				mockEntityManager.AssertExpectations(t)
+			})
+		})
+
+		Convey("When secrets are set", func() {
+			hookStatusCode = 200
+			deliverer.Secrets = []string{"old-secret-1234567890", "new-secret-1234567890"}
+			mockEntityManager.On("Persist", &delivery).Return(nil).Once()
+
+			Convey("it should sign the request with every secret", func() {
+				err := deliverer.Deliver(&delivery)
+				deliverer.Secrets = nil
+				assert.Nil(t, err)
+
+				timestamp := mocks.PredefinedTime.Unix()
+				payload := []byte(delivery.Payload)
+				assert.Equal(t, strconv.FormatInt(timestamp, 10), hookHeader.Get(signature.TimestampHeader))
+				assert.Equal(
+					t,
+					signature.Sign("old-secret-1234567890", timestamp, payload)+","+signature.Sign("new-secret-1234567890", timestamp, payload),
+					hookHeader.Get(signature.SignatureHeader),
+				)
+			})
+		})
+
+		Convey("When hook returns error", func() {
+			hookStatusCode = 503
+			mockEntityManager.On("Persist", &delivery).Return(nil).Once()
+
+			Convey("it should schedule next attempt", func() {
+				err := deliverer.Deliver(&delivery)
+				assert.Nil(t, err)
+				assert.Equal(t, "pending", delivery.Status)
+				assert.Equal(t, 1, delivery.Attempts)
+				assert.Equal(t, mocks.PredefinedTime.Add(10*time.Second), delivery.NextAttemptAt)
+				assert.Equal(t, "Error response from receive hook: 503", *delivery.LastError)
+				mockEntityManager.AssertExpectations(t)
+			})
+
+			Convey("it should double the delay after every attempt", func() {
+				delivery.Attempts = 1
+				err := deliverer.Deliver(&delivery)
+				assert.Nil(t, err)
+				assert.Equal(t, 2, delivery.Attempts)
+				assert.Equal(t, mocks.PredefinedTime.Add(20*time.Second), delivery.NextAttemptAt)
+			})
+
+			Convey("it should mark delivery as failed when no attempts left", func() {
+				delivery.Attempts = 2
+				err := deliverer.Deliver(&delivery)
+				assert.Nil(t, err)
+				assert.Equal(t, "failed", delivery.Status)
+				assert.Equal(t, 3, delivery.Attempts)
+				mockEntityManager.AssertExpectations(t)
+			})
+
+			Convey("When error hook is set", func() {
+				errorHook := "http://error.example.com/hook"
+				deliverer.ErrorHooks = map[string]string{"GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB": errorHook}
+				defer func() { deliverer.ErrorHooks = nil }()
+				delivery.Attempts = 2
+				delivery.Payload = "account_id=GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB&amount=200&id=1&memo=testing"
+
+				Convey("it should queue error hook delivery when no attempts left", func() {
+					errorDelivery := &db.HookDelivery{
+						Hook:          "error",
+						Url:           errorHook,
+						Payload:       "account_id=GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB&amount=200&error=Error+response+from+receive+hook%3A+503&id=1&memo=testing&reason=receive_hook_failed",
+						OperationId:   "1",
+						Status:        "pending",
+						NextAttemptAt: mocks.PredefinedTime,
+						CreatedAt:     mocks.PredefinedTime,
+					}
+					mockEntityManager.On("Persist", errorDelivery).Return(nil).Once()
+
+					err := deliverer.Deliver(&delivery)
+					assert.Nil(t, err)
+					assert.Equal(t, "failed", delivery.Status)
+					mockEntityManager.AssertExpectations(t)
+				})
+
+				Convey("it should not mark delivery as failed when error hook delivery cannot be saved", func() {
+					// Clear expectation of the parent Convey
+					mockEntityManager.ExpectedCalls = nil
+					mockEntityManager.On("Persist", mock.AnythingOfType("*db.HookDelivery")).Return(errors.New("DB error")).Once()
+
+					err := deliverer.Deliver(&delivery)
+					assert.Error(t, err)
+					mockEntityManager.AssertExpectations(t)
+				})
+			})
+		})
+
+		Convey("When pending deliveries are loaded", func() {
+			hookStatusCode = 200
+
+			Convey("it should deliver all of them", func() {
+				mockRepository.On("GetPendingHookDeliveries", mocks.PredefinedTime, pendingDeliveriesLimit).Return(
+					[]db.HookDelivery{delivery},
+					nil,
+				).Once()
+				mockEntityManager.On("Persist", mock.AnythingOfType("*db.HookDelivery")).Run(func(args mock.Arguments) {
+					assert.Equal(t, "delivered", args.Get(0).(*db.HookDelivery).Status)
+				}).Return(nil).Once()
+
+				err := deliverer.DeliverPending()
+				assert.Nil(t, err)
+				mockRepository.AssertExpectations(t)
+				mockEntityManager.AssertExpectations(t)
+			})
+
+			Convey("it should return error when deliveries cannot be loaded", func() {
+				mockRepository.On("GetPendingHookDeliveries", mocks.PredefinedTime, pendingDeliveriesLimit).Return(
+					[]db.HookDelivery{},
+					errors.New("DB error"),
+				).Once()
+
+				err := deliverer.DeliverPending()
+				assert.Error(t, err)
+				mockRepository.AssertExpectations(t)
+			})
+		})
+	})
+
+	Convey("Given deliverer backoff", t, func() {
+		Convey("it should be capped at MaxBackoff", func() {
+			assert.Equal(t, 10*time.Second, deliverer.backoff(1))
+			assert.Equal(t, 40*time.Second, deliverer.backoff(3))
+			assert.Equal(t, time.Hour, deliverer.backoff(20))
+		})
+	})
+}
@@ -0,0 +1,118 @@
+// Package signature signs webhook requests sent by the gateway server and
+// verifies them on the receiving side. It depends on the standard library
+// only so it can be imported by services receiving hooks.
+//
+// Every request has two headers:
+//
+//	X-Gateway-Timestamp: unix time (seconds) when the request was sent
+//	X-Gateway-Signature: comma separated hex encoded HMAC-SHA256 signatures
+//	                     of "<timestamp>.<body>", one for every secret
+//
+// During secret rotation the gateway server signs requests with both the
+// old and the new secret so receivers can switch secrets independently.
+package signature
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	TimestampHeader = "X-Gateway-Timestamp"
+	SignatureHeader = "X-Gateway-Signature"
+	// DefaultTolerance is a maximum difference between request timestamp and
+	// the current time accepted by VerifyRequest.
+	DefaultTolerance = 5 * time.Minute
+)
+
+var (
+	ErrMissingSignature = errors.New("Request is not signed")
+	ErrInvalidSignature = errors.New("Request signature is invalid")
+	ErrExpired          = errors.New("Request timestamp is outside of the tolerance")
+)
+
+// Sign returns hex encoded HMAC-SHA256 signature of timestamp and body.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SetHeaders adds timestamp and signatures made with every secret to
+// request headers.
+func SetHeaders(header http.Header, secrets []string, timestamp time.Time, body []byte) {
+	unix := timestamp.Unix()
+	var signatures []string
+	for _, secret := range secrets {
+		signatures = append(signatures, Sign(secret, unix, body))
+	}
+	header.Set(TimestampHeader, strconv.FormatInt(unix, 10))
+	header.Set(SignatureHeader, strings.Join(signatures, ","))
+}
+
+// Verify checks if headers contain a valid signature of body made with one
+// of the secrets and if timestamp is not older (or newer) than tolerance.
+func Verify(header http.Header, body []byte, secrets []string, tolerance time.Duration, now time.Time) error {
+	timestampValue := header.Get(TimestampHeader)
+	signatureValue := header.Get(SignatureHeader)
+	if timestampValue == "" || signatureValue == "" {
+		return ErrMissingSignature
+	}
+
+	timestamp, err := strconv.ParseInt(timestampValue, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	diff := now.Sub(time.Unix(timestamp, 0))
+	if diff > tolerance || diff < -tolerance {
+		return ErrExpired
+	}
+
+	for _, secret := range secrets {
+		expected := []byte(Sign(secret, timestamp, body))
+		for _, signature := range strings.Split(signatureValue, ",") {
+			if hmac.Equal(expected, []byte(strings.TrimSpace(signature))) {
+				return nil
+			}
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// VerifyRequest verifies signature of a hook request using DefaultTolerance.
+// Request body is read and replaced so r.ParseForm can be used afterwards.
+func VerifyRequest(r *http.Request, secrets ...string) error {
+	body, err := ioutil.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		return err
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return Verify(r.Header, body, secrets, DefaultTolerance, time.Now())
+}
+
+// Middleware responds with 401 Unauthorized to hook requests that are not
+// signed with one of the secrets.
+func Middleware(secrets ...string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			err := VerifyRequest(r, secrets...)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
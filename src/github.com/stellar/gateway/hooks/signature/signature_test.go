@@ -0,0 +1,106 @@
+package signature
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignature(t *testing.T) {
+	body := []byte("amount=200&id=1")
+	now := time.Unix(1460000000, 0)
+
+	Convey("Given signed request", t, func() {
+		header := http.Header{}
+		SetHeaders(header, []string{"secret-1"}, now, body)
+
+		Convey("it should use HMAC-SHA256 of timestamp and body", func() {
+			assert.Equal(t, "1460000000", header.Get(TimestampHeader))
+			// echo -n "1460000000.amount=200&id=1" | openssl dgst -sha256 -hmac "secret-1"
+			assert.Equal(t, "656fd661d2959d595375578b66a78b663ea5b1980710e907bf2ed0c2774b3485", header.Get(SignatureHeader))
+		})
+
+		Convey("it should be valid with the same secret", func() {
+			assert.Nil(t, Verify(header, body, []string{"secret-1"}, DefaultTolerance, now))
+		})
+
+		Convey("it should be valid when receiver has two secrets", func() {
+			assert.Nil(t, Verify(header, body, []string{"secret-2", "secret-1"}, DefaultTolerance, now))
+		})
+
+		Convey("it should be invalid with a different secret", func() {
+			assert.Equal(t, ErrInvalidSignature, Verify(header, body, []string{"secret-2"}, DefaultTolerance, now))
+		})
+
+		Convey("it should be invalid when body was changed", func() {
+			assert.Equal(t, ErrInvalidSignature, Verify(header, []byte("amount=2000&id=1"), []string{"secret-1"}, DefaultTolerance, now))
+		})
+
+		Convey("it should be invalid when timestamp was changed", func() {
+			header.Set(TimestampHeader, strconv.FormatInt(now.Unix()+1, 10))
+			assert.Equal(t, ErrInvalidSignature, Verify(header, body, []string{"secret-1"}, DefaultTolerance, now))
+		})
+
+		Convey("it should expire", func() {
+			assert.Nil(t, Verify(header, body, []string{"secret-1"}, DefaultTolerance, now.Add(4*time.Minute)))
+			assert.Equal(t, ErrExpired, Verify(header, body, []string{"secret-1"}, DefaultTolerance, now.Add(6*time.Minute)))
+			assert.Equal(t, ErrExpired, Verify(header, body, []string{"secret-1"}, DefaultTolerance, now.Add(-6*time.Minute)))
+		})
+	})
+
+	Convey("Given request signed with two secrets", t, func() {
+		header := http.Header{}
+		SetHeaders(header, []string{"secret-1", "secret-2"}, now, body)
+
+		Convey("it should be valid with any of them", func() {
+			assert.Equal(t, 2, len(strings.Split(header.Get(SignatureHeader), ",")))
+			assert.Nil(t, Verify(header, body, []string{"secret-1"}, DefaultTolerance, now))
+			assert.Nil(t, Verify(header, body, []string{"secret-2"}, DefaultTolerance, now))
+		})
+	})
+
+	Convey("Given request without signature", t, func() {
+		Convey("it should be rejected", func() {
+			assert.Equal(t, ErrMissingSignature, Verify(http.Header{}, body, []string{"secret-1"}, DefaultTolerance, now))
+		})
+	})
+
+	Convey("Given middleware", t, func() {
+		var handlerCalls int
+		var form url.Values
+		handler := Middleware("secret-1")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handlerCalls++
+			r.ParseForm()
+			form = r.PostForm
+		}))
+
+		newRequest := func(timestamp time.Time) *http.Request {
+			r := httptest.NewRequest("POST", "/receive", strings.NewReader(string(body)))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			SetHeaders(r.Header, []string{"secret-1"}, timestamp, body)
+			return r
+		}
+
+		Convey("it should pass valid request with readable body", func() {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, newRequest(time.Now()))
+			assert.Equal(t, 200, w.Code)
+			assert.Equal(t, 1, handlerCalls)
+			assert.Equal(t, "200", form.Get("amount"))
+		})
+
+		Convey("it should reject replayed request", func() {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, newRequest(time.Now().Add(-time.Hour)))
+			assert.Equal(t, 401, w.Code)
+			assert.Equal(t, 0, handlerCalls)
+		})
+	})
+}
@@ -1,6 +1,48 @@
 package horizon
 
 type AccountResponse struct {
-	AccountId      string `json:"id"`
-	SequenceNumber string `json:"sequence"`
+	AccountId      string                  `json:"id"`
+	SequenceNumber string                  `json:"sequence"`
+	Thresholds     AccountThresholds       `json:"thresholds"`
+	Signers        []AccountSignerResponse `json:"signers"`
+	Balances       []AccountBalance        `json:"balances"`
+}
+
+type AccountThresholds struct {
+	LowThreshold  byte `json:"low_threshold"`
+	MedThreshold  byte `json:"med_threshold"`
+	HighThreshold byte `json:"high_threshold"`
+}
+
+type AccountSignerResponse struct {
+	// Address is returned by older horizon versions, newer return PublicKey
+	Address   string `json:"address"`
+	PublicKey string `json:"public_key"`
+	Weight    byte   `json:"weight"`
+}
+
+// GetPublicKey returns ID of the signer
+func (s AccountSignerResponse) GetPublicKey() string {
+	if s.PublicKey != "" {
+		return s.PublicKey
+	}
+	return s.Address
+}
+
+type AccountBalance struct {
+	AssetType   string `json:"asset_type"`
+	AssetCode   string `json:"asset_code"`
+	AssetIssuer string `json:"asset_issuer"`
+	Balance     string `json:"balance"`
+	Limit       string `json:"limit"`
+}
+
+// HasTrustline returns true when account trusts a given asset
+func (a AccountResponse) HasTrustline(assetCode, assetIssuer string) bool {
+	for _, balance := range a.Balances {
+		if balance.AssetCode == assetCode && balance.AssetIssuer == assetIssuer {
+			return true
+		}
+	}
+	return false
 }
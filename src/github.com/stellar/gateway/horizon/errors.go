@@ -0,0 +1,30 @@
+package horizon
+
+import (
+	"errors"
+)
+
+var (
+	// ErrNotFound is returned when horizon responds with 404 Not Found
+	// (ex. account or transaction does not exist).
+	ErrNotFound = errors.New("Horizon resource not found")
+	// ErrRateLimited is returned when horizon responds with 429 Too Many Requests.
+	ErrRateLimited = errors.New("Horizon rate limit exceeded")
+	// ErrServerError is returned when horizon responds with 5xx status code.
+	ErrServerError = errors.New("Horizon server error")
+	// ErrTimeout is returned when horizon does not respond in time.
+	ErrTimeout = errors.New("Horizon request timeout")
+	// ErrUnavailable is returned when connection to horizon cannot be made.
+	ErrUnavailable = errors.New("Horizon server unavailable")
+)
+
+// isRetryable returns true when request that failed with err can be retried.
+func isRetryable(err error) bool {
+	return err == ErrRateLimited || err == ErrServerError || err == ErrTimeout || err == ErrUnavailable
+}
+
+// isNotProcessed returns true when request that failed with err was certainly
+// not processed by horizon so it's safe to send it to another server.
+func isNotProcessed(err error) bool {
+	return err == ErrRateLimited || err == ErrUnavailable
+}
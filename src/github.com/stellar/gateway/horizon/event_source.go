@@ -0,0 +1,386 @@
+package horizon
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// ErrStreamIdle is returned when no data (events or comments) was received
+// from a stream for EventSource.IdleTimeout.
+var ErrStreamIdle = errors.New("Stream idle timeout")
+
+// Event is a server-sent event.
+type Event struct {
+	// Type is "message" when the event has no event field
+	Type string
+	Data string
+	// LastEventId is the value of the last id field received in the stream
+	// (not necessarily in this event)
+	LastEventId string
+}
+
+// EventReader parses a stream of server-sent events as defined in
+// https://html.spec.whatwg.org/multipage/server-sent-events.html
+type EventReader struct {
+	reader *bufio.Reader
+	// Retry is the reconnection time sent by the server in the last retry
+	// field (0 when it was not sent)
+	Retry       time.Duration
+	lastEventId string
+	eventType   string
+	data        bytes.Buffer
+	pending     bool
+	firstLine   bool
+	skipLF      bool
+}
+
+func NewEventReader(r io.Reader) *EventReader {
+	return &EventReader{reader: bufio.NewReader(r), firstLine: true}
+}
+
+// Read returns the next event. Returns io.EOF when the stream ended after
+// a complete event and io.ErrUnexpectedEOF when it ended in the middle of
+// an event (the incomplete event is discarded).
+func (r *EventReader) Read() (event Event, err error) {
+	for {
+		var line []byte
+		line, err = r.readLine()
+		if err != nil {
+			if err == io.EOF && (r.pending || len(line) > 0) {
+				err = io.ErrUnexpectedEOF
+			}
+			return
+		}
+
+		if len(line) > 0 {
+			r.pending = true
+			r.processField(line)
+			continue
+		}
+
+		// Empty line dispatches the event
+		r.pending = false
+		if r.data.Len() == 0 {
+			r.eventType = ""
+			continue
+		}
+
+		event.Type = r.eventType
+		if event.Type == "" {
+			event.Type = "message"
+		}
+		event.Data = string(bytes.TrimSuffix(r.data.Bytes(), []byte("\n")))
+		event.LastEventId = r.lastEventId
+
+		r.eventType = ""
+		r.data.Reset()
+		return
+	}
+}
+
+// readLine reads a line terminated by CRLF, LF or CR. A line that is not
+// terminated is returned with io.EOF.
+func (r *EventReader) readLine() (line []byte, err error) {
+	for {
+		var b byte
+		b, err = r.reader.ReadByte()
+		if err != nil {
+			return
+		}
+
+		// LF after CR was a part of the previous line ending. It's skipped
+		// here and not peeked after CR so a stalled stream does not block
+		// the previous line.
+		if r.skipLF {
+			r.skipLF = false
+			if b == '\n' {
+				continue
+			}
+		}
+
+		switch b {
+		case '\r':
+			r.skipLF = true
+			return r.trimBOM(line), nil
+		case '\n':
+			return r.trimBOM(line), nil
+		}
+		line = append(line, b)
+	}
+}
+
+// trimBOM removes UTF-8 byte order mark from the first line of the stream.
+func (r *EventReader) trimBOM(line []byte) []byte {
+	if !r.firstLine {
+		return line
+	}
+	r.firstLine = false
+	return bytes.TrimPrefix(line, []byte("\xEF\xBB\xBF"))
+}
+
+func (r *EventReader) processField(line []byte) {
+	// Comment (ex. heartbeat)
+	if line[0] == ':' {
+		return
+	}
+
+	name := line
+	var value []byte
+	if i := bytes.IndexByte(line, ':'); i >= 0 {
+		name = line[:i]
+		value = bytes.TrimPrefix(line[i+1:], []byte(" "))
+	}
+
+	switch string(name) {
+	case "event":
+		r.eventType = string(value)
+	case "data":
+		r.data.Write(value)
+		r.data.WriteByte('\n')
+	case "id":
+		if bytes.IndexByte(value, 0) < 0 {
+			r.lastEventId = string(value)
+		}
+	case "retry":
+		if !isDigits(value) {
+			return
+		}
+		milliseconds, err := strconv.ParseInt(string(value), 10, 64)
+		if err == nil {
+			r.Retry = time.Duration(milliseconds) * time.Millisecond
+		}
+	}
+}
+
+func isDigits(value []byte) bool {
+	if len(value) == 0 {
+		return false
+	}
+	for _, b := range value {
+		if b < '0' || b > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// EventHandler is called for every event received by EventSource. Stream
+// is stopped when it returns an error.
+type EventHandler func(Event) error
+
+// EventSource reads server-sent events from a URL. When the connection is
+// closed or stalls it reconnects with Last-Event-ID header so the server can
+// continue from the last handled event.
+type EventSource struct {
+	Url    string
+	Client *http.Client
+	// LastEventId is the id of the last handled event. It's sent in
+	// Last-Event-ID header when connecting.
+	LastEventId string
+	// RetryDelay is a delay before reconnecting. It's changed by retry field
+	// sent by the server. After every failed connection the delay is doubled
+	// up to MaxRetryDelay.
+	RetryDelay    time.Duration
+	MaxRetryDelay time.Duration
+	// MaxRetries is a number of failed connections in a row after which
+	// Stream returns the error. Streams closed by the server without an
+	// error are always reconnected.
+	MaxRetries int
+	// IdleTimeout is a maximum time without any data received from the
+	// server. Stalled connection is closed and reconnected.
+	IdleTimeout time.Duration
+	log         *logrus.Entry
+}
+
+func NewEventSource(client *http.Client, url string) *EventSource {
+	return &EventSource{
+		Url:           url,
+		Client:        client,
+		RetryDelay:    time.Second,
+		MaxRetryDelay: time.Minute,
+		MaxRetries:    3,
+		IdleTimeout:   time.Minute,
+		log: logrus.WithFields(logrus.Fields{
+			"service": "EventSource",
+			"url":     url,
+		}),
+	}
+}
+
+// Stream connects to the server and calls handler for every event until ctx
+// is done, handler returns an error, the server responds with 204 No Content
+// (nil is returned) or with an error status code, or connection failed
+// MaxRetries+1 times in a row.
+func (es *EventSource) Stream(ctx context.Context, handler EventHandler) (err error) {
+	failures := 0
+	for {
+		var received bool
+		received, err = es.connect(ctx, handler)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		var streamErr *streamError
+		switch {
+		case errors.As(err, &streamErr):
+			return streamErr.err
+		case err == errNoContent:
+			return nil
+		case err == nil:
+			// Closed by the server, it's not a failure
+			failures = 0
+		case received:
+			// Connection worked before it failed
+			failures = 1
+		default:
+			failures++
+		}
+
+		if failures > es.MaxRetries {
+			return
+		}
+
+		delay := es.retryDelay(failures)
+		es.log.WithFields(logrus.Fields{
+			"err":   err,
+			"delay": delay,
+		}).Warn("Stream closed, reconnecting")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// streamError wraps errors that stop the stream without reconnecting.
+type streamError struct {
+	err error
+}
+
+func (e *streamError) Error() string {
+	return e.err.Error()
+}
+
+var errNoContent = errors.New("No content")
+
+// retryDelay returns a delay before reconnecting after a given number of
+// failed connections in a row.
+func (es *EventSource) retryDelay(failures int) time.Duration {
+	delay := es.RetryDelay
+	for i := 1; i < failures; i++ {
+		delay *= 2
+		if delay >= es.MaxRetryDelay {
+			return es.MaxRetryDelay
+		}
+	}
+	return delay
+}
+
+// connect reads events from a single connection. Returns nil when the
+// connection was closed by the server after a complete event. received is
+// true when at least one event was received.
+func (es *EventSource) connect(ctx context.Context, handler EventHandler) (received bool, err error) {
+	req, err := http.NewRequest("GET", es.Url, nil)
+	if err != nil {
+		return false, &streamError{err}
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+	if es.LastEventId != "" {
+		req.Header.Set("Last-Event-ID", es.LastEventId)
+	}
+
+	connCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Read deadline: connection is closed when no data is received for
+	// IdleTimeout
+	var idle int32
+	timer := time.AfterFunc(es.IdleTimeout, func() {
+		atomic.StoreInt32(&idle, 1)
+		cancel()
+	})
+	defer timer.Stop()
+
+	resp, err := es.Client.Do(req.WithContext(connCtx))
+	if err != nil {
+		if atomic.LoadInt32(&idle) == 1 {
+			err = ErrStreamIdle
+		} else if isConnectionError(err) {
+			err = ErrUnavailable
+		}
+		return
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNoContent:
+		return false, errNoContent
+	case resp.StatusCode != http.StatusOK:
+		err = statusCodeError(resp.StatusCode)
+		if err == nil {
+			err = fmt.Errorf("Invalid stream response status code: %d", resp.StatusCode)
+		}
+		return false, &streamError{err}
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/event-stream" {
+		return false, &streamError{fmt.Errorf("Invalid stream content type: %s", resp.Header.Get("Content-Type"))}
+	}
+
+	reader := NewEventReader(&idleTimeoutReader{resp.Body, timer, es.IdleTimeout})
+	for {
+		var event Event
+		event, err = reader.Read()
+		if reader.Retry > 0 {
+			es.RetryDelay = reader.Retry
+		}
+		if err != nil {
+			if atomic.LoadInt32(&idle) == 1 {
+				err = ErrStreamIdle
+			} else if err == io.EOF {
+				err = nil
+			}
+			return
+		}
+
+		received = true
+		// Handler can be slow (ex. when it retries), it's not a stalled stream
+		timer.Stop()
+		err = handler(event)
+		if err != nil {
+			return received, &streamError{err}
+		}
+		timer.Reset(es.IdleTimeout)
+		es.LastEventId = event.LastEventId
+	}
+}
+
+// idleTimeoutReader resets timer every time data is read.
+type idleTimeoutReader struct {
+	reader  io.Reader
+	timer   *time.Timer
+	timeout time.Duration
+}
+
+func (r *idleTimeoutReader) Read(p []byte) (n int, err error) {
+	n, err = r.reader.Read(p)
+	if n > 0 {
+		r.timer.Reset(r.timeout)
+	}
+	return
+}
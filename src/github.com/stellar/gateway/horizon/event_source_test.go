@@ -0,0 +1,367 @@
+package horizon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
+)
+
+// readEvents reads all events from a stream.
+func readEvents(r io.Reader) (reader *EventReader, events []Event, err error) {
+	reader = NewEventReader(r)
+	for {
+		var event Event
+		event, err = reader.Read()
+		if err != nil {
+			return
+		}
+		events = append(events, event)
+	}
+}
+
+func TestEventReader(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream string
+		events []Event
+		err    error
+		retry  time.Duration
+	}{
+		{
+			name:   "single event",
+			stream: "data: test\n\n",
+			events: []Event{{Type: "message", Data: "test"}},
+			err:    io.EOF,
+		},
+		{
+			name:   "multi-line data",
+			stream: "data: YHOO\ndata: +2\ndata: 10\n\n",
+			events: []Event{{Type: "message", Data: "YHOO\n+2\n10"}},
+			err:    io.EOF,
+		},
+		{
+			name:   "CRLF and CR line endings",
+			stream: "event: a\r\ndata: 1\r\n\r\nevent: b\rdata: 2\r\r",
+			events: []Event{{Type: "a", Data: "1"}, {Type: "b", Data: "2"}},
+			err:    io.EOF,
+		},
+		{
+			name:   "comments",
+			stream: ": heartbeat\n\n:another\ndata: test\n\n",
+			events: []Event{{Type: "message", Data: "test"}},
+			err:    io.EOF,
+		},
+		{
+			name:   "only one leading space is removed",
+			stream: "data:test\n\ndata:  test\n\n",
+			events: []Event{{Type: "message", Data: "test"}, {Type: "message", Data: " test"}},
+			err:    io.EOF,
+		},
+		{
+			name:   "field without colon",
+			stream: "data\n\ndata\ndata\n\ndata:",
+			events: []Event{{Type: "message", Data: ""}, {Type: "message", Data: "\n"}},
+			err:    io.ErrUnexpectedEOF,
+		},
+		{
+			name:   "event type is reset after dispatch",
+			stream: "event: open\ndata: hello\n\ndata: 1\n\n",
+			events: []Event{{Type: "open", Data: "hello"}, {Type: "message", Data: "1"}},
+			err:    io.EOF,
+		},
+		{
+			name:   "event without data is not dispatched",
+			stream: "event: open\n\ndata: 1\n\n",
+			events: []Event{{Type: "message", Data: "1"}},
+			err:    io.EOF,
+		},
+		{
+			name:   "last event id is kept for next events",
+			stream: "id: 1\ndata: a\n\ndata: b\n\nid\ndata: c\n\n",
+			events: []Event{
+				{Type: "message", Data: "a", LastEventId: "1"},
+				{Type: "message", Data: "b", LastEventId: "1"},
+				{Type: "message", Data: "c", LastEventId: ""},
+			},
+			err: io.EOF,
+		},
+		{
+			name:   "id with NULL is ignored",
+			stream: "id: 1\ndata: a\n\nid: 2\x003\ndata: b\n\n",
+			events: []Event{
+				{Type: "message", Data: "a", LastEventId: "1"},
+				{Type: "message", Data: "b", LastEventId: "1"},
+			},
+			err: io.EOF,
+		},
+		{
+			name:   "retry",
+			stream: "retry: 1000\n\n",
+			err:    io.EOF,
+			retry:  time.Second,
+		},
+		{
+			name:   "invalid retry is ignored",
+			stream: "retry: 1000\n\nretry: 10s\n\nretry: -1\n\nretry:\n\n",
+			err:    io.EOF,
+			retry:  time.Second,
+		},
+		{
+			name:   "unknown fields are ignored",
+			stream: "foo: bar\nData: x\ndata: test\n\n",
+			events: []Event{{Type: "message", Data: "test"}},
+			err:    io.EOF,
+		},
+		{
+			name:   "byte order mark",
+			stream: "\xEF\xBB\xBFdata: test\n\n",
+			events: []Event{{Type: "message", Data: "test"}},
+			err:    io.EOF,
+		},
+		{
+			name:   "incomplete event is discarded",
+			stream: "data: a\n\ndata: b\n",
+			events: []Event{{Type: "message", Data: "a"}},
+			err:    io.ErrUnexpectedEOF,
+		},
+		{
+			name:   "incomplete line is discarded",
+			stream: "data: a\n\ndata: b",
+			events: []Event{{Type: "message", Data: "a"}},
+			err:    io.ErrUnexpectedEOF,
+		},
+		{
+			name:   "empty stream",
+			stream: "",
+			err:    io.EOF,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reader, events, err := readEvents(strings.NewReader(test.stream))
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.events, events)
+			assert.Equal(t, test.retry, reader.Retry)
+
+			// Stream split into single bytes must be parsed the same way
+			_, events, err = readEvents(iotest.OneByteReader(strings.NewReader(test.stream)))
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.events, events)
+		})
+	}
+}
+
+func FuzzEventReader(f *testing.F) {
+	f.Add("data: test\n\n")
+	f.Add("event: a\r\nid: 1\r\ndata: 1\r\n\r\n")
+	f.Add("retry: 1000\rdata: x\r\r: comment\n")
+	f.Add("\xEF\xBB\xBFdata\n\ndata: b")
+
+	f.Fuzz(func(t *testing.T, stream string) {
+		_, events, err := readEvents(strings.NewReader(stream))
+		if err != io.EOF && err != io.ErrUnexpectedEOF {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, event := range events {
+			if event.Type == "" {
+				t.Fatalf("event without type: %#v", event)
+			}
+			if strings.ContainsRune(event.LastEventId, 0) {
+				t.Fatalf("last event id with NULL: %#v", event)
+			}
+		}
+
+		// Parsing must not depend on how the stream is split into reads
+		_, oneByteEvents, oneByteErr := readEvents(iotest.OneByteReader(strings.NewReader(stream)))
+		assert.Equal(t, err, oneByteErr)
+		assert.Equal(t, events, oneByteEvents)
+	})
+}
+
+func TestEventSource(t *testing.T) {
+	var mutex sync.Mutex
+	var requests []*http.Request
+	var respond func(w http.ResponseWriter, r *http.Request, request int)
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		requests = append(requests, r)
+		request := len(requests)
+		mutex.Unlock()
+		respond(w, r, request)
+	}))
+	defer testServer.Close()
+
+	stream := func(w http.ResponseWriter, data string) {
+		w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, data)
+		w.(http.Flusher).Flush()
+	}
+
+	Convey("Given event source", t, func() {
+		requests = nil
+		eventSource := NewEventSource(&http.Client{}, testServer.URL+"/stream")
+		eventSource.RetryDelay = time.Millisecond
+		eventSource.IdleTimeout = time.Second
+		eventSource.MaxRetries = 1
+
+		var events []Event
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		Convey("When stream is closed by the server", func() {
+			respond = func(w http.ResponseWriter, r *http.Request, request int) {
+				switch request {
+				case 1:
+					stream(w, "retry: 2\nid: 1\ndata: a\n\nid: 2\ndata: b\n\n")
+				case 2:
+					stream(w, "id: 3\ndata: c\n\n")
+				default:
+					w.WriteHeader(http.StatusNoContent)
+				}
+			}
+
+			Convey("it should reconnect with the last event id", func() {
+				err := eventSource.Stream(ctx, func(event Event) error {
+					events = append(events, event)
+					return nil
+				})
+				assert.Nil(t, err)
+				assert.Equal(t, 3, len(events))
+				assert.Equal(t, 3, len(requests))
+				assert.Equal(t, "", requests[0].Header.Get("Last-Event-ID"))
+				assert.Equal(t, "text/event-stream", requests[0].Header.Get("Accept"))
+				assert.Equal(t, "2", requests[1].Header.Get("Last-Event-ID"))
+				assert.Equal(t, "3", requests[2].Header.Get("Last-Event-ID"))
+				assert.Equal(t, 2*time.Millisecond, eventSource.RetryDelay)
+			})
+		})
+
+		Convey("When stream ends in the middle of an event", func() {
+			respond = func(w http.ResponseWriter, r *http.Request, request int) {
+				if request == 1 {
+					stream(w, "id: 1\ndata: a\n\nid: 2\ndata: b")
+					return
+				}
+				stream(w, "id: 2\ndata: b")
+			}
+
+			Convey("it should reconnect from the last complete event and return error after MaxRetries", func() {
+				err := eventSource.Stream(ctx, func(event Event) error {
+					events = append(events, event)
+					return nil
+				})
+				assert.Equal(t, io.ErrUnexpectedEOF, err)
+				assert.Equal(t, 2, len(requests))
+				assert.Equal(t, "1", requests[1].Header.Get("Last-Event-ID"))
+				assert.Equal(t, 1, len(events))
+			})
+		})
+
+		Convey("When stream stalls", func() {
+			eventSource.IdleTimeout = 50 * time.Millisecond
+			eventSource.MaxRetries = 0
+			respond = func(w http.ResponseWriter, r *http.Request, request int) {
+				stream(w, "data: a\n\n")
+				<-r.Context().Done()
+			}
+
+			Convey("it should close the connection after IdleTimeout", func() {
+				err := eventSource.Stream(ctx, func(event Event) error {
+					events = append(events, event)
+					return nil
+				})
+				assert.Equal(t, ErrStreamIdle, err)
+				assert.Equal(t, 1, len(events))
+			})
+		})
+
+		Convey("When server sends heartbeats", func() {
+			eventSource.IdleTimeout = 100 * time.Millisecond
+			respond = func(w http.ResponseWriter, r *http.Request, request int) {
+				if request > 1 {
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				stream(w, "")
+				for i := 0; i < 5; i++ {
+					time.Sleep(50 * time.Millisecond)
+					fmt.Fprint(w, ": heartbeat\n")
+					w.(http.Flusher).Flush()
+				}
+				fmt.Fprint(w, "\ndata: a\n\n")
+			}
+
+			Convey("it should not close the connection", func() {
+				err := eventSource.Stream(ctx, func(event Event) error {
+					events = append(events, event)
+					return nil
+				})
+				assert.Nil(t, err)
+				assert.Equal(t, 1, len(events))
+				assert.Equal(t, 2, len(requests))
+			})
+		})
+
+		Convey("When server responds with error", func() {
+			respond = func(w http.ResponseWriter, r *http.Request, request int) {
+				http.Error(w, "error", http.StatusServiceUnavailable)
+			}
+
+			Convey("it should return error without reconnecting", func() {
+				err := eventSource.Stream(ctx, func(event Event) error { return nil })
+				assert.Equal(t, ErrServerError, err)
+				assert.Equal(t, 1, len(requests))
+			})
+		})
+
+		Convey("When response is not an event stream", func() {
+			respond = func(w http.ResponseWriter, r *http.Request, request int) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte("{}"))
+			}
+
+			Convey("it should return error without reconnecting", func() {
+				err := eventSource.Stream(ctx, func(event Event) error { return nil })
+				assert.Error(t, err)
+				assert.Equal(t, 1, len(requests))
+			})
+		})
+
+		Convey("When handler returns error", func() {
+			respond = func(w http.ResponseWriter, r *http.Request, request int) {
+				stream(w, "id: 1\ndata: a\n\nid: 2\ndata: b\n\n")
+			}
+
+			Convey("it should stop streaming", func() {
+				handlerErr := errors.New("handler error")
+				err := eventSource.Stream(ctx, func(event Event) error { return handlerErr })
+				assert.Equal(t, handlerErr, err)
+				assert.Equal(t, 1, len(requests))
+				assert.Equal(t, "", eventSource.LastEventId)
+			})
+		})
+
+		Convey("When server is not available", func() {
+			eventSource.Url = "http://127.0.0.1:1/stream"
+
+			Convey("it should return ErrUnavailable after MaxRetries", func() {
+				err := eventSource.Stream(ctx, func(event Event) error { return nil })
+				assert.Equal(t, ErrUnavailable, err)
+			})
+		})
+	})
+}
@@ -0,0 +1,377 @@
+package horizontest
+
+import (
+	"strings"
+
+	"github.com/stellar/go-stellar-base/keypair"
+	"github.com/stellar/go-stellar-base/strkey"
+	"github.com/stellar/go-stellar-base/xdr"
+)
+
+type account struct {
+	id       string
+	sequence int64
+	// balance of native asset in stroops
+	balance       int64
+	trustlines    []*trustline
+	signers       map[string]byte
+	thresholds    [3]byte
+	authRequired  bool
+	authRevocable bool
+}
+
+type trustline struct {
+	assetCode   string
+	assetIssuer string
+	balance     int64
+	limit       int64
+	authorized  bool
+}
+
+const (
+	thresholdLow = iota
+	thresholdMedium
+	thresholdHigh
+)
+
+const maxLimit = int64(^uint64(0) >> 1)
+
+func newAccount(id string, sequence, balance int64) *account {
+	return &account{
+		id:       id,
+		sequence: sequence,
+		balance:  balance,
+		signers:  map[string]byte{id: 1},
+	}
+}
+
+func (a *account) clone() *account {
+	c := *a
+	c.trustlines = make([]*trustline, len(a.trustlines))
+	for i, line := range a.trustlines {
+		lineCopy := *line
+		c.trustlines[i] = &lineCopy
+	}
+	c.signers = make(map[string]byte, len(a.signers))
+	for signer, weight := range a.signers {
+		c.signers[signer] = weight
+	}
+	return &c
+}
+
+func (a *account) trustline(assetCode, assetIssuer string) *trustline {
+	for _, line := range a.trustlines {
+		if line.assetCode == assetCode && line.assetIssuer == assetIssuer {
+			return line
+		}
+	}
+	return nil
+}
+
+func (a *account) removeTrustline(line *trustline) {
+	for i, l := range a.trustlines {
+		if l == line {
+			a.trustlines = append(a.trustlines[:i], a.trustlines[i+1:]...)
+			return
+		}
+	}
+}
+
+// subentries returns the number of ledger entries owned by the account that
+// increase its minimum balance.
+func (a *account) subentries() int64 {
+	subentries := int64(len(a.trustlines))
+	for signer := range a.signers {
+		if signer != a.id {
+			subentries++
+		}
+	}
+	return subentries
+}
+
+// signatureWeight returns the sum of weights of account signers that signed
+// the transaction.
+func (a *account) signatureWeight(hash [32]byte, signatures []xdr.DecoratedSignature) (weight int) {
+	for signer, signerWeight := range a.signers {
+		kp, err := keypair.Parse(signer)
+		if err != nil {
+			continue
+		}
+		hint := kp.Hint()
+		for _, signature := range signatures {
+			if signature.Hint == xdr.SignatureHint(hint) && kp.Verify(hash[:], signature.Signature) == nil {
+				weight += int(signerWeight)
+				break
+			}
+		}
+	}
+	return
+}
+
+// isAuthorized returns true when signatures meet a given threshold of the
+// account. At least one signature is required even if the threshold is 0.
+func (a *account) isAuthorized(hash [32]byte, signatures []xdr.DecoratedSignature, threshold int) bool {
+	weight := a.signatureWeight(hash, signatures)
+	return weight > 0 && weight >= int(a.thresholds[threshold])
+}
+
+// ledgerState is a set of accounts changed by a transaction. Changes are
+// written to the server only when all operations succeeded.
+type ledgerState struct {
+	accounts    map[string]*account
+	baseReserve int64
+	ledger      uint32
+}
+
+func (s *ledgerState) minBalance(a *account) int64 {
+	return (2 + a.subentries()) * s.baseReserve
+}
+
+// operationThreshold returns the threshold category of the operation.
+func operationThreshold(operation xdr.Operation) int {
+	switch operation.Body.Type {
+	case xdr.OperationTypeAllowTrust:
+		return thresholdLow
+	default:
+		return thresholdMedium
+	}
+}
+
+// isSupported returns true for operations that can be applied by Server.
+func isSupported(operation xdr.Operation) bool {
+	switch operation.Body.Type {
+	case xdr.OperationTypeCreateAccount, xdr.OperationTypePayment,
+		xdr.OperationTypeChangeTrust, xdr.OperationTypeAllowTrust:
+		return true
+	}
+	return false
+}
+
+// applyOperation applies operation to the state. record is returned when
+// the operation succeeded.
+func (s *ledgerState) applyOperation(source *account, operation xdr.Operation) (result xdr.OperationResult, record *operationRecord) {
+	body := operation.Body
+	tr := &xdr.OperationResultTr{Type: body.Type}
+	result = xdr.OperationResult{Code: xdr.OperationResultCodeOpInner, Tr: tr}
+
+	switch body.Type {
+	case xdr.OperationTypeCreateAccount:
+		var code xdr.CreateAccountResultCode
+		code, record = s.createAccount(source, *body.CreateAccountOp)
+		tr.CreateAccountResult = &xdr.CreateAccountResult{Code: code}
+	case xdr.OperationTypePayment:
+		var code xdr.PaymentResultCode
+		code, record = s.payment(source, *body.PaymentOp)
+		tr.PaymentResult = &xdr.PaymentResult{Code: code}
+	case xdr.OperationTypeChangeTrust:
+		var code xdr.ChangeTrustResultCode
+		code, record = s.changeTrust(source, *body.ChangeTrustOp)
+		tr.ChangeTrustResult = &xdr.ChangeTrustResult{Code: code}
+	case xdr.OperationTypeAllowTrust:
+		var code xdr.AllowTrustResultCode
+		code, record = s.allowTrust(source, *body.AllowTrustOp)
+		tr.AllowTrustResult = &xdr.AllowTrustResult{Code: code}
+	}
+	return
+}
+
+func (s *ledgerState) createAccount(source *account, op xdr.CreateAccountOp) (xdr.CreateAccountResultCode, *operationRecord) {
+	destination := address(op.Destination)
+	startingBalance := int64(op.StartingBalance)
+
+	switch {
+	case startingBalance <= 0 || destination == source.id:
+		return xdr.CreateAccountResultCodeCreateAccountMalformed, nil
+	case s.accounts[destination] != nil:
+		return xdr.CreateAccountResultCodeCreateAccountAlreadyExist, nil
+	case startingBalance < 2*s.baseReserve:
+		return xdr.CreateAccountResultCodeCreateAccountLowReserve, nil
+	case source.balance-startingBalance < s.minBalance(source):
+		return xdr.CreateAccountResultCodeCreateAccountUnderfunded, nil
+	}
+
+	source.balance -= startingBalance
+	s.accounts[destination] = newAccount(destination, int64(s.ledger)<<32, startingBalance)
+
+	return xdr.CreateAccountResultCodeCreateAccountSuccess, &operationRecord{
+		Type:            "create_account",
+		Funder:          source.id,
+		Account:         destination,
+		StartingBalance: formatAmount(startingBalance),
+		accounts:        []string{source.id, destination},
+	}
+}
+
+func (s *ledgerState) payment(source *account, op xdr.PaymentOp) (xdr.PaymentResultCode, *operationRecord) {
+	amount := int64(op.Amount)
+	if amount <= 0 {
+		return xdr.PaymentResultCodePaymentMalformed, nil
+	}
+
+	destination := s.accounts[address(op.Destination)]
+	if destination == nil {
+		return xdr.PaymentResultCodePaymentNoDestination, nil
+	}
+
+	var assetType, assetCode, assetIssuer string
+	if err := op.Asset.Extract(&assetType, &assetCode, &assetIssuer); err != nil {
+		return xdr.PaymentResultCodePaymentMalformed, nil
+	}
+
+	record := &operationRecord{
+		Type:        "payment",
+		From:        source.id,
+		To:          destination.id,
+		AssetType:   assetType,
+		AssetCode:   assetCode,
+		AssetIssuer: assetIssuer,
+		Amount:      formatAmount(amount),
+		accounts:    []string{source.id, destination.id},
+	}
+
+	if op.Asset.Type == xdr.AssetTypeAssetTypeNative {
+		if source.balance-amount < s.minBalance(source) {
+			return xdr.PaymentResultCodePaymentUnderfunded, nil
+		}
+		source.balance -= amount
+		destination.balance += amount
+		return xdr.PaymentResultCodePaymentSuccess, record
+	}
+
+	if s.accounts[assetIssuer] == nil {
+		return xdr.PaymentResultCodePaymentNoIssuer, nil
+	}
+
+	// Issuer sends and receives its own asset without a trustline
+	var destinationLine, sourceLine *trustline
+	if destination.id != assetIssuer {
+		destinationLine = destination.trustline(assetCode, assetIssuer)
+		switch {
+		case destinationLine == nil:
+			return xdr.PaymentResultCodePaymentNoTrust, nil
+		case !destinationLine.authorized:
+			return xdr.PaymentResultCodePaymentNotAuthorized, nil
+		case destinationLine.limit-destinationLine.balance < amount:
+			return xdr.PaymentResultCodePaymentLineFull, nil
+		}
+	}
+	if source.id != assetIssuer {
+		sourceLine = source.trustline(assetCode, assetIssuer)
+		switch {
+		case sourceLine == nil:
+			return xdr.PaymentResultCodePaymentSrcNoTrust, nil
+		case !sourceLine.authorized:
+			return xdr.PaymentResultCodePaymentSrcNotAuthorized, nil
+		case sourceLine.balance < amount:
+			return xdr.PaymentResultCodePaymentUnderfunded, nil
+		}
+	}
+
+	if sourceLine != nil {
+		sourceLine.balance -= amount
+	}
+	if destinationLine != nil {
+		destinationLine.balance += amount
+	}
+	return xdr.PaymentResultCodePaymentSuccess, record
+}
+
+func (s *ledgerState) changeTrust(source *account, op xdr.ChangeTrustOp) (xdr.ChangeTrustResultCode, *operationRecord) {
+	var assetType, assetCode, assetIssuer string
+	if op.Line.Type == xdr.AssetTypeAssetTypeNative || op.Limit < 0 {
+		return xdr.ChangeTrustResultCodeChangeTrustMalformed, nil
+	}
+	if err := op.Line.Extract(&assetType, &assetCode, &assetIssuer); err != nil || assetIssuer == source.id {
+		return xdr.ChangeTrustResultCodeChangeTrustMalformed, nil
+	}
+
+	issuer := s.accounts[assetIssuer]
+	if issuer == nil {
+		return xdr.ChangeTrustResultCodeChangeTrustNoIssuer, nil
+	}
+
+	limit := int64(op.Limit)
+	record := &operationRecord{
+		Type:        "change_trust",
+		AssetType:   assetType,
+		AssetCode:   assetCode,
+		AssetIssuer: assetIssuer,
+		Limit:       formatAmount(limit),
+		Trustor:     source.id,
+		Trustee:     assetIssuer,
+		accounts:    []string{source.id},
+	}
+
+	line := source.trustline(assetCode, assetIssuer)
+	switch {
+	case line != nil && limit == 0 && line.balance == 0:
+		source.removeTrustline(line)
+		return xdr.ChangeTrustResultCodeChangeTrustSuccess, record
+	case line != nil && limit < line.balance, line == nil && limit == 0:
+		return xdr.ChangeTrustResultCodeChangeTrustInvalidLimit, nil
+	case line != nil:
+		line.limit = limit
+		return xdr.ChangeTrustResultCodeChangeTrustSuccess, record
+	}
+
+	if source.balance < s.minBalance(source)+s.baseReserve {
+		return xdr.ChangeTrustResultCodeChangeTrustLowReserve, nil
+	}
+	source.trustlines = append(source.trustlines, &trustline{
+		assetCode:   assetCode,
+		assetIssuer: assetIssuer,
+		limit:       limit,
+		authorized:  !issuer.authRequired,
+	})
+	return xdr.ChangeTrustResultCodeChangeTrustSuccess, record
+}
+
+func (s *ledgerState) allowTrust(source *account, op xdr.AllowTrustOp) (xdr.AllowTrustResultCode, *operationRecord) {
+	var assetType, assetCode string
+	switch op.Asset.Type {
+	case xdr.AssetTypeAssetTypeCreditAlphanum4:
+		assetType = "credit_alphanum4"
+		assetCode = strings.TrimRight(string(op.Asset.AssetCode4[:]), "\x00")
+	case xdr.AssetTypeAssetTypeCreditAlphanum12:
+		assetType = "credit_alphanum12"
+		assetCode = strings.TrimRight(string(op.Asset.AssetCode12[:]), "\x00")
+	default:
+		return xdr.AllowTrustResultCodeAllowTrustMalformed, nil
+	}
+
+	trustor := s.accounts[address(op.Trustor)]
+	switch {
+	case !source.authRequired:
+		return xdr.AllowTrustResultCodeAllowTrustTrustNotRequired, nil
+	case trustor == source:
+		return xdr.AllowTrustResultCodeAllowTrustMalformed, nil
+	case !op.Authorize && !source.authRevocable:
+		return xdr.AllowTrustResultCodeAllowTrustCantRevoke, nil
+	case trustor == nil:
+		return xdr.AllowTrustResultCodeAllowTrustNoTrustLine, nil
+	}
+
+	line := trustor.trustline(assetCode, source.id)
+	if line == nil {
+		return xdr.AllowTrustResultCodeAllowTrustNoTrustLine, nil
+	}
+	line.authorized = op.Authorize
+
+	authorize := op.Authorize
+	return xdr.AllowTrustResultCodeAllowTrustSuccess, &operationRecord{
+		Type:        "allow_trust",
+		AssetType:   assetType,
+		AssetCode:   assetCode,
+		AssetIssuer: source.id,
+		Trustor:     trustor.id,
+		Trustee:     source.id,
+		Authorize:   &authorize,
+		accounts:    []string{source.id, trustor.id},
+	}
+}
+
+// address returns strkey encoded account ID.
+func address(accountId xdr.AccountId) string {
+	raw := accountId.MustEd25519()
+	return strkey.MustEncode(strkey.VersionByteAccountID, raw[:])
+}
@@ -0,0 +1,41 @@
+package horizontest
+
+import (
+	"github.com/stellar/go-stellar-base/build"
+	"github.com/stellar/go-stellar-base/xdr"
+)
+
+// ChangeTrust adds change_trust operation to a transaction (go-stellar-base
+// has no builder for it). Limit is in stroops, 0 removes the trustline.
+type ChangeTrust struct {
+	Code   string
+	Issuer string
+	Limit  xdr.Int64
+}
+
+func (m ChangeTrust) MutateTransaction(o *build.TransactionBuilder) error {
+	var issuer xdr.AccountId
+	err := issuer.SetAddress(m.Issuer)
+	if err != nil {
+		return err
+	}
+	var line xdr.Asset
+	if len(m.Code) <= 4 {
+		var code [4]byte
+		copy(code[:], m.Code)
+		line, err = xdr.NewAsset(xdr.AssetTypeAssetTypeCreditAlphanum4, xdr.AssetAlphaNum4{AssetCode: code, Issuer: issuer})
+	} else {
+		var code [12]byte
+		copy(code[:], m.Code)
+		line, err = xdr.NewAsset(xdr.AssetTypeAssetTypeCreditAlphanum12, xdr.AssetAlphaNum12{AssetCode: code, Issuer: issuer})
+	}
+	if err != nil {
+		return err
+	}
+	body, err := xdr.NewOperationBody(xdr.OperationTypeChangeTrust, xdr.ChangeTrustOp{Line: line, Limit: m.Limit})
+	if err != nil {
+		return err
+	}
+	o.TX.Operations = append(o.TX.Operations, xdr.Operation{Body: body})
+	return nil
+}
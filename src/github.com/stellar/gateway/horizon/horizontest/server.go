@@ -0,0 +1,756 @@
+// Package horizontest provides a fake horizon server for tests. It keeps
+// accounts in memory and applies submitted transactions the way
+// stellar-core does for operations used by the gateway server: sequence
+// numbers, fees, signatures and thresholds are checked and failed
+// transactions are returned with real result XDR, so the whole gateway.App
+// can be run against it.
+//
+// Supported endpoints:
+//
+//	GET  /                        server info
+//	GET  /accounts/{id}           account details
+//	GET  /accounts/{id}/payments  payments page or SSE stream
+//	GET  /operations              operations page or SSE stream
+//	GET  /transactions/{hash}     transaction with memo
+//	POST /transactions            transaction submission
+//
+// Supported operations are create_account, payment, change_trust and
+// allow_trust. Every transaction is included in its own ledger.
+package horizontest
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/go-stellar-base/amount"
+	"github.com/stellar/go-stellar-base/build"
+	"github.com/stellar/go-stellar-base/keypair"
+	"github.com/stellar/go-stellar-base/network"
+	"github.com/stellar/go-stellar-base/xdr"
+)
+
+// Server is a fake horizon server. Accounts are added with AddAccount and
+// other setup methods which panic on invalid arguments.
+type Server struct {
+	*httptest.Server
+	NetworkPassphrase string
+	// BaseFee is a minimum fee per operation in stroops
+	BaseFee int64
+	// BaseReserve in stroops, minimum balance of an account is
+	// (2 + number of trustlines and signers) * BaseReserve
+	BaseReserve int64
+
+	mutex        sync.Mutex
+	ledger       uint32
+	accounts     map[string]*account
+	transactions map[string]transactionResponse
+	operations   []operationRecord
+	// ledgerClosed is closed (and replaced) when a new ledger is closed
+	ledgerClosed chan struct{}
+	closed       chan struct{}
+	closeOnce    sync.Once
+}
+
+// NewServer starts a server for a given network. It must be closed with
+// Close.
+func NewServer(networkPassphrase string) (s *Server) {
+	s = &Server{
+		NetworkPassphrase: networkPassphrase,
+		BaseFee:           100,
+		BaseReserve:       10 * amount.One,
+		ledger:            2,
+		accounts:          map[string]*account{},
+		transactions:      map[string]transactionResponse{},
+		ledgerClosed:      make(chan struct{}),
+		closed:            make(chan struct{}),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return
+}
+
+// Close closes open streams and shuts down the server.
+func (s *Server) Close() {
+	s.closeOnce.Do(func() {
+		close(s.closed)
+	})
+	s.Server.Close()
+}
+
+// AddAccount creates account with a given balance of native asset.
+func (s *Server) AddAccount(accountId, balance string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, err := keypair.Parse(accountId); err != nil {
+		panic(err)
+	}
+	s.accounts[accountId] = newAccount(accountId, int64(s.ledger)<<32, mustParseAmount(balance))
+}
+
+// AddTrustline creates account's trustline with the maximum limit.
+func (s *Server) AddTrustline(accountId, assetCode, assetIssuer, balance string, authorized bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	a := s.mustAccount(accountId)
+	a.trustlines = append(a.trustlines, &trustline{
+		assetCode:   assetCode,
+		assetIssuer: assetIssuer,
+		balance:     mustParseAmount(balance),
+		limit:       maxLimit,
+		authorized:  authorized,
+	})
+}
+
+// SetAuthFlags sets AUTH_REQUIRED and AUTH_REVOCABLE flags of the issuing
+// account.
+func (s *Server) SetAuthFlags(accountId string, authRequired, authRevocable bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	a := s.mustAccount(accountId)
+	a.authRequired = authRequired
+	a.authRevocable = authRevocable
+}
+
+// SetSigner sets weight of account's signer (weight of the master key when
+// signerId is accountId). Signer is removed when weight is 0.
+func (s *Server) SetSigner(accountId, signerId string, weight byte) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	a := s.mustAccount(accountId)
+	if _, err := keypair.Parse(signerId); err != nil {
+		panic(err)
+	}
+	if weight == 0 {
+		delete(a.signers, signerId)
+		return
+	}
+	a.signers[signerId] = weight
+}
+
+// SetThresholds sets account's thresholds.
+func (s *Server) SetThresholds(accountId string, low, medium, high byte) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.mustAccount(accountId).thresholds = [3]byte{low, medium, high}
+}
+
+// Balance returns account's balance of a given asset (native when assetCode
+// is empty). Empty string is returned when the account or the trustline does
+// not exist.
+func (s *Server) Balance(accountId, assetCode, assetIssuer string) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	a := s.accounts[accountId]
+	if a == nil {
+		return ""
+	}
+	if assetCode == "" {
+		return formatAmount(a.balance)
+	}
+	line := a.trustline(assetCode, assetIssuer)
+	if line == nil {
+		return ""
+	}
+	return formatAmount(line.balance)
+}
+
+// IsAuthorized returns true when account's trustline exists and is
+// authorized by the issuer.
+func (s *Server) IsAuthorized(accountId, assetCode, assetIssuer string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	a := s.accounts[accountId]
+	if a == nil {
+		return false
+	}
+	line := a.trustline(assetCode, assetIssuer)
+	return line != nil && line.authorized
+}
+
+// Sequence returns the current sequence number of the account.
+func (s *Server) Sequence(accountId string) int64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.mustAccount(accountId).sequence
+}
+
+func (s *Server) mustAccount(accountId string) *account {
+	a := s.accounts[accountId]
+	if a == nil {
+		panic("horizontest: account does not exist: " + accountId)
+	}
+	return a
+}
+
+func mustParseAmount(value string) int64 {
+	parsed, err := amount.Parse(value)
+	if err != nil {
+		panic(err)
+	}
+	return int64(parsed)
+}
+
+func formatAmount(value int64) string {
+	return amount.String(xdr.Int64(value))
+}
+
+type problem struct {
+	Type   string         `json:"type"`
+	Title  string         `json:"title"`
+	Status int            `json:"status"`
+	Detail string         `json:"detail,omitempty"`
+	Extras *problemExtras `json:"extras,omitempty"`
+}
+
+type problemExtras struct {
+	EnvelopeXdr string       `json:"envelope_xdr"`
+	ResultXdr   string       `json:"result_xdr,omitempty"`
+	ResultCodes *resultCodes `json:"result_codes,omitempty"`
+}
+
+type resultCodes struct {
+	Transaction string   `json:"transaction"`
+	Operations  []string `json:"operations,omitempty"`
+}
+
+type accountResponse struct {
+	Id            string                          `json:"id"`
+	AccountId     string                          `json:"account_id"`
+	Sequence      string                          `json:"sequence"`
+	SubentryCount int64                           `json:"subentry_count"`
+	Thresholds    horizon.AccountThresholds       `json:"thresholds"`
+	Flags         accountFlags                    `json:"flags"`
+	Balances      []balanceResponse               `json:"balances"`
+	Signers       []horizon.AccountSignerResponse `json:"signers"`
+}
+
+type accountFlags struct {
+	AuthRequired  bool `json:"auth_required"`
+	AuthRevocable bool `json:"auth_revocable"`
+}
+
+type balanceResponse struct {
+	AssetType    string `json:"asset_type"`
+	AssetCode    string `json:"asset_code,omitempty"`
+	AssetIssuer  string `json:"asset_issuer,omitempty"`
+	Balance      string `json:"balance"`
+	Limit        string `json:"limit,omitempty"`
+	IsAuthorized *bool  `json:"is_authorized,omitempty"`
+}
+
+type transactionResponse struct {
+	horizon.TransactionResponse
+	SourceAccount string `json:"source_account"`
+	MemoType      string `json:"memo_type"`
+	Memo          string `json:"memo,omitempty"`
+}
+
+type operationRecord struct {
+	Id              string `json:"id"`
+	PagingToken     string `json:"paging_token"`
+	Type            string `json:"type"`
+	SourceAccount   string `json:"source_account"`
+	TransactionHash string `json:"transaction_hash"`
+	Links           struct {
+		Transaction struct {
+			Href string `json:"href"`
+		} `json:"transaction"`
+	} `json:"_links"`
+
+	From        string `json:"from,omitempty"`
+	To          string `json:"to,omitempty"`
+	AssetType   string `json:"asset_type,omitempty"`
+	AssetCode   string `json:"asset_code,omitempty"`
+	AssetIssuer string `json:"asset_issuer,omitempty"`
+	Amount      string `json:"amount,omitempty"`
+
+	Funder          string `json:"funder,omitempty"`
+	Account         string `json:"account,omitempty"`
+	StartingBalance string `json:"starting_balance,omitempty"`
+
+	Trustor   string `json:"trustor,omitempty"`
+	Trustee   string `json:"trustee,omitempty"`
+	Limit     string `json:"limit,omitempty"`
+	Authorize *bool  `json:"authorize,omitempty"`
+
+	pagingToken int64
+	// accounts that participate in the operation
+	accounts []string
+}
+
+// isPayment returns true for operations listed by the payments endpoint.
+func (r operationRecord) isPayment() bool {
+	return r.Type == "payment" || r.Type == "create_account"
+}
+
+// hasParticipant returns true when the account participates in the
+// operation.
+func (r operationRecord) hasParticipant(accountId string) bool {
+	for _, participant := range r.accounts {
+		if participant == accountId {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	switch {
+	case r.Method == "GET" && r.URL.Path == "/":
+		writeJSON(w, http.StatusOK, map[string]string{
+			"horizon_version":    "horizontest",
+			"network_passphrase": s.NetworkPassphrase,
+		})
+	case r.Method == "GET" && len(path) == 2 && path[0] == "accounts":
+		s.account(w, path[1])
+	case r.Method == "GET" && len(path) == 3 && path[0] == "accounts" && path[2] == "payments":
+		accountId := path[1]
+		s.serveOperations(w, r, func(record operationRecord) bool {
+			return record.isPayment() && record.hasParticipant(accountId)
+		})
+	case r.Method == "GET" && r.URL.Path == "/operations":
+		s.serveOperations(w, r, func(operationRecord) bool { return true })
+	case r.Method == "GET" && len(path) == 2 && path[0] == "transactions":
+		s.transaction(w, path[1])
+	case r.Method == "POST" && r.URL.Path == "/transactions":
+		s.submitTransaction(w, r)
+	default:
+		writeProblem(w, http.StatusNotFound, "not_found", "Resource Missing", "")
+	}
+}
+
+func (s *Server) account(w http.ResponseWriter, accountId string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	a := s.accounts[accountId]
+	if a == nil {
+		writeProblem(w, http.StatusNotFound, "not_found", "Resource Missing", "")
+		return
+	}
+
+	response := accountResponse{
+		Id:            a.id,
+		AccountId:     a.id,
+		Sequence:      strconv.FormatInt(a.sequence, 10),
+		SubentryCount: a.subentries(),
+		Thresholds: horizon.AccountThresholds{
+			LowThreshold:  a.thresholds[thresholdLow],
+			MedThreshold:  a.thresholds[thresholdMedium],
+			HighThreshold: a.thresholds[thresholdHigh],
+		},
+		Flags: accountFlags{AuthRequired: a.authRequired, AuthRevocable: a.authRevocable},
+	}
+
+	for _, line := range a.trustlines {
+		assetType := "credit_alphanum4"
+		if len(line.assetCode) > 4 {
+			assetType = "credit_alphanum12"
+		}
+		authorized := line.authorized
+		response.Balances = append(response.Balances, balanceResponse{
+			AssetType:    assetType,
+			AssetCode:    line.assetCode,
+			AssetIssuer:  line.assetIssuer,
+			Balance:      formatAmount(line.balance),
+			Limit:        formatAmount(line.limit),
+			IsAuthorized: &authorized,
+		})
+	}
+	response.Balances = append(response.Balances, balanceResponse{
+		AssetType: "native",
+		Balance:   formatAmount(a.balance),
+	})
+
+	for signer, weight := range a.signers {
+		response.Signers = append(response.Signers, horizon.AccountSignerResponse{
+			Address:   signer,
+			PublicKey: signer,
+			Weight:    weight,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) transaction(w http.ResponseWriter, hash string) {
+	s.mutex.Lock()
+	transaction, ok := s.transactions[hash]
+	s.mutex.Unlock()
+
+	if !ok {
+		writeProblem(w, http.StatusNotFound, "not_found", "Resource Missing", "")
+		return
+	}
+	writeJSON(w, http.StatusOK, transaction)
+}
+
+// serveOperations responds with a page or a stream of operations matching
+// a given filter.
+func (s *Server) serveOperations(w http.ResponseWriter, r *http.Request, match func(operationRecord) bool) {
+	if r.Header.Get("Accept") == "text/event-stream" {
+		s.streamOperations(w, r, match)
+	} else {
+		s.operationsPage(w, r, match)
+	}
+}
+
+// operationsPage responds with matching operations after cursor.
+func (s *Server) operationsPage(w http.ResponseWriter, r *http.Request, match func(operationRecord) bool) {
+	query := r.URL.Query()
+
+	limit := 10
+	if query.Get("limit") != "" {
+		var err error
+		limit, err = strconv.Atoi(query.Get("limit"))
+		if err != nil || limit < 1 || limit > 200 {
+			writeProblem(w, http.StatusBadRequest, "bad_request", "Bad Request", "invalid limit")
+			return
+		}
+	}
+
+	desc := query.Get("order") == "desc"
+	cursor, ok := s.parseCursor(query.Get("cursor"), desc)
+	if !ok {
+		writeProblem(w, http.StatusBadRequest, "bad_request", "Bad Request", "invalid cursor")
+		return
+	}
+
+	s.mutex.Lock()
+	records := s.findOperations(match, cursor, desc, limit)
+	s.mutex.Unlock()
+
+	var response struct {
+		Embedded struct {
+			Records []operationRecord `json:"records"`
+		} `json:"_embedded"`
+	}
+	response.Embedded.Records = append([]operationRecord{}, records...)
+	writeJSON(w, http.StatusOK, response)
+}
+
+// streamOperations streams matching operations after cursor (or
+// Last-Event-ID) until the client disconnects or the server is closed.
+func (s *Server) streamOperations(w http.ResponseWriter, r *http.Request, match func(operationRecord) bool) {
+	cursorParam := r.URL.Query().Get("cursor")
+	if r.Header.Get("Last-Event-ID") != "" {
+		cursorParam = r.Header.Get("Last-Event-ID")
+	}
+	cursor, ok := s.parseCursor(cursorParam, false)
+	if !ok {
+		writeProblem(w, http.StatusBadRequest, "bad_request", "Bad Request", "invalid cursor")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "retry: 1000\nevent: open\ndata: \"hello\"\n\n")
+
+	for {
+		s.mutex.Lock()
+		records := s.findOperations(match, cursor, false, -1)
+		ledgerClosed := s.ledgerClosed
+		s.mutex.Unlock()
+
+		for _, record := range records {
+			data, err := json.Marshal(record)
+			if err != nil {
+				return
+			}
+			fmt.Fprintf(w, "id: %s\ndata: %s\n\n", record.PagingToken, data)
+			cursor = record.pagingToken
+		}
+		w.(http.Flusher).Flush()
+
+		select {
+		case <-ledgerClosed:
+		case <-r.Context().Done():
+			return
+		case <-s.closed:
+			return
+		}
+	}
+}
+
+// parseCursor parses paging token. Empty cursor is before (or after in
+// descending order) all operations, "now" is after all existing operations.
+func (s *Server) parseCursor(cursor string, desc bool) (int64, bool) {
+	switch {
+	case cursor == "" && desc:
+		return maxLimit, true
+	case cursor == "":
+		return 0, true
+	case cursor == "now":
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		return int64(s.ledger+1)<<32 - 1, true
+	}
+	value, err := strconv.ParseInt(cursor, 10, 64)
+	return value, err == nil && value >= 0
+}
+
+// findOperations returns matching operations after cursor. All matching
+// operations are returned when limit is negative.
+func (s *Server) findOperations(match func(operationRecord) bool, cursor int64, desc bool, limit int) (records []operationRecord) {
+	for i := range s.operations {
+		record := s.operations[i]
+		if desc {
+			record = s.operations[len(s.operations)-1-i]
+		}
+		if limit >= 0 && len(records) >= limit {
+			return
+		}
+		if (desc && record.pagingToken >= cursor) || (!desc && record.pagingToken <= cursor) {
+			continue
+		}
+		if match(record) {
+			records = append(records, record)
+		}
+	}
+	return
+}
+
+func (s *Server) submitTransaction(w http.ResponseWriter, r *http.Request) {
+	envelopeXdr := r.PostFormValue("tx")
+
+	var envelope xdr.TransactionEnvelope
+	err := xdr.SafeUnmarshalBase64(envelopeXdr, &envelope)
+	if err != nil {
+		writeProblem(w, http.StatusBadRequest, "transaction_malformed", "Transaction Malformed", err.Error())
+		return
+	}
+
+	for _, operation := range envelope.Tx.Operations {
+		if !isSupported(operation) {
+			writeProblem(w, http.StatusBadRequest, "transaction_malformed", "Transaction Malformed",
+				fmt.Sprintf("operation type %d is not supported by horizontest", operation.Body.Type))
+			return
+		}
+	}
+
+	networkId := network.ID(s.NetworkPassphrase)
+	txBuilder := build.TransactionBuilder{TX: &envelope.Tx, NetworkID: networkId}
+	hash, err := txBuilder.Hash()
+	if err != nil {
+		writeProblem(w, http.StatusBadRequest, "transaction_malformed", "Transaction Malformed", err.Error())
+		return
+	}
+	hashHex := hex.EncodeToString(hash[:])
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Transaction was already included in a ledger
+	if transaction, ok := s.transactions[hashHex]; ok {
+		writeJSON(w, http.StatusOK, transaction.TransactionResponse)
+		return
+	}
+
+	result, operations := s.applyTransaction(envelope, hash)
+	resultXdr, err := xdr.MarshalBase64(result)
+	if err != nil {
+		writeProblem(w, http.StatusInternalServerError, "server_error", "Internal Server Error", err.Error())
+		return
+	}
+
+	if result.Result.Code != xdr.TransactionResultCodeTxSuccess {
+		extras := &problemExtras{EnvelopeXdr: envelopeXdr, ResultXdr: resultXdr}
+		errors, err := horizon.DecodeTransactionResult(resultXdr)
+		if err == nil {
+			extras.ResultCodes = &resultCodes{
+				Transaction: errors.TransactionErrorCode,
+				Operations:  errors.OperationErrorCodes,
+			}
+		}
+		writeJSON(w, http.StatusBadRequest, problem{
+			Type:   "transaction_failed",
+			Title:  "Transaction Failed",
+			Status: http.StatusBadRequest,
+			Extras: extras,
+		})
+		return
+	}
+
+	ledger := uint64(s.ledger)
+	transaction := transactionResponse{
+		TransactionResponse: horizon.TransactionResponse{
+			Hash:        hashHex,
+			Ledger:      &ledger,
+			EnvelopeXdr: envelopeXdr,
+			ResultXdr:   resultXdr,
+		},
+		SourceAccount: address(envelope.Tx.SourceAccount),
+	}
+	transaction.MemoType, transaction.Memo = memo(envelope.Tx.Memo)
+	s.transactions[hashHex] = transaction
+
+	for _, operation := range operations {
+		operation.TransactionHash = hashHex
+		operation.Links.Transaction.Href = s.URL + "/transactions/" + hashHex
+		s.operations = append(s.operations, operation)
+	}
+
+	writeJSON(w, http.StatusOK, transaction.TransactionResponse)
+}
+
+// applyTransaction validates the transaction and applies its operations.
+// Transactions that passed validation are included in a new ledger: fee is
+// charged and sequence number is consumed even if operations failed. Must be
+// called with s.mutex locked.
+func (s *Server) applyTransaction(envelope xdr.TransactionEnvelope, hash [32]byte) (result xdr.TransactionResult, records []operationRecord) {
+	tx := envelope.Tx
+	source := s.accounts[address(tx.SourceAccount)]
+	fee := int64(tx.Fee)
+	now := uint64(time.Now().Unix())
+
+	switch {
+	case source == nil:
+		result.Result.Code = xdr.TransactionResultCodeTxNoAccount
+	case len(tx.Operations) == 0:
+		result.Result.Code = xdr.TransactionResultCodeTxMissingOperation
+	case tx.TimeBounds != nil && uint64(tx.TimeBounds.MinTime) > now:
+		result.Result.Code = xdr.TransactionResultCodeTxTooEarly
+	case tx.TimeBounds != nil && tx.TimeBounds.MaxTime != 0 && uint64(tx.TimeBounds.MaxTime) < now:
+		result.Result.Code = xdr.TransactionResultCodeTxTooLate
+	case fee < s.BaseFee*int64(len(tx.Operations)):
+		result.Result.Code = xdr.TransactionResultCodeTxInsufficientFee
+	case int64(tx.SeqNum) != source.sequence+1:
+		result.Result.Code = xdr.TransactionResultCodeTxBadSeq
+	case !source.isAuthorized(hash, envelope.Signatures, thresholdLow):
+		result.Result.Code = xdr.TransactionResultCodeTxBadAuth
+	case source.balance < fee:
+		result.Result.Code = xdr.TransactionResultCodeTxInsufficientBalance
+	}
+	if result.Result.Code != xdr.TransactionResultCodeTxSuccess {
+		return
+	}
+
+	s.ledger++
+	source.balance -= fee
+	source.sequence = int64(tx.SeqNum)
+	result.FeeCharged = xdr.Int64(fee)
+
+	state := ledgerState{
+		accounts:    map[string]*account{},
+		baseReserve: s.BaseReserve,
+		ledger:      s.ledger,
+	}
+	for id, a := range s.accounts {
+		state.accounts[id] = a.clone()
+	}
+
+	failed := false
+	results := make([]xdr.OperationResult, len(tx.Operations))
+	for i, operation := range tx.Operations {
+		operationSource := state.accounts[address(tx.SourceAccount)]
+		if operation.SourceAccount != nil {
+			operationSource = state.accounts[address(*operation.SourceAccount)]
+		}
+
+		var record *operationRecord
+		switch {
+		case operationSource == nil:
+			results[i] = xdr.OperationResult{Code: xdr.OperationResultCodeOpNoAccount}
+		case !operationSource.isAuthorized(hash, envelope.Signatures, operationThreshold(operation)):
+			results[i] = xdr.OperationResult{Code: xdr.OperationResultCodeOpBadAuth}
+		default:
+			results[i], record = state.applyOperation(operationSource, operation)
+		}
+
+		if operationFailed(results[i]) {
+			failed = true
+			continue
+		}
+
+		// Operation ID: ledger, transaction index (always 1) and operation
+		// index (starting from 1)
+		record.pagingToken = int64(s.ledger)<<32 | 1<<12 | int64(i+1)
+		record.Id = strconv.FormatInt(record.pagingToken, 10)
+		record.PagingToken = record.Id
+		record.SourceAccount = operationSource.id
+		records = append(records, *record)
+	}
+
+	result.Result.Results = &results
+	if failed {
+		result.Result.Code = xdr.TransactionResultCodeTxFailed
+		records = nil
+	} else {
+		s.accounts = state.accounts
+	}
+
+	s.closeLedger()
+	return
+}
+
+// closeLedger notifies streams about the new ledger.
+func (s *Server) closeLedger() {
+	close(s.ledgerClosed)
+	s.ledgerClosed = make(chan struct{})
+}
+
+// operationFailed returns true when the operation result is not a
+// success.
+func operationFailed(result xdr.OperationResult) bool {
+	if result.Code != xdr.OperationResultCodeOpInner {
+		return true
+	}
+	tr := result.Tr
+	switch {
+	case tr.CreateAccountResult != nil:
+		return tr.CreateAccountResult.Code != xdr.CreateAccountResultCodeCreateAccountSuccess
+	case tr.PaymentResult != nil:
+		return tr.PaymentResult.Code != xdr.PaymentResultCodePaymentSuccess
+	case tr.ChangeTrustResult != nil:
+		return tr.ChangeTrustResult.Code != xdr.ChangeTrustResultCodeChangeTrustSuccess
+	case tr.AllowTrustResult != nil:
+		return tr.AllowTrustResult.Code != xdr.AllowTrustResultCodeAllowTrustSuccess
+	}
+	return true
+}
+
+// memo returns memo type and value the way horizon shows them.
+func memo(m xdr.Memo) (memoType, value string) {
+	switch m.Type {
+	case xdr.MemoTypeMemoText:
+		return "text", *m.Text
+	case xdr.MemoTypeMemoId:
+		return "id", strconv.FormatUint(uint64(*m.Id), 10)
+	case xdr.MemoTypeMemoHash:
+		return "hash", base64.StdEncoding.EncodeToString(m.Hash[:])
+	case xdr.MemoTypeMemoReturn:
+		return "return", base64.StdEncoding.EncodeToString(m.RetHash[:])
+	}
+	return "none", ""
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, response interface{}) {
+	body, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/hal+json; charset=utf-8")
+	w.WriteHeader(statusCode)
+	w.Write(body)
+}
+
+func writeProblem(w http.ResponseWriter, statusCode int, problemType, title, detail string) {
+	writeJSON(w, statusCode, problem{
+		Type:   problemType,
+		Title:  title,
+		Status: statusCode,
+		Detail: detail,
+	})
+}
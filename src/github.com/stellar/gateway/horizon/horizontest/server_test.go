@@ -0,0 +1,336 @@
+package horizontest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/go-stellar-base/build"
+	"github.com/stellar/go-stellar-base/keypair"
+	"github.com/stretchr/testify/assert"
+)
+
+const networkPassphrase = "Test SDF Network ; September 2015"
+
+// operationError returns errors of a failed transaction with one operation.
+func operationError(code string) *horizon.SubmitTransactionResponseError {
+	return &horizon.SubmitTransactionResponseError{
+		TransactionErrorCode: "transaction_failed",
+		OperationErrorCode:   code,
+		OperationErrorCodes:  []string{code},
+	}
+}
+
+func TestServer(t *testing.T) {
+	issuingSeed := "SC34WILLHVADXMP6ACPMIRA6TRAWJMVCLPFNW7S6MUMXJVLAZUC4EWHP"
+	issuingAccountId := "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR"
+
+	Convey("Given horizontest server", t, func() {
+		server := NewServer(networkPassphrase)
+		defer server.Close()
+
+		client := horizon.New(server.URL)
+		client.RetryBackoff = time.Millisecond
+		ctx := context.Background()
+
+		sender, err := keypair.Random()
+		assert.Nil(t, err)
+		receiver, err := keypair.Random()
+		assert.Nil(t, err)
+
+		server.AddAccount(issuingAccountId, "1000")
+		server.AddAccount(sender.Address(), "100")
+		server.AddTrustline(sender.Address(), "USD", issuingAccountId, "50", true)
+
+		// submit signs and submits transaction with the next sequence number
+		// of the source account
+		submit := func(seed string, muts ...build.TransactionMutator) horizon.SubmitTransactionResponse {
+			source := keypair.MustParse(seed).Address()
+			muts = append(muts,
+				build.SourceAccount{seed},
+				build.Sequence{uint64(server.Sequence(source) + 1)},
+				build.Network{networkPassphrase},
+			)
+			txe := build.Transaction(muts...).Sign(seed)
+			txeB64, err := txe.Base64()
+			assert.Nil(t, err)
+
+			response, err := client.SubmitTransaction(ctx, txeB64)
+			assert.Nil(t, err)
+			return response
+		}
+
+		Convey("it should return account", func() {
+			account, err := client.LoadAccount(ctx, sender.Address())
+			assert.Nil(t, err)
+			assert.Equal(t, sender.Address(), account.AccountId)
+			assert.True(t, account.HasTrustline("USD", issuingAccountId))
+			assert.Equal(t, "50.0000000", account.Balances[0].Balance)
+			assert.Equal(t, "native", account.Balances[1].AssetType)
+			assert.Equal(t, "100.0000000", account.Balances[1].Balance)
+			assert.Equal(t, 1, len(account.Signers))
+			assert.Equal(t, byte(1), account.Signers[0].Weight)
+
+			_, err = client.LoadAccount(ctx, receiver.Address())
+			assert.Equal(t, horizon.ErrNotFound, err)
+		})
+
+		Convey("When account is created and paid", func() {
+			response := submit(sender.Seed(),
+				build.CreateAccount(build.Destination{receiver.Address()}, build.NativeAmount{"50"}),
+				build.MemoText{"hello"},
+			)
+			assert.Nil(t, response.Errors)
+			assert.NotNil(t, response.Ledger)
+
+			response = submit(receiver.Seed(), ChangeTrust{"USD", issuingAccountId, 1000 * 10000000})
+			assert.Nil(t, response.Errors)
+
+			response = submit(sender.Seed(), build.Payment(
+				build.Destination{receiver.Address()},
+				build.CreditAmount{"USD", issuingAccountId, "20"},
+			))
+			assert.Nil(t, response.Errors)
+
+			Convey("it should update balances and sequence numbers", func() {
+				assert.Equal(t, "49.9999800", server.Balance(sender.Address(), "", ""))
+				assert.Equal(t, "30.0000000", server.Balance(sender.Address(), "USD", issuingAccountId))
+				assert.Equal(t, "49.9999900", server.Balance(receiver.Address(), "", ""))
+				assert.Equal(t, "20.0000000", server.Balance(receiver.Address(), "USD", issuingAccountId))
+			})
+
+			Convey("it should return payments with memo", func() {
+				payments, err := client.LoadPayments(ctx, receiver.Address(), "", 200)
+				assert.Nil(t, err)
+				if !assert.Equal(t, 2, len(payments)) {
+					return
+				}
+				assert.Equal(t, "create_account", payments[0].Type)
+				assert.Equal(t, sender.Address(), payments[0].Funder)
+				assert.Equal(t, "50.0000000", payments[0].StartingBalance)
+				assert.Equal(t, "payment", payments[1].Type)
+				assert.Equal(t, "USD", payments[1].AssetCode)
+				assert.Equal(t, "20.0000000", payments[1].Amount)
+
+				err = client.LoadMemo(ctx, &payments[0])
+				assert.Nil(t, err)
+				assert.Equal(t, "text", payments[0].Memo.Type)
+				assert.Equal(t, "hello", payments[0].Memo.Value)
+
+				payments, err = client.LoadPayments(ctx, receiver.Address(), payments[0].PagingToken, 200)
+				assert.Nil(t, err)
+				assert.Equal(t, 1, len(payments))
+			})
+
+			Convey("it should return transaction", func() {
+				transaction, err := client.LoadTransaction(ctx, response.Hash)
+				assert.Nil(t, err)
+				assert.Equal(t, response.Hash, transaction.Hash)
+				assert.Equal(t, *response.Ledger, *transaction.Ledger)
+			})
+		})
+
+		Convey("When sequence number is invalid", func() {
+			txe := build.Transaction(
+				build.SourceAccount{sender.Seed()},
+				build.Sequence{uint64(server.Sequence(sender.Address()) + 2)},
+				build.Network{networkPassphrase},
+				build.Payment(build.Destination{issuingAccountId}, build.NativeAmount{"1"}),
+			).Sign(sender.Seed())
+			txeB64, err := txe.Base64()
+			assert.Nil(t, err)
+
+			Convey("it should return transaction_bad_seq without charging fee", func() {
+				response, err := client.SubmitTransaction(ctx, txeB64)
+				assert.Nil(t, err)
+				assert.Equal(t, &horizon.SubmitTransactionResponseError{TransactionErrorCode: "transaction_bad_seq"}, response.Errors)
+				assert.Equal(t, "100.0000000", server.Balance(sender.Address(), "", ""))
+			})
+		})
+
+		Convey("When transaction is signed by other key", func() {
+			txe := build.Transaction(
+				build.SourceAccount{sender.Address()},
+				build.Sequence{uint64(server.Sequence(sender.Address()) + 1)},
+				build.Network{networkPassphrase},
+				build.Payment(build.Destination{issuingAccountId}, build.NativeAmount{"1"}),
+			).Sign(receiver.Seed())
+			txeB64, err := txe.Base64()
+			assert.Nil(t, err)
+
+			Convey("it should return transaction_bad_auth", func() {
+				response, err := client.SubmitTransaction(ctx, txeB64)
+				assert.Nil(t, err)
+				assert.Equal(t, &horizon.SubmitTransactionResponseError{TransactionErrorCode: "transaction_bad_auth"}, response.Errors)
+			})
+		})
+
+		Convey("When account has additional signer", func() {
+			server.SetSigner(sender.Address(), receiver.Address(), 1)
+			server.SetThresholds(sender.Address(), 0, 2, 2)
+
+			Convey("it should require signatures meeting the threshold", func() {
+				response := submit(sender.Seed(), build.Payment(build.Destination{issuingAccountId}, build.NativeAmount{"1"}))
+				assert.Equal(t, operationError("operation_bad_auth"), response.Errors)
+
+				txe := build.Transaction(
+					build.SourceAccount{sender.Address()},
+					build.Sequence{uint64(server.Sequence(sender.Address()) + 1)},
+					build.Network{networkPassphrase},
+					build.Payment(build.Destination{issuingAccountId}, build.NativeAmount{"1"}),
+				).Sign(sender.Seed(), receiver.Seed())
+				txeB64, err := txe.Base64()
+				assert.Nil(t, err)
+
+				response, err = client.SubmitTransaction(ctx, txeB64)
+				assert.Nil(t, err)
+				assert.Nil(t, response.Errors)
+			})
+		})
+
+		Convey("When payment fails", func() {
+			server.AddAccount(receiver.Address(), "30")
+
+			Convey("it should return operation error and charge fee only", func() {
+				response := submit(sender.Seed(), build.Payment(
+					build.Destination{receiver.Address()},
+					build.CreditAmount{"USD", issuingAccountId, "20"},
+				))
+				assert.Equal(t, operationError("payment_no_trust"), response.Errors)
+				assert.Equal(t, "50.0000000", server.Balance(sender.Address(), "USD", issuingAccountId))
+				assert.Equal(t, "99.9999900", server.Balance(sender.Address(), "", ""))
+
+				response = submit(sender.Seed(), build.Payment(
+					build.Destination{issuingAccountId},
+					build.CreditAmount{"USD", issuingAccountId, "60"},
+				))
+				assert.Equal(t, operationError("payment_underfunded"), response.Errors)
+
+				response = submit(sender.Seed(), build.Payment(
+					build.Destination{receiver.Address()},
+					build.NativeAmount{"80"},
+				))
+				assert.Equal(t, operationError("payment_underfunded"), response.Errors)
+
+				response = submit(sender.Seed(),
+					build.Payment(build.Destination{receiver.Address()}, build.NativeAmount{"1"}),
+					build.CreateAccount(build.Destination{receiver.Address()}, build.NativeAmount{"30"}),
+				)
+				assert.Equal(t, &horizon.SubmitTransactionResponseError{
+					TransactionErrorCode: "transaction_failed",
+					OperationErrorCode:   "create_account_already_exist",
+					OperationErrorCodes:  []string{"", "create_account_already_exist"},
+				}, response.Errors)
+				assert.Equal(t, "30.0000000", server.Balance(receiver.Address(), "", ""))
+			})
+		})
+
+		Convey("When issuer requires authorization", func() {
+			server.SetAuthFlags(issuingAccountId, true, false)
+			server.AddAccount(receiver.Address(), "50")
+			response := submit(receiver.Seed(), ChangeTrust{"USD", issuingAccountId, 1000 * 10000000})
+			assert.Nil(t, response.Errors)
+
+			Convey("it should reject payments until trustline is authorized", func() {
+				assert.False(t, server.IsAuthorized(receiver.Address(), "USD", issuingAccountId))
+
+				response = submit(issuingSeed, build.Payment(
+					build.Destination{receiver.Address()},
+					build.CreditAmount{"USD", issuingAccountId, "20"},
+				))
+				assert.Equal(t, operationError("payment_not_authorized"), response.Errors)
+
+				response = submit(issuingSeed, build.AllowTrust(
+					build.Trustor{receiver.Address()},
+					build.AllowTrustAsset{"USD"},
+					build.Authorize{true},
+				))
+				assert.Nil(t, response.Errors)
+				assert.True(t, server.IsAuthorized(receiver.Address(), "USD", issuingAccountId))
+
+				response = submit(issuingSeed, build.AllowTrust(
+					build.Trustor{receiver.Address()},
+					build.AllowTrustAsset{"USD"},
+					build.Authorize{false},
+				))
+				assert.Equal(t, operationError("allow_trust_trust_cant_revoke"), response.Errors)
+			})
+		})
+
+		Convey("When streaming payments", func() {
+			server.AddAccount(receiver.Address(), "30")
+			submit(sender.Seed(), build.Payment(build.Destination{receiver.Address()}, build.NativeAmount{"1"}))
+
+			Convey("it should stream existing and new payments", func() {
+				streamCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+				defer cancel()
+
+				var payments []horizon.PaymentResponse
+				err := client.StreamPayments(streamCtx, receiver.Address(), nil, func(payment horizon.PaymentResponse) error {
+					payments = append(payments, payment)
+					if len(payments) == 1 {
+						go submit(sender.Seed(), build.Payment(build.Destination{receiver.Address()}, build.NativeAmount{"2"}))
+					} else {
+						cancel()
+					}
+					return nil
+				})
+				assert.Equal(t, context.Canceled, err)
+				if assert.Equal(t, 2, len(payments)) {
+					assert.Equal(t, "1.0000000", payments[0].Amount)
+					assert.Equal(t, "2.0000000", payments[1].Amount)
+				}
+			})
+
+			Convey("it should start stream after existing payments with now cursor", func() {
+				streamCtx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+				defer cancel()
+
+				cursor := "now"
+				var payments []horizon.PaymentResponse
+				client.StreamPayments(streamCtx, receiver.Address(), &cursor, func(payment horizon.PaymentResponse) error {
+					payments = append(payments, payment)
+					return nil
+				})
+				assert.Equal(t, 0, len(payments))
+			})
+		})
+
+		Convey("When streaming operations", func() {
+			server.AddAccount(receiver.Address(), "50")
+			submit(receiver.Seed(), ChangeTrust{"USD", issuingAccountId, 1000 * 10000000})
+			submit(sender.Seed(), build.Payment(build.Destination{receiver.Address()}, build.NativeAmount{"1"}))
+
+			Convey("it should stream operations of all accounts", func() {
+				streamCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+				defer cancel()
+
+				var operations []horizon.OperationResponse
+				err := client.StreamOperations(streamCtx, nil, func(operation horizon.OperationResponse) error {
+					operations = append(operations, operation)
+					if len(operations) == 2 {
+						cancel()
+					}
+					return nil
+				})
+				assert.Equal(t, context.Canceled, err)
+				if assert.Equal(t, 2, len(operations)) {
+					assert.Equal(t, "change_trust", operations[0].Type)
+					assert.Equal(t, receiver.Address(), operations[0].Trustor)
+					assert.Equal(t, issuingAccountId, operations[0].Trustee)
+					assert.Equal(t, "USD", operations[0].AssetCode)
+					assert.Equal(t, "1000.0000000", operations[0].Limit)
+					assert.Equal(t, "payment", operations[1].Type)
+				}
+			})
+
+			Convey("it should not list change_trust in payments", func() {
+				payments, err := client.LoadPayments(ctx, receiver.Address(), "", 200)
+				assert.Nil(t, err)
+				assert.Equal(t, 1, len(payments))
+			})
+		})
+	})
+}
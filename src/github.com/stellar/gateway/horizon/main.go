@@ -1,68 +1,86 @@
 package horizon
 
 import (
-	"bufio"
-	"encoding/base64"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/Sirupsen/logrus"
-	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
-
-	"github.com/stellar/go-stellar-base/xdr"
 )
 
 type PaymentHandler func(PaymentResponse) error
 
+type OperationHandler func(OperationResponse) error
+
 type HorizonInterface interface {
-	LoadAccount(accountId string) (response AccountResponse, err error)
-	LoadMemo(p *PaymentResponse) (err error)
-	StreamPayments(accountId string, cursor *string, onPaymentHandler PaymentHandler) (err error)
-	SubmitTransaction(txeBase64 string) (response SubmitTransactionResponse, err error)
+	LoadAccount(ctx context.Context, accountId string) (response AccountResponse, err error)
+	LoadMemo(ctx context.Context, p *PaymentResponse) (err error)
+	LoadTransaction(ctx context.Context, hash string) (response TransactionResponse, err error)
+	LoadPayments(ctx context.Context, accountId string, cursor string, limit int) (payments []PaymentResponse, err error)
+	StreamPayments(ctx context.Context, accountId string, cursor *string, onPaymentHandler PaymentHandler) (err error)
+	StreamOperations(ctx context.Context, cursor *string, onOperationHandler OperationHandler) (err error)
+	SubmitTransaction(ctx context.Context, txeBase64 string) (response SubmitTransactionResponse, err error)
 }
 
 type Horizon struct {
 	ServerUrl string
-	log       *logrus.Entry
+	Client    *http.Client
+	// Timeout of a single read request
+	Timeout time.Duration
+	// SubmitTimeout of transaction submission. Horizon responds when the
+	// transaction is included in a ledger so it should be longer than Timeout.
+	SubmitTimeout time.Duration
+	// StreamIdleTimeout is a maximum time without any data received from
+	// a stream after which it's reconnected
+	StreamIdleTimeout time.Duration
+	// MaxRetries is a number of times failed read request is retried (or
+	// stream is reconnected)
+	MaxRetries int
+	// RetryBackoff is a delay before the first retry. It's doubled before
+	// every next retry.
+	RetryBackoff time.Duration
+	log          *logrus.Entry
 }
 
 func New(serverUrl string) (horizon Horizon) {
 	horizon.ServerUrl = serverUrl
+	horizon.Client = &http.Client{}
+	horizon.Timeout = 10 * time.Second
+	horizon.SubmitTimeout = 60 * time.Second
+	horizon.StreamIdleTimeout = time.Minute
+	horizon.MaxRetries = 3
+	horizon.RetryBackoff = 500 * time.Millisecond
 	horizon.log = logrus.WithFields(logrus.Fields{
 		"service": "Horizon",
+		"horizon": serverUrl,
 	})
 	return
 }
 
-func (h *Horizon) LoadAccount(accountId string) (response AccountResponse, err error) {
+func (h *Horizon) LoadAccount(ctx context.Context, accountId string) (response AccountResponse, err error) {
 	h.log.WithFields(logrus.Fields{
 		"accountId": accountId,
 	}).Info("Loading account")
-	resp, err := http.Get(h.ServerUrl + "/accounts/" + accountId)
-	if err != nil {
-		return
-	}
 
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return
-	}
-
-	if resp.StatusCode != 200 {
+	err = h.get(ctx, h.ServerUrl+"/accounts/"+accountId, &response)
+	if err == ErrNotFound {
 		h.log.WithFields(logrus.Fields{
 			"accountId": accountId,
 		}).Error("Account does not exist")
-		err = fmt.Errorf("StatusCode indicates error: %s", body)
 		return
 	}
-
-	err = json.Unmarshal(body, &response)
 	if err != nil {
+		h.log.WithFields(logrus.Fields{
+			"accountId": accountId,
+			"err":       err,
+		}).Error("Error loading account")
 		return
 	}
 
@@ -72,96 +90,152 @@ func (h *Horizon) LoadAccount(accountId string) (response AccountResponse, err e
 	return
 }
 
-func (h *Horizon) LoadMemo(p *PaymentResponse) (err error) {
-	res, err := http.Get(p.Links.Transaction.Href)
-	if err != nil {
-		return err
+// LoadMemo loads memo of the transaction the payment belongs to. Transaction
+// is loaded from this server even if the payment was streamed from another one.
+func (h *Horizon) LoadMemo(ctx context.Context, p *PaymentResponse) (err error) {
+	hash := p.GetTransactionHash()
+	if hash == "" {
+		return fmt.Errorf("Invalid transaction link: %s", p.Links.Transaction.Href)
 	}
-	defer res.Body.Close()
-	return json.NewDecoder(res.Body).Decode(&p.Memo)
+	return h.get(ctx, h.ServerUrl+"/transactions/"+hash, &p.Memo)
 }
 
-func (h *Horizon) StreamPayments(accountId string, cursor *string, onPaymentHandler PaymentHandler) (err error) {
-	url := h.ServerUrl + "/accounts/" + accountId + "/payments"
-	if cursor != nil {
-		url += "?cursor=" + *cursor
-	}
+// Ping checks if horizon server responds.
+func (h *Horizon) Ping(ctx context.Context) (err error) {
+	var response map[string]interface{}
+	return h.getOnce(ctx, h.ServerUrl+"/", &response)
+}
 
-	req, _ := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return
+// LoadTransaction loads transaction with a given hash. Returns ErrNotFound
+// when the transaction is not in a ledger.
+func (h *Horizon) LoadTransaction(ctx context.Context, hash string) (response TransactionResponse, err error) {
+	h.log.WithFields(logrus.Fields{
+		"hash": hash,
+	}).Info("Loading transaction")
+	err = h.get(ctx, h.ServerUrl+"/transactions/"+hash, &response)
+	return
+}
+
+// LoadPayments loads a page of payments of a given account (in ascending
+// order) after cursor. Empty cursor loads payments from the beginning.
+func (h *Horizon) LoadPayments(ctx context.Context, accountId string, cursor string, limit int) (payments []PaymentResponse, err error) {
+	query := url.Values{}
+	query.Set("order", "asc")
+	query.Set("limit", strconv.Itoa(limit))
+	if cursor != "" {
+		query.Set("cursor", cursor)
 	}
-	req.Header.Set("Accept", "text/event-stream")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	var response PaymentsPageResponse
+	err = h.get(ctx, h.ServerUrl+"/accounts/"+accountId+"/payments?"+query.Encode(), &response)
 	if err != nil {
-		return err
+		return
 	}
-	defer resp.Body.Close()
-	scanner := bufio.NewScanner(resp.Body)
-	scanner.Split(splitSSE)
+	return response.Embedded.Records, nil
+}
 
-	for scanner.Scan() {
-		if len(scanner.Bytes()) == 0 {
-			continue
+// StreamPayments streams payments of a given account after cursor. Stream
+// is reconnected (with the id of the last handled payment) when it's closed
+// or stalls for StreamIdleTimeout, an error is returned after MaxRetries
+// failed connections in a row.
+func (h *Horizon) StreamPayments(ctx context.Context, accountId string, cursor *string, onPaymentHandler PaymentHandler) (err error) {
+	return h.stream(ctx, "/accounts/"+accountId+"/payments", cursor, func(data []byte) (err error) {
+		var payment PaymentResponse
+		err = json.Unmarshal(data, &payment)
+		if err != nil {
+			return
 		}
+		return h.retryHandler(ctx, func() error {
+			return onPaymentHandler(payment)
+		})
+	})
+}
 
-		ev, err := parseEvent(scanner.Bytes())
+// StreamOperations streams all operations of the network after cursor.
+// Reconnects the same way as StreamPayments.
+func (h *Horizon) StreamOperations(ctx context.Context, cursor *string, onOperationHandler OperationHandler) (err error) {
+	return h.stream(ctx, "/operations", cursor, func(data []byte) (err error) {
+		var operation OperationResponse
+		err = json.Unmarshal(data, &operation)
 		if err != nil {
-			return err
+			return
 		}
+		return h.retryHandler(ctx, func() error {
+			return onOperationHandler(operation)
+		})
+	})
+}
 
-		if ev.Event != "message" {
-			continue
-		}
+// stream calls onMessage with data of every message streamed from a given
+// path after cursor.
+func (h *Horizon) stream(ctx context.Context, path string, cursor *string, onMessage func(data []byte) error) (err error) {
+	url := h.ServerUrl + path
+	if cursor != nil {
+		url += "?cursor=" + *cursor
+	}
 
-		var payment PaymentResponse
-		data := ev.Data.(string)
-		err = json.Unmarshal([]byte(data), &payment)
-		if err != nil {
-			return err
+	eventSource := NewEventSource(h.Client, url)
+	eventSource.MaxRetries = h.MaxRetries
+	eventSource.IdleTimeout = h.StreamIdleTimeout
+
+	return eventSource.Stream(ctx, func(event Event) (err error) {
+		if event.Type != "message" {
+			return nil
 		}
+		return onMessage([]byte(event.Data))
+	})
+}
 
-		for {
-			err = onPaymentHandler(payment)
-			if err != nil {
-				h.log.Error("Error from onPaymentHandler: ", err)
-				h.log.Info("Sleeping...")
-				time.Sleep(10 * time.Second)
-			} else {
-				break
-			}
+// retryHandler calls handler until it succeeds (every 10 seconds) so the
+// stream does not move past an event that was not processed.
+func (h *Horizon) retryHandler(ctx context.Context, handler func() error) (err error) {
+	for {
+		err = handler()
+		if err == nil {
+			return
 		}
-	}
 
-	err = scanner.Err()
-	if err == io.ErrUnexpectedEOF {
-		h.log.Info("Streaming connection closed.")
-		return nil
-	}
-	if err != nil {
-		return err
+		h.log.Error("Error from stream handler: ", err)
+		h.log.Info("Sleeping...")
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(10 * time.Second):
+		}
 	}
-
-	return nil
 }
 
-func (h *Horizon) SubmitTransaction(txeBase64 string) (response SubmitTransactionResponse, err error) {
+// SubmitTransaction submits transaction to horizon. Submission is not
+// retried, the caller must check if the transaction was included in a ledger
+// before submitting it again when ErrTimeout or ErrServerError is returned.
+func (h *Horizon) SubmitTransaction(ctx context.Context, txeBase64 string) (response SubmitTransactionResponse, err error) {
+	response.ServerUrl = h.ServerUrl
+
 	v := url.Values{}
 	v.Set("tx", txeBase64)
-	// TODO add request timeout
-	resp, err := http.PostForm(h.ServerUrl+"/transactions", v)
+
+	req, err := http.NewRequest("POST", h.ServerUrl+"/transactions", strings.NewReader(v.Encode()))
 	if err != nil {
 		return
 	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
+	requestCtx, cancel := context.WithTimeout(ctx, h.SubmitTimeout)
+	defer cancel()
+
+	body, statusCode, err := h.do(requestCtx, req)
 	if err != nil {
 		return
 	}
 
+	// Failed transactions are returned with 400 Bad Request
+	if statusCode != http.StatusBadRequest {
+		err = statusCodeError(statusCode)
+		if err != nil {
+			return
+		}
+	}
+
 	err = json.Unmarshal(body, &response)
 	if err != nil {
 		h.log.WithFields(logrus.Fields{
@@ -174,7 +248,7 @@ func (h *Horizon) SubmitTransaction(txeBase64 string) (response SubmitTransactio
 		h.log.WithFields(logrus.Fields{
 			"ledger": response.Ledger,
 		}).Info("Success response from horizon")
-	} else {
+	} else if response.Extras != nil {
 		h.log.WithFields(logrus.Fields{
 			"envelope": response.Extras.EnvelopeXdr,
 			"result":   response.Extras.ResultXdr,
@@ -183,83 +257,113 @@ func (h *Horizon) SubmitTransaction(txeBase64 string) (response SubmitTransactio
 
 	// Decode errors
 	if response.Ledger == nil && response.Extras != nil {
-		var txResult xdr.TransactionResult
-		txResult, err = unmarshalTransactionResult(response.Extras.ResultXdr)
-
+		response.Errors, err = DecodeTransactionResult(response.Extras.ResultXdr)
 		if err != nil {
 			h.log.Info("Cannot decode transaction result")
 			return
 		}
+	}
+
+	return
+}
+
+// get sends GET request and unmarshals JSON response to response param.
+// Requests that failed with a retryable error are retried with exponential backoff.
+func (h *Horizon) get(ctx context.Context, url string, response interface{}) (err error) {
+	backoff := h.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		err = h.getOnce(ctx, url, response)
+		if err == nil || !isRetryable(err) || attempt >= h.MaxRetries {
+			return
+		}
 
-		transactionResult := txResult.Result.Code
-		operationsResults := *txResult.Result.Results
-		var transactionErrorCode string
-		var operationErrorCode string
-
-		if transactionResult != xdr.TransactionResultCodeTxSuccess {
-			switch transactionResult {
-			case xdr.TransactionResultCodeTxFailed:
-				transactionErrorCode = "transaction_failed"
-			case xdr.TransactionResultCodeTxBadSeq:
-				transactionErrorCode = "transaction_bad_seq"
-			default:
-				transactionErrorCode = "unknown"
-			}
+		h.log.WithFields(logrus.Fields{
+			"url": url,
+			"err": err,
+		}).Warn("Retrying request")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
 		}
+		backoff *= 2
+	}
+}
+
+func (h *Horizon) getOnce(ctx context.Context, url string, response interface{}) (err error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return
+	}
+
+	requestCtx, cancel := context.WithTimeout(ctx, h.Timeout)
+	defer cancel()
 
-		if operationsResults != nil {
-			if operationsResults[0].Tr.AllowTrustResult != nil {
-				switch operationsResults[0].Tr.AllowTrustResult.Code {
-				case xdr.AllowTrustResultCodeAllowTrustMalformed:
-					operationErrorCode = "allow_trust_malformed"
-				case xdr.AllowTrustResultCodeAllowTrustNoTrustLine:
-					operationErrorCode = "allow_trust_not_trustline"
-				case xdr.AllowTrustResultCodeAllowTrustTrustNotRequired:
-					operationErrorCode = "allow_trust_trust_not_required"
-				case xdr.AllowTrustResultCodeAllowTrustCantRevoke:
-					operationErrorCode = "allow_trust_trust_cant_revoke"
-				default:
-					operationErrorCode = "unknown"
-				}
-			} else if operationsResults[0].Tr.PaymentResult != nil {
-				switch operationsResults[0].Tr.PaymentResult.Code {
-				case xdr.PaymentResultCodePaymentMalformed:
-					operationErrorCode = "payment_malformed"
-				case xdr.PaymentResultCodePaymentUnderfunded:
-					operationErrorCode = "payment_underfunded"
-				case xdr.PaymentResultCodePaymentSrcNoTrust:
-					operationErrorCode = "payment_src_no_trust"
-				case xdr.PaymentResultCodePaymentSrcNotAuthorized:
-					operationErrorCode = "payment_src_not_authorized"
-				case xdr.PaymentResultCodePaymentNoDestination:
-					operationErrorCode = "payment_no_destination"
-				case xdr.PaymentResultCodePaymentNoTrust:
-					operationErrorCode = "payment_no_trust"
-				case xdr.PaymentResultCodePaymentNotAuthorized:
-					operationErrorCode = "payment_not_authorized"
-				case xdr.PaymentResultCodePaymentLineFull:
-					operationErrorCode = "payment_line_full"
-				case xdr.PaymentResultCodePaymentNoIssuer:
-					operationErrorCode = "payment_no_issuer"
-				default:
-					operationErrorCode = "unknown"
-				}
-			}
+	body, statusCode, err := h.do(requestCtx, req)
+	if err != nil {
+		return
+	}
+
+	err = statusCodeError(statusCode)
+	if err != nil {
+		return
+	}
+
+	if statusCode != http.StatusOK {
+		err = fmt.Errorf("StatusCode indicates error: %s", body)
+		return
+	}
+
+	return json.Unmarshal(body, response)
+}
+
+// do sends request and reads response body. Returns ErrTimeout when
+// ctx deadline is exceeded and ErrUnavailable when connection cannot be made.
+func (h *Horizon) do(ctx context.Context, req *http.Request) (body []byte, statusCode int, err error) {
+	resp, err := h.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		switch {
+		case ctx.Err() == context.DeadlineExceeded:
+			err = ErrTimeout
+		case ctx.Err() == nil && isConnectionError(err):
+			err = ErrUnavailable
 		}
+		return
+	}
+	defer resp.Body.Close()
 
-		errors := &SubmitTransactionResponseError{
-			TransactionErrorCode: transactionErrorCode,
-			OperationErrorCode:   operationErrorCode,
+	body, err = ioutil.ReadAll(resp.Body)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			err = ErrTimeout
 		}
-		response.Errors = errors
+		return
 	}
 
+	statusCode = resp.StatusCode
 	return
 }
 
-func unmarshalTransactionResult(transactionResult string) (txResult xdr.TransactionResult, err error) {
-	reader := strings.NewReader(transactionResult)
-	b64r := base64.NewDecoder(base64.StdEncoding, reader)
-	_, err = xdr.Unmarshal(b64r, &txResult)
-	return
+// isConnectionError returns true when err was returned before the request
+// was sent to the server (ex. connection refused or DNS lookup failure).
+func isConnectionError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return opErr.Op == "dial"
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
+
+func statusCodeError(statusCode int) error {
+	switch {
+	case statusCode == http.StatusNotFound:
+		return ErrNotFound
+	case statusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case statusCode >= 500:
+		return ErrServerError
+	}
+	return nil
 }
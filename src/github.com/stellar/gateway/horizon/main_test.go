@@ -0,0 +1,227 @@
+package horizon
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHorizon(t *testing.T) {
+	var requests int
+	var respond func(w http.ResponseWriter, r *http.Request)
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		respond(w, r)
+	}))
+	defer testServer.Close()
+
+	Convey("Given horizon client", t, func() {
+		requests = 0
+		horizon := New(testServer.URL)
+		horizon.Timeout = 100 * time.Millisecond
+		horizon.RetryBackoff = time.Millisecond
+		horizon.MaxRetries = 2
+
+		Convey("When account exists", func() {
+			respond = func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/accounts/GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR", r.URL.Path)
+				w.Write([]byte(`{"id": "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR", "sequence": "100"}`))
+			}
+
+			Convey("it should return account", func() {
+				account, err := horizon.LoadAccount(context.Background(), "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR")
+				assert.Nil(t, err)
+				assert.Equal(t, "100", account.SequenceNumber)
+			})
+		})
+
+		Convey("When loading payment memo", func() {
+			respond = func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/transactions/588e1987755d2d97225e34636fc3971da8ffa0b8be86eaf64899fd2ce36bcd29", r.URL.Path)
+				w.Write([]byte(`{"memo_type": "text", "memo": "test"}`))
+			}
+
+			Convey("it should load transaction from this server", func() {
+				var payment PaymentResponse
+				payment.Links.Transaction.Href = "https://other-horizon.example.com/transactions/588e1987755d2d97225e34636fc3971da8ffa0b8be86eaf64899fd2ce36bcd29"
+				err := horizon.LoadMemo(context.Background(), &payment)
+				assert.Nil(t, err)
+				assert.Equal(t, "text", payment.Memo.Type)
+				assert.Equal(t, "test", payment.Memo.Value)
+			})
+		})
+
+		Convey("When loading payments", func() {
+			respond = func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/accounts/GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB/payments", r.URL.Path)
+				assert.Equal(t, "asc", r.URL.Query().Get("order"))
+				assert.Equal(t, "200", r.URL.Query().Get("limit"))
+				assert.Equal(t, "100", r.URL.Query().Get("cursor"))
+				w.Write([]byte(`{"_embedded": {"records": [{"id": "101", "paging_token": "101", "type": "payment"}]}}`))
+			}
+
+			Convey("it should return payments after cursor", func() {
+				payments, err := horizon.LoadPayments(context.Background(), "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB", "100", 200)
+				assert.Nil(t, err)
+				assert.Equal(t, 1, len(payments))
+				assert.Equal(t, "101", payments[0].PagingToken)
+			})
+		})
+
+		Convey("When streaming payments", func() {
+			respond = func(w http.ResponseWriter, r *http.Request) {
+				if requests > 1 {
+					assert.Equal(t, "101", r.Header.Get("Last-Event-ID"))
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				assert.Equal(t, "/accounts/GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB/payments", r.URL.Path)
+				assert.Equal(t, "100", r.URL.Query().Get("cursor"))
+				w.Header().Set("Content-Type", "text/event-stream")
+				w.Write([]byte("retry: 1\nevent: open\ndata: \"hello\"\n\nid: 101\ndata: {\"id\": \"101\", \"paging_token\": \"101\"}\n\n"))
+			}
+
+			Convey("it should call handler for every payment", func() {
+				var payments []PaymentResponse
+				cursor := "100"
+				err := horizon.StreamPayments(context.Background(), "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB", &cursor, func(payment PaymentResponse) error {
+					payments = append(payments, payment)
+					return nil
+				})
+				assert.Nil(t, err)
+				assert.Equal(t, 2, requests)
+				assert.Equal(t, 1, len(payments))
+				assert.Equal(t, "101", payments[0].PagingToken)
+			})
+		})
+
+		Convey("When account does not exist", func() {
+			respond = func(w http.ResponseWriter, r *http.Request) {
+				http.NotFound(w, r)
+			}
+
+			Convey("it should return ErrNotFound without retrying", func() {
+				_, err := horizon.LoadAccount(context.Background(), "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR")
+				assert.Equal(t, ErrNotFound, err)
+				assert.Equal(t, 1, requests)
+			})
+		})
+
+		Convey("When horizon returns server error once", func() {
+			respond = func(w http.ResponseWriter, r *http.Request) {
+				if requests == 1 {
+					http.Error(w, "error", http.StatusInternalServerError)
+					return
+				}
+				w.Write([]byte(`{"id": "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR", "sequence": "100"}`))
+			}
+
+			Convey("it should retry the request", func() {
+				account, err := horizon.LoadAccount(context.Background(), "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR")
+				assert.Nil(t, err)
+				assert.Equal(t, "100", account.SequenceNumber)
+				assert.Equal(t, 2, requests)
+			})
+		})
+
+		Convey("When horizon is rate limiting", func() {
+			respond = func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "slow down", http.StatusTooManyRequests)
+			}
+
+			Convey("it should return ErrRateLimited when retries are exhausted", func() {
+				_, err := horizon.LoadAccount(context.Background(), "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR")
+				assert.Equal(t, ErrRateLimited, err)
+				assert.Equal(t, 3, requests)
+			})
+		})
+
+		Convey("When horizon does not respond in time", func() {
+			respond = func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(200 * time.Millisecond)
+			}
+
+			Convey("it should return ErrTimeout", func() {
+				horizon.MaxRetries = 0
+				_, err := horizon.LoadTransaction(context.Background(), "588e1987755d2d97225e34636fc3971da8ffa0b8be86eaf64899fd2ce36bcd29")
+				assert.Equal(t, ErrTimeout, err)
+			})
+		})
+
+		Convey("When context is cancelled", func() {
+			respond = func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "error", http.StatusServiceUnavailable)
+			}
+
+			Convey("it should stop retrying", func() {
+				horizon.RetryBackoff = time.Second
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(50*time.Millisecond, cancel)
+
+				_, err := horizon.LoadAccount(ctx, "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR")
+				assert.Equal(t, context.Canceled, err)
+				assert.Equal(t, 1, requests)
+			})
+		})
+
+		Convey("When transaction fails", func() {
+			respond = func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "POST", r.Method)
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"extras": {"envelope_xdr": "envelope", "result_xdr": "AAAAAAAAAAD////7AAAAAA=="}}`))
+			}
+
+			Convey("it should decode errors", func() {
+				response, err := horizon.SubmitTransaction(context.Background(), "envelope")
+				assert.Nil(t, err)
+				assert.Equal(t, "transaction_bad_seq", response.Errors.TransactionErrorCode)
+				assert.Equal(t, 1, requests)
+			})
+		})
+
+		Convey("When submission returns server error", func() {
+			respond = func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "error", http.StatusGatewayTimeout)
+			}
+
+			Convey("it should not retry it", func() {
+				_, err := horizon.SubmitTransaction(context.Background(), "envelope")
+				assert.Equal(t, ErrServerError, err)
+				assert.Equal(t, 1, requests)
+			})
+		})
+
+		Convey("When streaming operations", func() {
+			respond = func(w http.ResponseWriter, r *http.Request) {
+				if requests > 1 {
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				assert.Equal(t, "/operations", r.URL.Path)
+				assert.Equal(t, "now", r.URL.Query().Get("cursor"))
+				w.Header().Set("Content-Type", "text/event-stream")
+				w.Write([]byte("retry: 1\nid: 201\ndata: {\"id\": \"201\", \"paging_token\": \"201\", \"type\": \"change_trust\", \"asset_code\": \"USD\", \"trustor\": \"GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB\"}\n\n"))
+			}
+
+			Convey("it should call handler for every operation", func() {
+				var operations []OperationResponse
+				cursor := "now"
+				err := horizon.StreamOperations(context.Background(), &cursor, func(operation OperationResponse) error {
+					operations = append(operations, operation)
+					return nil
+				})
+				assert.Nil(t, err)
+				if assert.Equal(t, 1, len(operations)) {
+					assert.Equal(t, "change_trust", operations[0].Type)
+					assert.Equal(t, "USD", operations[0].AssetCode)
+					assert.Equal(t, "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB", operations[0].Trustor)
+				}
+			})
+		})
+	})
+}
@@ -0,0 +1,24 @@
+package horizon
+
+// OperationResponse is an operation returned by /operations endpoint. Only
+// fields of operations used by the gateway server are decoded.
+type OperationResponse struct {
+	Id              string `json:"id"`
+	Type            string `json:"type"`
+	PagingToken     string `json:"paging_token"`
+	SourceAccount   string `json:"source_account"`
+	TransactionHash string `json:"transaction_hash"`
+
+	// change_trust/allow_trust fields
+	AssetType   string `json:"asset_type"`
+	AssetCode   string `json:"asset_code"`
+	AssetIssuer string `json:"asset_issuer"`
+	Trustor     string `json:"trustor"`
+	Trustee     string `json:"trustee"`
+
+	// change_trust fields
+	Limit string `json:"limit"`
+
+	// allow_trust fields
+	Authorize bool `json:"authorize"`
+}
@@ -1,9 +1,14 @@
 package horizon
 
+import (
+	"strings"
+)
+
 type PaymentResponse struct {
-	Id          string `json:"id"`
-	Type        string `json:"type"`
-	PagingToken string `json:"paging_token"`
+	Id              string `json:"id"`
+	Type            string `json:"type"`
+	PagingToken     string `json:"paging_token"`
+	TransactionHash string `json:"transaction_hash"`
 
 	Links struct {
 		Transaction struct {
@@ -19,9 +24,40 @@ type PaymentResponse struct {
 	AssetIssuer string `json:"asset_issuer"`
 	Amount      string `json:"amount"`
 
+	// path_payment fields (asset and amount fields above are the destination
+	// asset and amount)
+	SourceAmount      string `json:"source_amount"`
+	SourceAssetType   string `json:"source_asset_type"`
+	SourceAssetCode   string `json:"source_asset_code"`
+	SourceAssetIssuer string `json:"source_asset_issuer"`
+
+	// create_account fields
+	Funder          string `json:"funder"`
+	Account         string `json:"account"`
+	StartingBalance string `json:"starting_balance"`
+
 	// transaction fields
 	Memo struct {
 		Type  string `json:"memo_type"`
 		Value string `json:"memo"`
 	}
 }
+
+// GetTransactionHash returns hash of the transaction the payment belongs to.
+// Older horizon versions do not send transaction_hash so it's taken from
+// the transaction link then.
+func (p PaymentResponse) GetTransactionHash() string {
+	if p.TransactionHash != "" {
+		return p.TransactionHash
+	}
+	href := p.Links.Transaction.Href
+	return href[strings.LastIndex(href, "/")+1:]
+}
+
+// PaymentsPageResponse is a page of payments returned by
+// /accounts/{id}/payments endpoint.
+type PaymentsPageResponse struct {
+	Embedded struct {
+		Records []PaymentResponse `json:"records"`
+	} `json:"_embedded"`
+}
@@ -0,0 +1,228 @@
+package horizon
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// Pool sends requests to multiple horizon servers. Requests are sent to
+// healthy servers first (in the order servers were configured). When a server
+// does not respond or returns a server error it's marked as unhealthy and the
+// request is sent to the next server. Unhealthy servers are probed every
+// HealthCheckInterval and used again as soon as they respond.
+type Pool struct {
+	Servers []*Horizon
+	// MaxRetries is a number of times failed request is retried when
+	// all servers failed
+	MaxRetries int
+	// RetryBackoff is a delay before the first retry. It's doubled before
+	// every next retry.
+	RetryBackoff        time.Duration
+	HealthCheckInterval time.Duration
+	unhealthy           map[*Horizon]bool
+	mutex               sync.Mutex
+	log                 *logrus.Entry
+}
+
+func NewPool(serverUrls []string) (pool *Pool) {
+	pool = &Pool{
+		MaxRetries:          3,
+		RetryBackoff:        500 * time.Millisecond,
+		HealthCheckInterval: 30 * time.Second,
+		unhealthy:           make(map[*Horizon]bool),
+		log: logrus.WithFields(logrus.Fields{
+			"service": "HorizonPool",
+		}),
+	}
+	for _, serverUrl := range serverUrls {
+		server := New(serverUrl)
+		// Failed requests are retried by the pool
+		server.MaxRetries = 0
+		pool.Servers = append(pool.Servers, &server)
+	}
+	return
+}
+
+// StartHealthChecks starts probing servers every HealthCheckInterval.
+func (p *Pool) StartHealthChecks() {
+	go func() {
+		for {
+			p.CheckHealth(context.Background())
+			time.Sleep(p.HealthCheckInterval)
+		}
+	}()
+}
+
+// CheckHealth probes all servers and updates their health status.
+func (p *Pool) CheckHealth(ctx context.Context) {
+	for _, server := range p.Servers {
+		err := server.Ping(ctx)
+		if err != nil {
+			p.log.WithFields(logrus.Fields{
+				"horizon": server.ServerUrl,
+				"err":     err,
+			}).Warn("Horizon server health check failed")
+		}
+		p.setHealthy(server, err == nil)
+	}
+}
+
+// IsHealthy returns true if server with a given URL is healthy.
+func (p *Pool) IsHealthy(serverUrl string) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for _, server := range p.Servers {
+		if server.ServerUrl == serverUrl {
+			return !p.unhealthy[server]
+		}
+	}
+	return false
+}
+
+func (p *Pool) LoadAccount(ctx context.Context, accountId string) (response AccountResponse, err error) {
+	err = p.call(ctx, isRetryable, func(server *Horizon) (err error) {
+		response, err = server.LoadAccount(ctx, accountId)
+		return
+	})
+	return
+}
+
+func (p *Pool) LoadMemo(ctx context.Context, payment *PaymentResponse) (err error) {
+	return p.call(ctx, isRetryable, func(server *Horizon) error {
+		return server.LoadMemo(ctx, payment)
+	})
+}
+
+func (p *Pool) LoadTransaction(ctx context.Context, hash string) (response TransactionResponse, err error) {
+	err = p.call(ctx, isRetryable, func(server *Horizon) (err error) {
+		response, err = server.LoadTransaction(ctx, hash)
+		return
+	})
+	return
+}
+
+func (p *Pool) LoadPayments(ctx context.Context, accountId string, cursor string, limit int) (payments []PaymentResponse, err error) {
+	err = p.call(ctx, isRetryable, func(server *Horizon) (err error) {
+		payments, err = server.LoadPayments(ctx, accountId, cursor, limit)
+		return
+	})
+	return
+}
+
+// StreamPayments streams payments from the first healthy server. When the
+// stream fails the server is marked as unhealthy so the next call (with the
+// cursor of the last processed payment) uses another server.
+func (p *Pool) StreamPayments(ctx context.Context, accountId string, cursor *string, onPaymentHandler PaymentHandler) (err error) {
+	server := p.servers()[0]
+	p.log.WithFields(logrus.Fields{
+		"horizon": server.ServerUrl,
+	}).Info("Streaming payments")
+
+	err = server.StreamPayments(ctx, accountId, cursor, onPaymentHandler)
+	if err != nil && ctx.Err() == nil {
+		p.setHealthy(server, false)
+	}
+	return
+}
+
+// StreamOperations streams operations from the first healthy server. Fails
+// over the same way as StreamPayments.
+func (p *Pool) StreamOperations(ctx context.Context, cursor *string, onOperationHandler OperationHandler) (err error) {
+	server := p.servers()[0]
+	p.log.WithFields(logrus.Fields{
+		"horizon": server.ServerUrl,
+	}).Info("Streaming operations")
+
+	err = server.StreamOperations(ctx, cursor, onOperationHandler)
+	if err != nil && ctx.Err() == nil {
+		p.setHealthy(server, false)
+	}
+	return
+}
+
+// SubmitTransaction submits transaction to the first healthy server. It's
+// sent to the next server only when the previous one certainly did not
+// process it (connection failed or rate limit exceeded). Timeouts and server
+// errors are returned to the caller because the transaction could have been
+// included in a ledger.
+func (p *Pool) SubmitTransaction(ctx context.Context, txeBase64 string) (response SubmitTransactionResponse, err error) {
+	err = p.call(ctx, isNotProcessed, func(server *Horizon) (err error) {
+		response, err = server.SubmitTransaction(ctx, txeBase64)
+		if err == ErrTimeout || err == ErrServerError {
+			p.setHealthy(server, false)
+		}
+		return
+	})
+	return
+}
+
+// call calls fn with servers (healthy first) until it succeeds or returns
+// an error for which shouldFailover returns false. When all servers failed
+// the whole round is retried with exponential backoff.
+func (p *Pool) call(ctx context.Context, shouldFailover func(error) bool, fn func(server *Horizon) error) (err error) {
+	backoff := p.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		for _, server := range p.servers() {
+			err = fn(server)
+			if err == nil || !shouldFailover(err) {
+				if err == nil {
+					p.setHealthy(server, true)
+				}
+				return
+			}
+
+			p.log.WithFields(logrus.Fields{
+				"horizon": server.ServerUrl,
+				"err":     err,
+			}).Warn("Horizon server failed, trying next one")
+			p.setHealthy(server, false)
+		}
+
+		if attempt >= p.MaxRetries {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// servers returns healthy servers followed by unhealthy ones.
+func (p *Pool) servers() (servers []*Horizon) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	var unhealthy []*Horizon
+	for _, server := range p.Servers {
+		if p.unhealthy[server] {
+			unhealthy = append(unhealthy, server)
+		} else {
+			servers = append(servers, server)
+		}
+	}
+	return append(servers, unhealthy...)
+}
+
+func (p *Pool) setHealthy(server *Horizon, healthy bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.unhealthy[server] == !healthy {
+		return
+	}
+	p.unhealthy[server] = !healthy
+
+	log := p.log.WithFields(logrus.Fields{"horizon": server.ServerUrl})
+	if healthy {
+		log.Info("Horizon server is healthy")
+	} else {
+		log.Warn("Horizon server marked as unhealthy")
+	}
+}
@@ -0,0 +1,112 @@
+package horizon
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPool(t *testing.T) {
+	var requests int
+	var respond func(w http.ResponseWriter, r *http.Request)
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		respond(w, r)
+	}))
+	defer testServer.Close()
+
+	// Closed server refuses connections
+	downServer := httptest.NewServer(http.NotFoundHandler())
+	downServer.Close()
+
+	Convey("Given horizon pool", t, func() {
+		requests = 0
+		pool := NewPool([]string{downServer.URL, testServer.URL})
+		pool.RetryBackoff = time.Millisecond
+
+		Convey("When the first server is down", func() {
+			respond = func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"id": "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR", "sequence": "100"}`))
+			}
+
+			Convey("it should load account from the next server", func() {
+				account, err := pool.LoadAccount(context.Background(), "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR")
+				assert.Nil(t, err)
+				assert.Equal(t, "100", account.SequenceNumber)
+				assert.False(t, pool.IsHealthy(downServer.URL))
+				assert.True(t, pool.IsHealthy(testServer.URL))
+
+				Convey("and send next requests to healthy server first", func() {
+					assert.Equal(t, testServer.URL, pool.servers()[0].ServerUrl)
+				})
+			})
+
+			Convey("it should submit transaction to the next server", func() {
+				respond = func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte(`{"ledger": 100}`))
+				}
+
+				response, err := pool.SubmitTransaction(context.Background(), "envelope")
+				assert.Nil(t, err)
+				assert.Equal(t, uint64(100), *response.Ledger)
+				assert.Equal(t, testServer.URL, response.ServerUrl)
+			})
+		})
+
+		Convey("When server returns error during submission", func() {
+			pool = NewPool([]string{testServer.URL, downServer.URL})
+			respond = func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "error", http.StatusGatewayTimeout)
+			}
+
+			Convey("it should not submit transaction to another server", func() {
+				response, err := pool.SubmitTransaction(context.Background(), "envelope")
+				assert.Equal(t, ErrServerError, err)
+				assert.Equal(t, testServer.URL, response.ServerUrl)
+				assert.Equal(t, 1, requests)
+				assert.False(t, pool.IsHealthy(testServer.URL))
+			})
+		})
+
+		Convey("When resource does not exist", func() {
+			respond = func(w http.ResponseWriter, r *http.Request) {
+				http.NotFound(w, r)
+			}
+
+			Convey("it should return ErrNotFound", func() {
+				_, err := pool.LoadTransaction(context.Background(), "588e1987755d2d97225e34636fc3971da8ffa0b8be86eaf64899fd2ce36bcd29")
+				assert.Equal(t, ErrNotFound, err)
+				assert.Equal(t, 1, requests)
+			})
+		})
+
+		Convey("When all servers are down", func() {
+			pool = NewPool([]string{downServer.URL})
+			pool.RetryBackoff = time.Millisecond
+			pool.MaxRetries = 1
+
+			Convey("it should return ErrUnavailable", func() {
+				_, err := pool.LoadAccount(context.Background(), "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR")
+				assert.Equal(t, ErrUnavailable, err)
+			})
+		})
+
+		Convey("When unhealthy server recovers", func() {
+			respond = func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{}`))
+			}
+			pool.setHealthy(pool.Servers[1], false)
+
+			Convey("health check should mark it as healthy", func() {
+				pool.CheckHealth(context.Background())
+				assert.True(t, pool.IsHealthy(testServer.URL))
+				assert.False(t, pool.IsHealthy(downServer.URL))
+			})
+		})
+	})
+}
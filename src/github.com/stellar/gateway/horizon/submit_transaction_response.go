@@ -1,14 +1,23 @@
 package horizon
 
 type SubmitTransactionResponse struct {
+	Hash   string                           `json:"hash"`
 	Ledger *uint64                          `json:"ledger"`
 	Errors *SubmitTransactionResponseError  `json:"errors"`
 	Extras *SubmitTransactionResponseExtras `json:"extras"`
+	// ServerUrl is an URL of horizon server that handled the submission
+	ServerUrl string `json:"-"`
+	// SentTransactionId is an id of SentTransaction saved by
+	// TransactionSubmitter (also when submission returned an error)
+	SentTransactionId *int64 `json:"-"`
 }
 
 type SubmitTransactionResponseError struct {
 	TransactionErrorCode string `json:"transaction_error"`
 	OperationErrorCode   string `json:"operation_error"`
+	// OperationErrorCodes contains error codes of all operations in
+	// transaction (empty string for operations that succeeded)
+	OperationErrorCodes []string `json:"operation_errors,omitempty"`
 }
 
 type SubmitTransactionResponseExtras struct {
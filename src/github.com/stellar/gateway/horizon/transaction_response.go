@@ -0,0 +1,8 @@
+package horizon
+
+type TransactionResponse struct {
+	Hash        string  `json:"hash"`
+	Ledger      *uint64 `json:"ledger"`
+	EnvelopeXdr string  `json:"envelope_xdr"`
+	ResultXdr   string  `json:"result_xdr"`
+}
@@ -0,0 +1,232 @@
+package horizon
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/stellar/go-stellar-base/xdr"
+)
+
+var transactionResultCodes = map[xdr.TransactionResultCode]string{
+	xdr.TransactionResultCodeTxFailed:              "transaction_failed",
+	xdr.TransactionResultCodeTxTooEarly:            "transaction_too_early",
+	xdr.TransactionResultCodeTxTooLate:             "transaction_too_late",
+	xdr.TransactionResultCodeTxMissingOperation:    "transaction_missing_operation",
+	xdr.TransactionResultCodeTxBadSeq:              "transaction_bad_seq",
+	xdr.TransactionResultCodeTxBadAuth:             "transaction_bad_auth",
+	xdr.TransactionResultCodeTxInsufficientBalance: "transaction_insufficient_balance",
+	xdr.TransactionResultCodeTxNoAccount:           "transaction_no_account",
+	xdr.TransactionResultCodeTxInsufficientFee:     "transaction_insufficient_fee",
+	xdr.TransactionResultCodeTxBadAuthExtra:        "transaction_bad_auth_extra",
+	xdr.TransactionResultCodeTxInternalError:       "transaction_internal_error",
+}
+
+var operationResultCodes = map[xdr.OperationResultCode]string{
+	xdr.OperationResultCodeOpBadAuth:   "operation_bad_auth",
+	xdr.OperationResultCodeOpNoAccount: "operation_no_account",
+}
+
+var createAccountResultCodes = map[xdr.CreateAccountResultCode]string{
+	xdr.CreateAccountResultCodeCreateAccountMalformed:    "create_account_malformed",
+	xdr.CreateAccountResultCodeCreateAccountUnderfunded:  "create_account_underfunded",
+	xdr.CreateAccountResultCodeCreateAccountLowReserve:   "create_account_low_reserve",
+	xdr.CreateAccountResultCodeCreateAccountAlreadyExist: "create_account_already_exist",
+}
+
+var paymentResultCodes = map[xdr.PaymentResultCode]string{
+	xdr.PaymentResultCodePaymentMalformed:        "payment_malformed",
+	xdr.PaymentResultCodePaymentUnderfunded:      "payment_underfunded",
+	xdr.PaymentResultCodePaymentSrcNoTrust:       "payment_src_no_trust",
+	xdr.PaymentResultCodePaymentSrcNotAuthorized: "payment_src_not_authorized",
+	xdr.PaymentResultCodePaymentNoDestination:    "payment_no_destination",
+	xdr.PaymentResultCodePaymentNoTrust:          "payment_no_trust",
+	xdr.PaymentResultCodePaymentNotAuthorized:    "payment_not_authorized",
+	xdr.PaymentResultCodePaymentLineFull:         "payment_line_full",
+	xdr.PaymentResultCodePaymentNoIssuer:         "payment_no_issuer",
+}
+
+var pathPaymentResultCodes = map[xdr.PathPaymentResultCode]string{
+	xdr.PathPaymentResultCodePathPaymentMalformed:        "path_payment_malformed",
+	xdr.PathPaymentResultCodePathPaymentUnderfunded:      "path_payment_underfunded",
+	xdr.PathPaymentResultCodePathPaymentSrcNoTrust:       "path_payment_src_no_trust",
+	xdr.PathPaymentResultCodePathPaymentSrcNotAuthorized: "path_payment_src_not_authorized",
+	xdr.PathPaymentResultCodePathPaymentNoDestination:    "path_payment_no_destination",
+	xdr.PathPaymentResultCodePathPaymentNoTrust:          "path_payment_no_trust",
+	xdr.PathPaymentResultCodePathPaymentNotAuthorized:    "path_payment_not_authorized",
+	xdr.PathPaymentResultCodePathPaymentLineFull:         "path_payment_line_full",
+	xdr.PathPaymentResultCodePathPaymentNoIssuer:         "path_payment_no_issuer",
+	xdr.PathPaymentResultCodePathPaymentTooFewOffers:     "path_payment_too_few_offers",
+	xdr.PathPaymentResultCodePathPaymentOfferCrossSelf:   "path_payment_offer_cross_self",
+	xdr.PathPaymentResultCodePathPaymentOverSendmax:      "path_payment_over_sendmax",
+}
+
+// manageOfferResultCodes are used by both manage_offer and create_passive_offer
+var manageOfferResultCodes = map[xdr.ManageOfferResultCode]string{
+	xdr.ManageOfferResultCodeManageOfferMalformed:         "manage_offer_malformed",
+	xdr.ManageOfferResultCodeManageOfferSellNoTrust:       "manage_offer_sell_no_trust",
+	xdr.ManageOfferResultCodeManageOfferBuyNoTrust:        "manage_offer_buy_no_trust",
+	xdr.ManageOfferResultCodeManageOfferSellNotAuthorized: "manage_offer_sell_not_authorized",
+	xdr.ManageOfferResultCodeManageOfferBuyNotAuthorized:  "manage_offer_buy_not_authorized",
+	xdr.ManageOfferResultCodeManageOfferLineFull:          "manage_offer_line_full",
+	xdr.ManageOfferResultCodeManageOfferUnderfunded:       "manage_offer_underfunded",
+	xdr.ManageOfferResultCodeManageOfferCrossSelf:         "manage_offer_cross_self",
+	xdr.ManageOfferResultCodeManageOfferSellNoIssuer:      "manage_offer_sell_no_issuer",
+	xdr.ManageOfferResultCodeManageOfferBuyNoIssuer:       "manage_offer_buy_no_issuer",
+	xdr.ManageOfferResultCodeManageOfferNotFound:          "manage_offer_not_found",
+	xdr.ManageOfferResultCodeManageOfferLowReserve:        "manage_offer_low_reserve",
+}
+
+var setOptionsResultCodes = map[xdr.SetOptionsResultCode]string{
+	xdr.SetOptionsResultCodeSetOptionsLowReserve:          "set_options_low_reserve",
+	xdr.SetOptionsResultCodeSetOptionsTooManySigners:      "set_options_too_many_signers",
+	xdr.SetOptionsResultCodeSetOptionsBadFlags:            "set_options_bad_flags",
+	xdr.SetOptionsResultCodeSetOptionsInvalidInflation:    "set_options_invalid_inflation",
+	xdr.SetOptionsResultCodeSetOptionsCantChange:          "set_options_cant_change",
+	xdr.SetOptionsResultCodeSetOptionsUnknownFlag:         "set_options_unknown_flag",
+	xdr.SetOptionsResultCodeSetOptionsThresholdOutOfRange: "set_options_threshold_out_of_range",
+	xdr.SetOptionsResultCodeSetOptionsBadSigner:           "set_options_bad_signer",
+	xdr.SetOptionsResultCodeSetOptionsInvalidHomeDomain:   "set_options_invalid_home_domain",
+}
+
+var changeTrustResultCodes = map[xdr.ChangeTrustResultCode]string{
+	xdr.ChangeTrustResultCodeChangeTrustMalformed:    "change_trust_malformed",
+	xdr.ChangeTrustResultCodeChangeTrustNoIssuer:     "change_trust_no_issuer",
+	xdr.ChangeTrustResultCodeChangeTrustInvalidLimit: "change_trust_invalid_limit",
+	xdr.ChangeTrustResultCodeChangeTrustLowReserve:   "change_trust_low_reserve",
+}
+
+// Names of allow_trust codes are kept for backwards compatibility
+var allowTrustResultCodes = map[xdr.AllowTrustResultCode]string{
+	xdr.AllowTrustResultCodeAllowTrustMalformed:        "allow_trust_malformed",
+	xdr.AllowTrustResultCodeAllowTrustNoTrustLine:      "allow_trust_not_trustline",
+	xdr.AllowTrustResultCodeAllowTrustTrustNotRequired: "allow_trust_trust_not_required",
+	xdr.AllowTrustResultCodeAllowTrustCantRevoke:       "allow_trust_trust_cant_revoke",
+}
+
+var accountMergeResultCodes = map[xdr.AccountMergeResultCode]string{
+	xdr.AccountMergeResultCodeAccountMergeMalformed:     "account_merge_malformed",
+	xdr.AccountMergeResultCodeAccountMergeNoAccount:     "account_merge_no_account",
+	xdr.AccountMergeResultCodeAccountMergeImmutableSet:  "account_merge_immutable_set",
+	xdr.AccountMergeResultCodeAccountMergeHasSubEntries: "account_merge_has_sub_entries",
+}
+
+var inflationResultCodes = map[xdr.InflationResultCode]string{
+	xdr.InflationResultCodeInflationNotTime: "inflation_not_time",
+}
+
+// DecodeTransactionResult decodes error codes from base64 encoded TransactionResult XDR.
+// OperationErrorCode is a code of the first failed operation while
+// OperationErrorCodes contains codes of all operations (empty string for
+// operations that succeeded).
+func DecodeTransactionResult(resultXdr string) (errors *SubmitTransactionResponseError, err error) {
+	txResult, err := unmarshalTransactionResult(resultXdr)
+	if err != nil {
+		return
+	}
+
+	errors = &SubmitTransactionResponseError{}
+
+	if txResult.Result.Code != xdr.TransactionResultCodeTxSuccess {
+		errors.TransactionErrorCode = resultCode(transactionResultCodes[txResult.Result.Code])
+	}
+
+	if txResult.Result.Results == nil {
+		return
+	}
+
+	for _, operationResult := range *txResult.Result.Results {
+		code := operationErrorCode(operationResult)
+		if code != "" && errors.OperationErrorCode == "" {
+			errors.OperationErrorCode = code
+		}
+		errors.OperationErrorCodes = append(errors.OperationErrorCodes, code)
+	}
+
+	if errors.OperationErrorCode == "" {
+		errors.OperationErrorCodes = nil
+	}
+	return
+}
+
+// operationErrorCode returns error code of operationResult or empty string
+// if operation succeeded.
+func operationErrorCode(operationResult xdr.OperationResult) string {
+	if operationResult.Code != xdr.OperationResultCodeOpInner {
+		return resultCode(operationResultCodes[operationResult.Code])
+	}
+
+	tr := operationResult.Tr
+	if tr == nil {
+		return "unknown"
+	}
+
+	switch {
+	case tr.CreateAccountResult != nil:
+		if tr.CreateAccountResult.Code == xdr.CreateAccountResultCodeCreateAccountSuccess {
+			return ""
+		}
+		return resultCode(createAccountResultCodes[tr.CreateAccountResult.Code])
+	case tr.PaymentResult != nil:
+		if tr.PaymentResult.Code == xdr.PaymentResultCodePaymentSuccess {
+			return ""
+		}
+		return resultCode(paymentResultCodes[tr.PaymentResult.Code])
+	case tr.PathPaymentResult != nil:
+		if tr.PathPaymentResult.Code == xdr.PathPaymentResultCodePathPaymentSuccess {
+			return ""
+		}
+		return resultCode(pathPaymentResultCodes[tr.PathPaymentResult.Code])
+	case tr.ManageOfferResult != nil:
+		if tr.ManageOfferResult.Code == xdr.ManageOfferResultCodeManageOfferSuccess {
+			return ""
+		}
+		return resultCode(manageOfferResultCodes[tr.ManageOfferResult.Code])
+	case tr.CreatePassiveOfferResult != nil:
+		if tr.CreatePassiveOfferResult.Code == xdr.ManageOfferResultCodeManageOfferSuccess {
+			return ""
+		}
+		return resultCode(manageOfferResultCodes[tr.CreatePassiveOfferResult.Code])
+	case tr.SetOptionsResult != nil:
+		if tr.SetOptionsResult.Code == xdr.SetOptionsResultCodeSetOptionsSuccess {
+			return ""
+		}
+		return resultCode(setOptionsResultCodes[tr.SetOptionsResult.Code])
+	case tr.ChangeTrustResult != nil:
+		if tr.ChangeTrustResult.Code == xdr.ChangeTrustResultCodeChangeTrustSuccess {
+			return ""
+		}
+		return resultCode(changeTrustResultCodes[tr.ChangeTrustResult.Code])
+	case tr.AllowTrustResult != nil:
+		if tr.AllowTrustResult.Code == xdr.AllowTrustResultCodeAllowTrustSuccess {
+			return ""
+		}
+		return resultCode(allowTrustResultCodes[tr.AllowTrustResult.Code])
+	case tr.AccountMergeResult != nil:
+		if tr.AccountMergeResult.Code == xdr.AccountMergeResultCodeAccountMergeSuccess {
+			return ""
+		}
+		return resultCode(accountMergeResultCodes[tr.AccountMergeResult.Code])
+	case tr.InflationResult != nil:
+		if tr.InflationResult.Code == xdr.InflationResultCodeInflationSuccess {
+			return ""
+		}
+		return resultCode(inflationResultCodes[tr.InflationResult.Code])
+	}
+	return "unknown"
+}
+
+// resultCode returns "unknown" for codes missing in the maps above
+// (ex. added in the newer protocol version).
+func resultCode(code string) string {
+	if code == "" {
+		return "unknown"
+	}
+	return code
+}
+
+func unmarshalTransactionResult(transactionResult string) (txResult xdr.TransactionResult, err error) {
+	reader := strings.NewReader(transactionResult)
+	b64r := base64.NewDecoder(base64.StdEncoding, reader)
+	_, err = xdr.Unmarshal(b64r, &txResult)
+	return
+}
@@ -0,0 +1,107 @@
+package horizon
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stellar/go-stellar-base/xdr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeTransactionResult(t *testing.T) {
+	encode := func(result xdr.TransactionResult) string {
+		resultXdr, err := xdr.MarshalBase64(result)
+		if err != nil {
+			panic(err)
+		}
+		return resultXdr
+	}
+
+	Convey("Given transaction result", t, func() {
+		Convey("When transaction failed before applying operations", func() {
+			resultXdr := encode(xdr.TransactionResult{
+				FeeCharged: 100,
+				Result: xdr.TransactionResultResult{
+					Code: xdr.TransactionResultCodeTxInsufficientFee,
+				},
+			})
+
+			Convey("it should decode transaction error", func() {
+				errors, err := DecodeTransactionResult(resultXdr)
+				assert.Nil(t, err)
+				assert.Equal(t, "transaction_insufficient_fee", errors.TransactionErrorCode)
+				assert.Equal(t, "", errors.OperationErrorCode)
+				assert.Nil(t, errors.OperationErrorCodes)
+			})
+		})
+
+		Convey("When one of the operations failed", func() {
+			results := []xdr.OperationResult{
+				{
+					Code: xdr.OperationResultCodeOpInner,
+					Tr: &xdr.OperationResultTr{
+						Type:          xdr.OperationTypePayment,
+						PaymentResult: &xdr.PaymentResult{Code: xdr.PaymentResultCodePaymentSuccess},
+					},
+				},
+				{
+					Code: xdr.OperationResultCodeOpInner,
+					Tr: &xdr.OperationResultTr{
+						Type:                xdr.OperationTypeCreateAccount,
+						CreateAccountResult: &xdr.CreateAccountResult{Code: xdr.CreateAccountResultCodeCreateAccountLowReserve},
+					},
+				},
+				{
+					Code: xdr.OperationResultCodeOpNoAccount,
+				},
+			}
+			resultXdr := encode(xdr.TransactionResult{
+				FeeCharged: 300,
+				Result: xdr.TransactionResultResult{
+					Code:    xdr.TransactionResultCodeTxFailed,
+					Results: &results,
+				},
+			})
+
+			Convey("it should decode errors of all operations", func() {
+				errors, err := DecodeTransactionResult(resultXdr)
+				assert.Nil(t, err)
+				assert.Equal(t, "transaction_failed", errors.TransactionErrorCode)
+				assert.Equal(t, "create_account_low_reserve", errors.OperationErrorCode)
+				assert.Equal(t, []string{"", "create_account_low_reserve", "operation_no_account"}, errors.OperationErrorCodes)
+			})
+		})
+
+		Convey("When allow_trust failed", func() {
+			results := []xdr.OperationResult{
+				{
+					Code: xdr.OperationResultCodeOpInner,
+					Tr: &xdr.OperationResultTr{
+						Type:             xdr.OperationTypeAllowTrust,
+						AllowTrustResult: &xdr.AllowTrustResult{Code: xdr.AllowTrustResultCodeAllowTrustNoTrustLine},
+					},
+				},
+			}
+			resultXdr := encode(xdr.TransactionResult{
+				FeeCharged: 100,
+				Result: xdr.TransactionResultResult{
+					Code:    xdr.TransactionResultCodeTxFailed,
+					Results: &results,
+				},
+			})
+
+			Convey("it should keep existing error code", func() {
+				errors, err := DecodeTransactionResult(resultXdr)
+				assert.Nil(t, err)
+				assert.Equal(t, "allow_trust_not_trustline", errors.OperationErrorCode)
+			})
+		})
+
+		Convey("When result is invalid", func() {
+			Convey("it should return error", func() {
+				_, err := DecodeTransactionResult("invalid")
+				assert.NotNil(t, err)
+			})
+		})
+	})
+}
@@ -1,27 +1,33 @@
 package listener
 
 import (
+	"context"
+	"encoding/hex"
 	"errors"
-	"io/ioutil"
-	"net/http"
 	"net/url"
 	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/stellar/gateway/config"
 	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/hooks"
 	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/submitter"
+	"github.com/stellar/go-stellar-base/amount"
+	b "github.com/stellar/go-stellar-base/build"
 	"github.com/stellar/go-stellar-base/keypair"
+	"github.com/stellar/go-stellar-base/xdr"
 )
 
 type PaymentListener struct {
-	config         *config.Config
-	entityManager  db.EntityManagerInterface
-	horizon        horizon.HorizonInterface
-	log            *logrus.Entry
-	repository     db.RepositoryInterface
-	issuingAccount keypair.KP
-	now            func() time.Time
+	config               *config.Config
+	entityManager        db.EntityManagerInterface
+	horizon              horizon.HorizonInterface
+	log                  *logrus.Entry
+	repository           db.RepositoryInterface
+	transactionSubmitter submitter.TransactionSubmitterInterface
+	issuingAccount       keypair.KP
+	now                  func() time.Time
 }
 
 func NewPaymentListener(
@@ -29,13 +35,15 @@ func NewPaymentListener(
 	entityManager db.EntityManagerInterface,
 	horizon horizon.HorizonInterface,
 	repository db.RepositoryInterface,
+	transactionSubmitter submitter.TransactionSubmitterInterface,
 	now func() time.Time,
 ) (pl PaymentListener, err error) {
 	pl.config = config
 	pl.entityManager = entityManager
 	pl.horizon = horizon
 	pl.repository = repository
-	pl.issuingAccount, err = keypair.Parse(*config.Accounts.IssuingSeed)
+	pl.transactionSubmitter = transactionSubmitter
+	pl.issuingAccount, err = keypair.Parse(config.Accounts.GetIssuingAccountId())
 	pl.now = now
 	pl.log = logrus.WithFields(logrus.Fields{
 		"service": "PaymentListener",
@@ -43,17 +51,35 @@ func NewPaymentListener(
 	return
 }
 
+// Listen starts streaming payments of all receiving accounts. Every account
+// is streamed in a separate goroutine starting from its own last cursor.
+// Accounts without receive hook are skipped.
 func (pl PaymentListener) Listen() (err error) {
-	accountId := *pl.config.Accounts.ReceivingAccountId
+	for _, account := range pl.config.ReceivingAccounts() {
+		if account.ReceiveHook == nil {
+			pl.log.WithFields(logrus.Fields{"accountId": account.AccountId}).Warning("No receive hook for receiving account. Skipping...")
+			continue
+		}
 
-	_, err = pl.horizon.LoadAccount(accountId)
+		err = pl.listen(account)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (pl PaymentListener) listen(account config.ReceivingAccount) (err error) {
+	log := pl.log.WithFields(logrus.Fields{"accountId": account.AccountId})
+
+	_, err = pl.horizon.LoadAccount(context.Background(), account.AccountId)
 	if err != nil {
 		return
 	}
 
-	cursor, err := pl.repository.GetLastCursorValue()
+	cursor, err := pl.lastCursor(account.AccountId)
 	if err != nil {
-		pl.log.Error("Could not load last cursor from the DB")
+		log.Error("Could not load last cursor from the DB")
 		return
 	}
 
@@ -62,124 +88,437 @@ func (pl PaymentListener) Listen() (err error) {
 		cursorValue = *cursor
 	}
 
-	pl.log.WithFields(logrus.Fields{
-		"accountId": accountId,
-		"cursor":    cursorValue,
+	log.WithFields(logrus.Fields{
+		"cursor": cursorValue,
 	}).Info("Started listening for new payments")
 
+	onPayment := func(payment horizon.PaymentResponse) error {
+		return pl.onStreamedPayment(account, payment)
+	}
+
 	go func() {
 		for {
-			err = pl.horizon.StreamPayments(
-				accountId,
+			err := pl.horizon.StreamPayments(
+				context.Background(),
+				account.AccountId,
 				cursor,
-				pl.onPayment,
+				onPayment,
 			)
 			if err != nil {
-				pl.log.Error("Error while streaming: ", err)
-				pl.log.Info("Sleeping...")
+				log.Error("Error while streaming: ", err)
+				log.Info("Sleeping...")
 				time.Sleep(10 * time.Second)
 			}
-			pl.log.Info("Streaming connection closed. Restarting...")
+			log.Info("Streaming connection closed. Restarting...")
+
+			// Restart from the last persisted payment, the stream can be
+			// reconnected to another horizon server.
+			lastCursor, err := pl.lastCursor(account.AccountId)
+			if err != nil {
+				log.Error("Could not load last cursor from the DB: ", err)
+				continue
+			}
+			if lastCursor != nil {
+				cursor = lastCursor
+			}
 		}
 	}()
 
 	return
 }
 
-func (pl PaymentListener) onPayment(payment horizon.PaymentResponse) (err error) {
-	pl.log.WithFields(logrus.Fields{"id": payment.Id}).Info("New payment")
+// StreamName returns name of the StreamCursor of payments of a given account.
+func StreamName(accountId string) string {
+	return "payments:" + accountId
+}
+
+// streamCursor returns StreamCursor of the payments stream of a given account
+// set to cursor. It's not saved in the DB.
+func (pl PaymentListener) streamCursor(accountId, cursor string) (streamCursor *db.StreamCursor, err error) {
+	streamCursor, err = pl.repository.GetStreamCursor(StreamName(accountId))
+	if err != nil {
+		pl.log.Error("Error loading stream cursor from the DB")
+		return
+	}
+	if streamCursor == nil {
+		streamCursor = &db.StreamCursor{Name: StreamName(accountId)}
+	}
+
+	streamCursor.Cursor = cursor
+	streamCursor.UpdatedAt = pl.now()
+	return
+}
+
+func (pl PaymentListener) saveCursor(streamCursor *db.StreamCursor) (err error) {
+	err = pl.entityManager.Persist(streamCursor)
+	if err != nil {
+		pl.log.Error("Error saving stream cursor to the DB")
+	}
+	return
+}
+
+// lastCursor returns saved cursor of the payments stream of a given account.
+// Payments saved before multiple receiving accounts were supported do not
+// have account ID and stream cursors are not created for them by migrations
+// so the last of them is used for `accounts.receiving_account_id`.
+func (pl PaymentListener) lastCursor(accountId string) (cursor *string, err error) {
+	streamCursor, err := pl.repository.GetStreamCursor(StreamName(accountId))
+	if err != nil {
+		return
+	}
+	if streamCursor != nil {
+		return &streamCursor.Cursor, nil
+	}
+
+	if pl.config.Accounts.ReceivingAccountId != nil && *pl.config.Accounts.ReceivingAccountId == accountId {
+		cursor, err = pl.repository.GetLastCursorValue("")
+	}
+	return
+}
+
+// onStreamedPayment processes a payment from the payments stream. Stream
+// cursor is saved in the same DB transaction as the payment.
+func (pl PaymentListener) onStreamedPayment(account config.ReceivingAccount, payment horizon.PaymentResponse) (err error) {
+	streamCursor, err := pl.streamCursor(account.AccountId, payment.PagingToken)
+	if err != nil {
+		return
+	}
+	return pl.processPayment(account, payment, streamCursor)
+}
+
+// onPayment processes a payment without changing stream cursor (ex. when
+// the payment is replayed).
+func (pl PaymentListener) onPayment(account config.ReceivingAccount, payment horizon.PaymentResponse) (err error) {
+	return pl.processPayment(account, payment, nil)
+}
+
+// processPayment saves a payment and queues hook deliveries. Hook delivery
+// and streamCursor (when not nil) are saved together with the payment.
+func (pl PaymentListener) processPayment(
+	account config.ReceivingAccount,
+	payment horizon.PaymentResponse,
+	streamCursor *db.StreamCursor,
+) (err error) {
+	pl.log.WithFields(logrus.Fields{"id": payment.Id, "accountId": account.AccountId}).Info("New payment")
+
+	normalizePayment(&payment)
+
+	existingPayment, err := pl.repository.GetReceivedPaymentByOperationId(payment.Id)
+	if err != nil {
+		pl.log.Error("Error loading payment from the DB")
+		return err
+	}
 
 	dbPayment := db.ReceivedPayment{
-		OperationId: payment.Id,
-		ProcessedAt: pl.now(),
-		PagingToken: payment.PagingToken,
+		OperationId:     payment.Id,
+		AccountId:       account.AccountId,
+		ProcessedAt:     pl.now(),
+		PagingToken:     payment.PagingToken,
+		TransactionHash: payment.GetTransactionHash(),
+		From:            payment.From,
+		Amount:          payment.Amount,
+		AssetCode:       payment.AssetCode,
+		AssetIssuer:     payment.AssetIssuer,
+	}
+
+	if existingPayment != nil {
+		// Hooks have been already called for this payment
+		if existingPayment.Status == "Success" {
+			pl.log.WithFields(logrus.Fields{"id": payment.Id}).Info("Payment already processed")
+			return pl.skipPayment(streamCursor)
+		}
+		// Refund could have been sent so the payment is never processed again
+		if existingPayment.RefundStatus != nil {
+			pl.log.WithFields(logrus.Fields{"id": payment.Id}).Info("Payment already refunded")
+			return pl.skipPayment(streamCursor)
+		}
+		// Error hook has been already queued for this rejected payment
+		var deliveries []db.HookDelivery
+		deliveries, err = pl.repository.GetHookDeliveries(db.HookDeliveriesFilter{OperationId: payment.Id, Limit: 1})
+		if err != nil {
+			pl.log.Error("Error loading hook deliveries from the DB")
+			return err
+		}
+		if len(deliveries) > 0 {
+			pl.log.WithFields(logrus.Fields{"id": payment.Id}).Info("Payment hooks already queued")
+			return pl.skipPayment(streamCursor)
+		}
+		// Process again and update the existing row
+		dbPayment.Id = existingPayment.Id
 	}
 
 	savePayment := func(payment *db.ReceivedPayment) (err error) {
-		err = pl.entityManager.Persist(payment)
+		err = pl.savePayment(payment, streamCursor, nil)
+		if err != nil {
+			pl.log.Error("Error saving payment to the DB")
+		}
 		return
 	}
 
-	if payment.Type != "payment" {
+	if payment.Type != "payment" && payment.Type != "path_payment" && payment.Type != "create_account" {
 		dbPayment.Status = "Not a payment operation"
-		savePayment(&dbPayment)
-		return
+		return savePayment(&dbPayment)
 	}
 
-	if payment.To != *pl.config.Accounts.ReceivingAccountId {
+	if payment.To != account.AccountId {
 		dbPayment.Status = "Operation sent not received"
-		savePayment(&dbPayment)
-		return nil
+		return savePayment(&dbPayment)
 	}
 
-	if !pl.isAssetAllowed(payment.AssetCode, payment.AssetIssuer) {
+	if !pl.isAssetAllowed(account, payment) {
 		dbPayment.Status = "Asset not allowed"
-		savePayment(&dbPayment)
-		return nil
+		return pl.reject(account, payment, &dbPayment, streamCursor, hooks.ReasonAssetNotAllowed)
 	}
 
-	err = pl.horizon.LoadMemo(&payment)
+	err = pl.horizon.LoadMemo(context.Background(), &payment)
 	if err != nil {
 		pl.log.Error("Unable to load transaction memo")
 		return err
 	}
 
+	dbPayment.MemoType = payment.Memo.Type
+	dbPayment.Memo = payment.Memo.Value
+
 	if payment.Memo.Type == "" || payment.Memo.Value == "" {
 		dbPayment.Status = "Transaction does not have memo"
-		savePayment(&dbPayment)
-		return nil
+		return pl.reject(account, payment, &dbPayment, streamCursor, hooks.ReasonMemoMissing)
 	}
 
-	resp, err := http.PostForm(
-		*pl.config.Hooks.Receive,
+	// Hook is delivered by hooks.Deliverer so a failing hook does not
+	// block processing of next payments
+	delivery := hooks.NewDelivery(
+		"receive",
+		*account.ReceiveHook,
 		url.Values{
 			"id":         {payment.Id},
+			"type":       {payment.Type},
+			"account_id": {account.AccountId},
 			"from":       {payment.From},
 			"amount":     {payment.Amount},
 			"asset_code": {payment.AssetCode},
 			"memo_type":  {payment.Memo.Type},
 			"memo":       {payment.Memo.Value},
 		},
+		payment.Id,
+		pl.now(),
 	)
+	dbPayment.Status = "Success"
+	err = pl.savePayment(&dbPayment, streamCursor, delivery)
 	if err != nil {
-		pl.log.Error("Error sending request to receive hook")
+		pl.log.Error("Error saving payment to the DB")
 		return err
 	}
 
-	if resp.StatusCode != 200 {
-		defer resp.Body.Close()
-		body, err := ioutil.ReadAll(resp.Body)
+	return nil
+}
+
+// savePayment saves dbPayment, delivery and streamCursor (when not nil) in
+// a single DB transaction so the hook is not queued when the payment cannot
+// be saved.
+func (pl PaymentListener) savePayment(dbPayment *db.ReceivedPayment, streamCursor *db.StreamCursor, delivery *db.HookDelivery) error {
+	entities := []db.Entity{dbPayment}
+	if delivery != nil {
+		entities = append(entities, delivery)
+	}
+	if streamCursor != nil {
+		entities = append(entities, streamCursor)
+	}
+
+	if len(entities) == 1 {
+		return pl.entityManager.Persist(dbPayment)
+	}
+	return pl.entityManager.PersistAll(entities...)
+}
+
+// skipPayment saves streamCursor (when not nil) of a payment that does not
+// need to be processed.
+func (pl PaymentListener) skipPayment(streamCursor *db.StreamCursor) error {
+	if streamCursor == nil {
+		return nil
+	}
+	return pl.saveCursor(streamCursor)
+}
+
+// reject saves status of a rejected payment together with error hook
+// delivery. The payment is refunded when refunds are enabled for a given
+// reason.
+func (pl PaymentListener) reject(
+	account config.ReceivingAccount,
+	payment horizon.PaymentResponse,
+	dbPayment *db.ReceivedPayment,
+	streamCursor *db.StreamCursor,
+	reason string,
+) (err error) {
+	delivery := pl.errorDelivery(account, payment, reason)
+
+	if !pl.isRefunded(payment, reason) {
+		err = pl.savePayment(dbPayment, streamCursor, delivery)
 		if err != nil {
-			pl.log.Error("Error reading receive hook response")
-			return err
+			pl.log.Error("Error saving payment to the DB")
 		}
-
-		pl.log.WithFields(logrus.Fields{
-			"status": resp.StatusCode,
-			"body":   string(body),
-		}).Error("Error response from receive hook")
-		return errors.New("Error response from receive hook")
+		return
 	}
 
-	dbPayment.Status = "Success"
-	err = savePayment(&dbPayment)
+	// Saved before submitting so the payment is not refunded again when
+	// the refund result is unknown
+	refundStatus := "pending"
+	dbPayment.RefundStatus = &refundStatus
+	err = pl.savePayment(dbPayment, streamCursor, delivery)
 	if err != nil {
 		pl.log.Error("Error saving payment to the DB")
-		return err
+		return
 	}
 
-	return nil
+	pl.refund(account, payment, dbPayment)
+
+	err = pl.entityManager.Persist(dbPayment)
+	if err != nil {
+		pl.log.Error("Error saving refund status to the DB")
+	}
+	return
+}
+
+// isRefunded returns true when payment rejected for a given reason should
+// be refunded. create_account is never refunded as the starting balance is
+// needed to keep the account.
+func (pl PaymentListener) isRefunded(payment horizon.PaymentResponse, reason string) bool {
+	return pl.config.Refunds != nil &&
+		pl.config.Refunds.IsRefunded(reason) &&
+		payment.Type != "create_account"
+}
+
+// refund sends the received asset and amount (minus refunds.fee) back to the
+// sender with MEMO_RETURN containing hash of the payment transaction. Result
+// is saved in dbPayment RefundStatus, RefundTransactionHash and
+// RefundTransactionId.
+func (pl PaymentListener) refund(account config.ReceivingAccount, payment horizon.PaymentResponse, dbPayment *db.ReceivedPayment) {
+	log := pl.log.WithFields(logrus.Fields{"id": payment.Id})
+	refundStatus := "failed"
+	defer func() { dbPayment.RefundStatus = &refundStatus }()
+
+	refundAmount, err := pl.refundAmount(payment.Amount)
+	if err != nil {
+		log.WithFields(logrus.Fields{"err": err}).Error("Cannot refund payment")
+		return
+	}
+
+	var memo xdr.Hash
+	hash, err := hex.DecodeString(payment.GetTransactionHash())
+	if err != nil || len(hash) != len(memo) {
+		log.Error("Cannot refund payment: invalid transaction hash")
+		return
+	}
+	copy(memo[:], hash)
+
+	var amountMutator interface{}
+	if payment.AssetType == "native" {
+		amountMutator = b.NativeAmount{refundAmount}
+	} else {
+		amountMutator = b.CreditAmount{payment.AssetCode, payment.AssetIssuer, refundAmount}
+	}
+	operation := b.Payment(b.Destination{payment.From}, amountMutator)
+
+	response, pending, err := submitTransaction(pl.transactionSubmitter, account.AccountId, operation, b.MemoReturn{memo})
+	dbPayment.RefundTransactionId = response.SentTransactionId
+	if err != nil {
+		log.WithFields(logrus.Fields{"err": err}).Error("Error submitting refund")
+		if pending {
+			refundStatus = "pending"
+		}
+		return
+	}
+
+	if response.Ledger == nil {
+		log.WithFields(logrus.Fields{"errors": response.Errors}).Error("Refund transaction failed")
+		return
+	}
+
+	log.WithFields(logrus.Fields{"hash": response.Hash}).Info("Payment refunded")
+	refundStatus = "sent"
+	dbPayment.RefundTransactionHash = &response.Hash
 }
 
-func (pl PaymentListener) isAssetAllowed(code string, issuer string) bool {
-	if issuer != pl.issuingAccount.Address() {
+// refundAmount returns payment amount minus refunds.fee.
+func (pl PaymentListener) refundAmount(paymentAmount string) (refundAmount string, err error) {
+	value, err := amount.Parse(paymentAmount)
+	if err != nil {
+		return
+	}
+
+	if pl.config.Refunds.Fee != "" {
+		var fee xdr.Int64
+		fee, err = amount.Parse(pl.config.Refunds.Fee)
+		if err != nil {
+			return
+		}
+		value -= fee
+	}
+
+	if value <= 0 {
+		err = errors.New("Payment amount is not greater than refunds.fee")
+		return
+	}
+	return amount.String(value), nil
+}
+
+// errorDelivery returns error hook delivery of a rejected payment (not saved
+// in the DB) or nil when the account has no error hook.
+func (pl PaymentListener) errorDelivery(account config.ReceivingAccount, payment horizon.PaymentResponse, reason string) *db.HookDelivery {
+	if account.ErrorHook == nil {
+		return nil
+	}
+
+	return hooks.NewDelivery(
+		"error",
+		*account.ErrorHook,
+		url.Values{
+			"id":           {payment.Id},
+			"type":         {payment.Type},
+			"account_id":   {account.AccountId},
+			"from":         {payment.From},
+			"amount":       {payment.Amount},
+			"asset_code":   {payment.AssetCode},
+			"asset_issuer": {payment.AssetIssuer},
+			"memo_type":    {payment.Memo.Type},
+			"memo":         {payment.Memo.Value},
+			"reason":       {reason},
+		},
+		payment.Id,
+		pl.now(),
+	)
+}
+
+func (pl PaymentListener) isAssetAllowed(account config.ReceivingAccount, payment horizon.PaymentResponse) bool {
+	issuer := pl.issuingAccount.Address()
+	if payment.AssetType == "native" {
+		issuer = ""
+	}
+	if payment.AssetIssuer != issuer {
 		return false
 	}
 
-	for _, b := range pl.config.Assets {
-		if b == code {
+	for _, b := range account.Assets {
+		if b == payment.AssetCode {
 			return true
 		}
 	}
 	return false
 }
+
+// normalizePayment sets from, to, amount and asset fields of all supported
+// operation types so they can be processed the same way. path_payment fields
+// already contain the destination amount and asset, create_account is
+// a native payment of the starting balance. Native asset code is set to XLM.
+func normalizePayment(payment *horizon.PaymentResponse) {
+	if payment.Type == "create_account" {
+		payment.From = payment.Funder
+		payment.To = payment.Account
+		payment.Amount = payment.StartingBalance
+		payment.AssetType = "native"
+	}
+
+	if payment.AssetType == "native" {
+		payment.AssetCode = "XLM"
+		payment.AssetIssuer = ""
+	}
+}
@@ -1,10 +1,8 @@
 package listener
 
 import (
+	"encoding/hex"
 	"errors"
-	"fmt"
-	"net/http"
-	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -13,28 +11,20 @@ import (
 	"github.com/stellar/gateway/db"
 	"github.com/stellar/gateway/horizon"
 	"github.com/stellar/gateway/mocks"
+	b "github.com/stellar/go-stellar-base/build"
+	"github.com/stellar/go-stellar-base/xdr"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestPaymentListener(t *testing.T) {
 	mockEntityManager := new(mocks.MockEntityManager)
 	mockHorizon := new(mocks.MockHorizon)
 	mockRepository := new(mocks.MockRepository)
+	mockTransactionSubmitter := new(mocks.MockTransactionSubmitter)
 
-	var receiveHookStatusCode int
-
-	receiveHookServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, "POST", r.Method)
-		assert.Equal(t, "1", r.FormValue("id"))
-		assert.Equal(t, "GBIHSMPXC2KJ3NJVHEYTG3KCHYEUQRT45X6AWYWXMAXZOAX4F5LFZYYQ", r.FormValue("from"))
-		assert.Equal(t, "200", r.FormValue("amount"))
-		assert.Equal(t, "USD", r.FormValue("asset_code"))
-		assert.Equal(t, "text", r.FormValue("memo_type"))
-		assert.Equal(t, "testing", r.FormValue("memo"))
-		w.WriteHeader(receiveHookStatusCode)
-		fmt.Fprintln(w, "Response")
-	}))
-	defer receiveHookServer.Close()
+	receiveHook := "http://receive.example.com/hook"
+	errorHook := "http://error.example.com/hook"
 
 	IssuingSeed := "SC34WILLHVADXMP6ACPMIRA6TRAWJMVCLPFNW7S6MUMXJVLAZUC4EWHP"
 	ReceivingAccountId := "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB"
@@ -47,7 +37,7 @@ func TestPaymentListener(t *testing.T) {
 			ReceivingAccountId: &ReceivingAccountId,
 		},
 		Hooks: &config.Hooks{
-			Receive: &receiveHookServer.URL,
+			Receive: &receiveHook,
 		},
 	}
 
@@ -56,37 +46,60 @@ func TestPaymentListener(t *testing.T) {
 		mockEntityManager,
 		mockHorizon,
 		mockRepository,
+		mockTransactionSubmitter,
 		mocks.Now,
 	)
 
+	// Payments are not in the DB unless a test uses a different operation ID
+	mockRepository.On("GetReceivedPaymentByOperationId", "1").Return((*db.ReceivedPayment)(nil), nil)
+
 	Convey("PaymentListener", t, func() {
+		account := config.ReceivingAccounts()[0]
+
 		operation := horizon.PaymentResponse{
 			Id:          "1",
 			From:        "GBIHSMPXC2KJ3NJVHEYTG3KCHYEUQRT45X6AWYWXMAXZOAX4F5LFZYYQ",
 			PagingToken: "2",
 			Amount:      "200",
 		}
+		operation.Links.Transaction.Href = "https://horizon-testnet.stellar.org/transactions/6391dd190f15f7d1665ba53c63842e368f485651a53d8d852ed442a446d1c69a"
 
 		mocks.PredefinedTime = time.Now()
 
 		dbPayment := db.ReceivedPayment{
-			OperationId: operation.Id,
-			ProcessedAt: mocks.PredefinedTime,
-			PagingToken: operation.PagingToken,
+			OperationId:     operation.Id,
+			AccountId:       ReceivingAccountId,
+			ProcessedAt:     mocks.PredefinedTime,
+			PagingToken:     operation.PagingToken,
+			TransactionHash: "6391dd190f15f7d1665ba53c63842e368f485651a53d8d852ed442a446d1c69a",
+			From:            operation.From,
+			Amount:          operation.Amount,
 		}
 
 		Convey("When operation is not a payment", func() {
-			operation.Type = "create_account"
+			operation.Type = "account_merge"
 			dbPayment.Status = "Not a payment operation"
 			mockEntityManager.On("Persist", &dbPayment).Return(nil).Once()
 
 			Convey("it should save the status", func() {
-				err := paymentListener.onPayment(operation)
+				err := paymentListener.onPayment(account, operation)
 				assert.Nil(t, err)
 				mockEntityManager.AssertExpectations(t)
 			})
 		})
 
+		Convey("When operation is not a payment and saving it fails", func() {
+			operation.Type = "account_merge"
+			dbPayment.Status = "Not a payment operation"
+			mockEntityManager.On("Persist", &dbPayment).Return(errors.New("DB error")).Once()
+
+			Convey("it should return error so the payment is retried", func() {
+				err := paymentListener.onPayment(account, operation)
+				assert.Error(t, err)
+				mockEntityManager.AssertExpectations(t)
+			})
+		})
+
 		Convey("When payment is sent not received", func() {
 			operation.Type = "payment"
 			operation.To = "GDNXBMIJLLLXZYKZBHXJ45WQ4AJQBRVT776YKGQTDBHTSPMNAFO3OZOS"
@@ -94,7 +107,7 @@ func TestPaymentListener(t *testing.T) {
 			mockEntityManager.On("Persist", &dbPayment).Return(nil).Once()
 
 			Convey("it should save the status", func() {
-				err := paymentListener.onPayment(operation)
+				err := paymentListener.onPayment(account, operation)
 				assert.Nil(t, err)
 				mockEntityManager.AssertExpectations(t)
 			})
@@ -105,11 +118,13 @@ func TestPaymentListener(t *testing.T) {
 			operation.To = "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB"
 			operation.AssetCode = "USD"
 			operation.AssetIssuer = "GC4WWLMUGZJMRVJM7JUVVZBY3LJ5HL4RKIPADEGKEMLAAJEDRONUGYG7"
+			dbPayment.AssetCode = operation.AssetCode
+			dbPayment.AssetIssuer = operation.AssetIssuer
 			dbPayment.Status = "Asset not allowed"
 			mockEntityManager.On("Persist", &dbPayment).Return(nil).Once()
 
 			Convey("it should save the status", func() {
-				err := paymentListener.onPayment(operation)
+				err := paymentListener.onPayment(account, operation)
 				assert.Nil(t, err)
 				mockEntityManager.AssertExpectations(t)
 			})
@@ -120,11 +135,33 @@ func TestPaymentListener(t *testing.T) {
 			operation.To = "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB"
 			operation.AssetCode = "GBP"
 			operation.AssetIssuer = "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR"
+			dbPayment.AssetCode = operation.AssetCode
+			dbPayment.AssetIssuer = operation.AssetIssuer
 			dbPayment.Status = "Asset not allowed"
-			mockEntityManager.On("Persist", &dbPayment).Return(nil).Once()
 
 			Convey("it should save the status", func() {
-				err := paymentListener.onPayment(operation)
+				mockEntityManager.On("Persist", &dbPayment).Return(nil).Once()
+
+				err := paymentListener.onPayment(account, operation)
+				assert.Nil(t, err)
+				mockEntityManager.AssertExpectations(t)
+			})
+
+			Convey("it should queue error hook delivery when hooks.error is set", func() {
+				account.ErrorHook = &errorHook
+
+				delivery := &db.HookDelivery{
+					Hook:          "error",
+					Url:           errorHook,
+					Payload:       "account_id=GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB&amount=200&asset_code=GBP&asset_issuer=GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR&from=GBIHSMPXC2KJ3NJVHEYTG3KCHYEUQRT45X6AWYWXMAXZOAX4F5LFZYYQ&id=1&memo=&memo_type=&reason=asset_not_allowed&type=payment",
+					OperationId:   "1",
+					Status:        "pending",
+					NextAttemptAt: mocks.PredefinedTime,
+					CreatedAt:     mocks.PredefinedTime,
+				}
+				mockEntityManager.On("PersistAll", []db.Entity{&dbPayment, delivery}).Return(nil).Once()
+
+				err := paymentListener.onPayment(account, operation)
 				assert.Nil(t, err)
 				mockEntityManager.AssertExpectations(t)
 			})
@@ -135,17 +172,53 @@ func TestPaymentListener(t *testing.T) {
 			operation.To = "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB"
 			operation.AssetCode = "USD"
 			operation.AssetIssuer = "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR"
+			dbPayment.AssetCode = operation.AssetCode
+			dbPayment.AssetIssuer = operation.AssetIssuer
 			dbPayment.Status = "Transaction does not have memo"
 
 			mockHorizon.On("LoadMemo", &operation).Return(nil).Once()
-			mockEntityManager.On("Persist", &dbPayment).Return(nil).Once()
 
 			Convey("it should save the status", func() {
-				err := paymentListener.onPayment(operation)
+				mockEntityManager.On("Persist", &dbPayment).Return(nil).Once()
+
+				err := paymentListener.onPayment(account, operation)
 				assert.Nil(t, err)
 				mockHorizon.AssertExpectations(t)
 				mockEntityManager.AssertExpectations(t)
 			})
+
+			Convey("When hooks.error is set", func() {
+				account.ErrorHook = &errorHook
+
+				Convey("it should queue error hook delivery and save the status", func() {
+					delivery := &db.HookDelivery{
+						Hook:          "error",
+						Url:           errorHook,
+						Payload:       "account_id=GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB&amount=200&asset_code=USD&asset_issuer=GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR&from=GBIHSMPXC2KJ3NJVHEYTG3KCHYEUQRT45X6AWYWXMAXZOAX4F5LFZYYQ&id=1&memo=&memo_type=&reason=memo_missing&type=payment",
+						OperationId:   "1",
+						Status:        "pending",
+						NextAttemptAt: mocks.PredefinedTime,
+						CreatedAt:     mocks.PredefinedTime,
+					}
+					mockEntityManager.On("PersistAll", []db.Entity{&dbPayment, delivery}).Return(nil).Once()
+
+					err := paymentListener.onPayment(account, operation)
+					assert.Nil(t, err)
+					mockHorizon.AssertExpectations(t)
+					mockEntityManager.AssertExpectations(t)
+				})
+
+				Convey("it should return error when delivery cannot be saved", func() {
+					mockEntityManager.On("PersistAll", mock.Anything).Return(errors.New("DB error")).Once()
+					persistCalls := len(mockEntityManager.Calls)
+
+					err := paymentListener.onPayment(account, operation)
+					assert.Error(t, err)
+					mockEntityManager.AssertExpectations(t)
+					// Neither payment nor delivery is saved so it will be processed again
+					assert.Equal(t, persistCalls+1, len(mockEntityManager.Calls))
+				})
+			})
 		})
 
 		Convey("When unable to load transaction memo", func() {
@@ -157,14 +230,14 @@ func TestPaymentListener(t *testing.T) {
 			mockHorizon.On("LoadMemo", &operation).Return(errors.New("Connection error")).Once()
 
 			Convey("it should return error", func() {
-				err := paymentListener.onPayment(operation)
+				err := paymentListener.onPayment(account, operation)
 				assert.Error(t, err)
 				mockHorizon.AssertExpectations(t)
 				mockEntityManager.AssertNotCalled(t, "Persist")
 			})
 		})
 
-		Convey("When receive hook returns error", func() {
+		Convey("When payment is valid", func() {
 			operation.Type = "payment"
 			operation.To = "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB"
 			operation.AssetCode = "USD"
@@ -173,17 +246,45 @@ func TestPaymentListener(t *testing.T) {
 			operation.Memo.Value = "testing"
 
 			mockHorizon.On("LoadMemo", &operation).Return(nil).Once()
-			receiveHookStatusCode = 503
 
-			Convey("it should save the status", func() {
-				err := paymentListener.onPayment(operation)
+			Convey("it should queue receive hook delivery and save the status", func() {
+				delivery := &db.HookDelivery{
+					Hook:          "receive",
+					Url:           receiveHook,
+					Payload:       "account_id=GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB&amount=200&asset_code=USD&from=GBIHSMPXC2KJ3NJVHEYTG3KCHYEUQRT45X6AWYWXMAXZOAX4F5LFZYYQ&id=1&memo=testing&memo_type=text&type=payment",
+					OperationId:   "1",
+					Status:        "pending",
+					NextAttemptAt: mocks.PredefinedTime,
+					CreatedAt:     mocks.PredefinedTime,
+				}
+				dbPayment.AssetCode = operation.AssetCode
+				dbPayment.AssetIssuer = operation.AssetIssuer
+				dbPayment.MemoType = operation.Memo.Type
+				dbPayment.Memo = operation.Memo.Value
+				dbPayment.Status = "Success"
+				mockEntityManager.On("PersistAll", []db.Entity{&dbPayment, delivery}).Return(nil).Once()
+
+				err := paymentListener.onPayment(account, operation)
+				assert.Nil(t, err)
+				mockHorizon.AssertExpectations(t)
+				mockEntityManager.AssertExpectations(t)
+			})
+
+			Convey("it should return error when delivery cannot be saved", func() {
+				mockEntityManager.On("PersistAll", mock.Anything).Return(errors.New("DB error")).Once()
+				persistCalls := len(mockEntityManager.Calls)
+
+				err := paymentListener.onPayment(account, operation)
 				assert.Error(t, err)
 				mockHorizon.AssertExpectations(t)
-				mockEntityManager.AssertNotCalled(t, "Persist")
+				mockEntityManager.AssertExpectations(t)
+				// Neither payment nor delivery is saved so it will be processed again
+				assert.Equal(t, persistCalls+1, len(mockEntityManager.Calls))
 			})
 		})
 
-		Convey("When receive hook returns success", func() {
+		Convey("When payment has been already processed", func() {
+			operation.Id = "3"
 			operation.Type = "payment"
 			operation.To = "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB"
 			operation.AssetCode = "USD"
@@ -191,18 +292,577 @@ func TestPaymentListener(t *testing.T) {
 			operation.Memo.Type = "text"
 			operation.Memo.Value = "testing"
 
-			dbPayment.Status = "Success"
+			id := int64(10)
+			existingPayment := &db.ReceivedPayment{
+				Id:          &id,
+				OperationId: "3",
+				PagingToken: "2",
+			}
+
+			Convey("it should not call hooks again when it succeeded", func() {
+				existingPayment.Status = "Success"
+				mockRepository.On("GetReceivedPaymentByOperationId", "3").Return(existingPayment, nil).Once()
+				persistCalls := len(mockEntityManager.Calls)
+				horizonCalls := len(mockHorizon.Calls)
+
+				err := paymentListener.onPayment(account, operation)
+				assert.Nil(t, err)
+				mockRepository.AssertExpectations(t)
+				assert.Equal(t, persistCalls, len(mockEntityManager.Calls))
+				assert.Equal(t, horizonCalls, len(mockHorizon.Calls))
+			})
+
+			Convey("it should not call hooks again when error hook has been queued", func() {
+				existingPayment.Status = "Asset not allowed"
+				mockRepository.On("GetReceivedPaymentByOperationId", "3").Return(existingPayment, nil).Once()
+				mockRepository.On("GetHookDeliveries", db.HookDeliveriesFilter{OperationId: "3", Limit: 1}).Return([]db.HookDelivery{{Hook: "error"}}, nil).Once()
+				persistCalls := len(mockEntityManager.Calls)
+				horizonCalls := len(mockHorizon.Calls)
+
+				err := paymentListener.onPayment(account, operation)
+				assert.Nil(t, err)
+				mockRepository.AssertExpectations(t)
+				assert.Equal(t, persistCalls, len(mockEntityManager.Calls))
+				assert.Equal(t, horizonCalls, len(mockHorizon.Calls))
+			})
+
+			Convey("it should process it again and update the status when it did not succeed", func() {
+				existingPayment.Status = "Asset not allowed"
+				mockRepository.On("GetReceivedPaymentByOperationId", "3").Return(existingPayment, nil).Once()
+				mockRepository.On("GetHookDeliveries", db.HookDeliveriesFilter{OperationId: "3", Limit: 1}).Return([]db.HookDelivery{}, nil).Once()
+				mockHorizon.On("LoadMemo", &operation).Return(nil).Once()
+				mockEntityManager.On("PersistAll", mock.Anything).Run(func(args mock.Arguments) {
+					entities := args.Get(0).([]db.Entity)
+					assert.Equal(t, 2, len(entities))
+					assert.IsType(t, &db.HookDelivery{}, entities[1])
+				}).Return(nil).Once()
+				// Payment is saved with the delivery
+				expectedPayment := &db.ReceivedPayment{
+					Id:              &id,
+					OperationId:     "3",
+					AccountId:       ReceivingAccountId,
+					ProcessedAt:     mocks.PredefinedTime,
+					PagingToken:     "2",
+					Status:          "Success",
+					TransactionHash: "6391dd190f15f7d1665ba53c63842e368f485651a53d8d852ed442a446d1c69a",
+					From:            "GBIHSMPXC2KJ3NJVHEYTG3KCHYEUQRT45X6AWYWXMAXZOAX4F5LFZYYQ",
+					Amount:          "200",
+					AssetCode:       "USD",
+					AssetIssuer:     "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR",
+					MemoType:        "text",
+					Memo:            "testing",
+				}
+
+				err := paymentListener.onPayment(account, operation)
+				assert.Nil(t, err)
+				assert.Equal(t, expectedPayment, mockEntityManager.Calls[len(mockEntityManager.Calls)-1].Arguments.Get(0).([]db.Entity)[0])
+				mockRepository.AssertExpectations(t)
+				mockHorizon.AssertExpectations(t)
+				mockEntityManager.AssertExpectations(t)
+			})
+		})
+
+		Convey("When payment cannot be loaded from the DB", func() {
+			operation.Id = "4"
+			mockRepository.On("GetReceivedPaymentByOperationId", "4").Return((*db.ReceivedPayment)(nil), errors.New("DB error")).Once()
+			persistCalls := len(mockEntityManager.Calls)
+
+			Convey("it should return error", func() {
+				err := paymentListener.onPayment(account, operation)
+				assert.Error(t, err)
+				mockRepository.AssertExpectations(t)
+				assert.Equal(t, persistCalls, len(mockEntityManager.Calls))
+			})
+		})
+	})
+}
+
+func TestPaymentListenerReceivingAccounts(t *testing.T) {
+	mockEntityManager := new(mocks.MockEntityManager)
+	mockHorizon := new(mocks.MockHorizon)
+	mockRepository := new(mocks.MockRepository)
+	mockTransactionSubmitter := new(mocks.MockTransactionSubmitter)
+
+	receiveHook := "http://receive.example.com/hook"
+	depositHook := "http://deposit.example.com/hook"
+
+	IssuingSeed := "SC34WILLHVADXMP6ACPMIRA6TRAWJMVCLPFNW7S6MUMXJVLAZUC4EWHP"
+	ReceivingAccountId := "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB"
+	DepositAccountId := "GDNXBMIJLLLXZYKZBHXJ45WQ4AJQBRVT776YKGQTDBHTSPMNAFO3OZOS"
+
+	config := &config.Config{
+		Assets: []string{"USD", "EUR"},
+		Accounts: &config.Accounts{
+			// GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR
+			IssuingSeed:        &IssuingSeed,
+			ReceivingAccountId: &ReceivingAccountId,
+		},
+		Hooks: &config.Hooks{
+			Receive: &receiveHook,
+		},
+		Receiving: []config.ReceivingAccount{
+			{
+				AccountId:   DepositAccountId,
+				Assets:      []string{"BTC", "XLM"},
+				ReceiveHook: &depositHook,
+			},
+		},
+	}
+
+	paymentListener, _ := NewPaymentListener(
+		config,
+		mockEntityManager,
+		mockHorizon,
+		mockRepository,
+		mockTransactionSubmitter,
+		mocks.Now,
+	)
+
+	Convey("Given receiving accounts", t, func() {
+		accounts := config.ReceivingAccounts()
+
+		Convey("it should use top-level assets and hooks for accounts.receiving_account_id", func() {
+			assert.Equal(t, 2, len(accounts))
+			assert.Equal(t, ReceivingAccountId, accounts[0].AccountId)
+			assert.Equal(t, []string{"USD", "EUR"}, accounts[0].Assets)
+			assert.Equal(t, receiveHook, *accounts[0].ReceiveHook)
+			assert.Equal(t, DepositAccountId, accounts[1].AccountId)
+			assert.Equal(t, []string{"BTC", "XLM"}, accounts[1].Assets)
+			assert.Equal(t, depositHook, *accounts[1].ReceiveHook)
+		})
 
+		Convey("When payment to [[receiving]] account is valid", func() {
+			mocks.PredefinedTime = time.Now()
+			operation := horizon.PaymentResponse{
+				Id:          "5",
+				Type:        "payment",
+				From:        "GBIHSMPXC2KJ3NJVHEYTG3KCHYEUQRT45X6AWYWXMAXZOAX4F5LFZYYQ",
+				To:          DepositAccountId,
+				PagingToken: "5",
+				Amount:      "1",
+				AssetCode:   "BTC",
+				AssetIssuer: "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR",
+			}
+			operation.Memo.Type = "id"
+			operation.Memo.Value = "42"
+
+			mockRepository.On("GetReceivedPaymentByOperationId", "5").Return((*db.ReceivedPayment)(nil), nil).Once()
 			mockHorizon.On("LoadMemo", &operation).Return(nil).Once()
-			mockEntityManager.On("Persist", &dbPayment).Return(nil).Once()
-			receiveHookStatusCode = 200
 
-			Convey("it should save the status", func() {
-				err := paymentListener.onPayment(operation)
+			Convey("it should queue delivery to the account receive hook", func() {
+				mockEntityManager.On("PersistAll", mock.Anything).Run(func(args mock.Arguments) {
+					entities := args.Get(0).([]db.Entity)
+					payment := entities[0].(*db.ReceivedPayment)
+					assert.Equal(t, DepositAccountId, payment.AccountId)
+					assert.Equal(t, "Success", payment.Status)
+					assert.Equal(t, &db.HookDelivery{
+						Hook:          "receive",
+						Url:           depositHook,
+						Payload:       "account_id=GDNXBMIJLLLXZYKZBHXJ45WQ4AJQBRVT776YKGQTDBHTSPMNAFO3OZOS&amount=1&asset_code=BTC&from=GBIHSMPXC2KJ3NJVHEYTG3KCHYEUQRT45X6AWYWXMAXZOAX4F5LFZYYQ&id=5&memo=42&memo_type=id&type=payment",
+						OperationId:   "5",
+						Status:        "pending",
+						NextAttemptAt: mocks.PredefinedTime,
+						CreatedAt:     mocks.PredefinedTime,
+					}, entities[1])
+				}).Return(nil).Once()
+
+				err := paymentListener.onPayment(accounts[1], operation)
+				assert.Nil(t, err)
+				mockRepository.AssertExpectations(t)
+				mockHorizon.AssertExpectations(t)
+				mockEntityManager.AssertExpectations(t)
+			})
+		})
+
+		Convey("When path payment is received", func() {
+			mocks.PredefinedTime = time.Now()
+			operation := horizon.PaymentResponse{
+				Id:                "6",
+				Type:              "path_payment",
+				From:              "GBIHSMPXC2KJ3NJVHEYTG3KCHYEUQRT45X6AWYWXMAXZOAX4F5LFZYYQ",
+				To:                DepositAccountId,
+				PagingToken:       "6",
+				Amount:            "1",
+				AssetType:         "credit_alphanum4",
+				AssetCode:         "BTC",
+				AssetIssuer:       "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR",
+				SourceAmount:      "400",
+				SourceAssetType:   "credit_alphanum4",
+				SourceAssetCode:   "USD",
+				SourceAssetIssuer: "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR",
+			}
+			operation.Memo.Type = "id"
+			operation.Memo.Value = "42"
+
+			mockRepository.On("GetReceivedPaymentByOperationId", "6").Return((*db.ReceivedPayment)(nil), nil).Once()
+			mockHorizon.On("LoadMemo", &operation).Return(nil).Once()
+
+			Convey("it should queue receive hook delivery with the destination amount and asset", func() {
+				mockEntityManager.On("PersistAll", mock.Anything).Run(func(args mock.Arguments) {
+					entities := args.Get(0).([]db.Entity)
+					payment := entities[0].(*db.ReceivedPayment)
+					assert.Equal(t, "1", payment.Amount)
+					assert.Equal(t, "BTC", payment.AssetCode)
+					assert.Equal(t, "Success", payment.Status)
+					assert.Equal(t, &db.HookDelivery{
+						Hook:          "receive",
+						Url:           depositHook,
+						Payload:       "account_id=GDNXBMIJLLLXZYKZBHXJ45WQ4AJQBRVT776YKGQTDBHTSPMNAFO3OZOS&amount=1&asset_code=BTC&from=GBIHSMPXC2KJ3NJVHEYTG3KCHYEUQRT45X6AWYWXMAXZOAX4F5LFZYYQ&id=6&memo=42&memo_type=id&type=path_payment",
+						OperationId:   "6",
+						Status:        "pending",
+						NextAttemptAt: mocks.PredefinedTime,
+						CreatedAt:     mocks.PredefinedTime,
+					}, entities[1])
+				}).Return(nil).Once()
+
+				err := paymentListener.onPayment(accounts[1], operation)
+				assert.Nil(t, err)
+				mockHorizon.AssertExpectations(t)
+				mockEntityManager.AssertExpectations(t)
+			})
+		})
+
+		Convey("When account is created", func() {
+			mocks.PredefinedTime = time.Now()
+			operation := horizon.PaymentResponse{
+				Id:              "7",
+				Type:            "create_account",
+				PagingToken:     "7",
+				Funder:          "GBIHSMPXC2KJ3NJVHEYTG3KCHYEUQRT45X6AWYWXMAXZOAX4F5LFZYYQ",
+				Account:         DepositAccountId,
+				StartingBalance: "20.0000000",
+			}
+			operation.Memo.Type = "id"
+			operation.Memo.Value = "42"
+
+			mockRepository.On("GetReceivedPaymentByOperationId", "7").Return((*db.ReceivedPayment)(nil), nil).Once()
+
+			Convey("it should queue receive hook delivery of the starting balance when XLM is allowed", func() {
+				normalized := operation
+				normalizePayment(&normalized)
+				mockHorizon.On("LoadMemo", &normalized).Return(nil).Once()
+				mockEntityManager.On("PersistAll", mock.Anything).Run(func(args mock.Arguments) {
+					entities := args.Get(0).([]db.Entity)
+					assert.IsType(t, &db.ReceivedPayment{}, entities[0])
+					assert.Equal(t, &db.HookDelivery{
+						Hook:          "receive",
+						Url:           depositHook,
+						Payload:       "account_id=GDNXBMIJLLLXZYKZBHXJ45WQ4AJQBRVT776YKGQTDBHTSPMNAFO3OZOS&amount=20.0000000&asset_code=XLM&from=GBIHSMPXC2KJ3NJVHEYTG3KCHYEUQRT45X6AWYWXMAXZOAX4F5LFZYYQ&id=7&memo=42&memo_type=id&type=create_account",
+						OperationId:   "7",
+						Status:        "pending",
+						NextAttemptAt: mocks.PredefinedTime,
+						CreatedAt:     mocks.PredefinedTime,
+					}, entities[1])
+				}).Return(nil).Once()
+
+				err := paymentListener.onPayment(accounts[1], operation)
 				assert.Nil(t, err)
 				mockHorizon.AssertExpectations(t)
 				mockEntityManager.AssertExpectations(t)
 			})
+
+			Convey("it should reject it when XLM is not allowed", func() {
+				operation.Account = ReceivingAccountId
+				mockEntityManager.On("Persist", mock.AnythingOfType("*db.ReceivedPayment")).Run(func(args mock.Arguments) {
+					assert.Equal(t, "Asset not allowed", args.Get(0).(*db.ReceivedPayment).Status)
+				}).Return(nil).Once()
+
+				err := paymentListener.onPayment(accounts[0], operation)
+				assert.Nil(t, err)
+				mockEntityManager.AssertExpectations(t)
+			})
+		})
+
+		Convey("When loading last cursor", func() {
+			cursor := "100"
+
+			Convey("it should use saved stream cursor", func() {
+				mockRepository.On("GetStreamCursor", "payments:"+ReceivingAccountId).Return(&db.StreamCursor{Cursor: "200"}, nil).Once()
+
+				lastCursor, err := paymentListener.lastCursor(ReceivingAccountId)
+				assert.Nil(t, err)
+				assert.Equal(t, "200", *lastCursor)
+				mockRepository.AssertExpectations(t)
+			})
+
+			Convey("it should use payments without account ID for accounts.receiving_account_id", func() {
+				mockRepository.On("GetStreamCursor", "payments:"+ReceivingAccountId).Return((*db.StreamCursor)(nil), nil).Once()
+				mockRepository.On("GetLastCursorValue", "").Return(&cursor, nil).Once()
+
+				lastCursor, err := paymentListener.lastCursor(ReceivingAccountId)
+				assert.Nil(t, err)
+				assert.Equal(t, "100", *lastCursor)
+				mockRepository.AssertExpectations(t)
+			})
+
+			Convey("it should not use payments without account ID for [[receiving]] accounts", func() {
+				mockRepository.On("GetStreamCursor", "payments:"+DepositAccountId).Return((*db.StreamCursor)(nil), nil).Once()
+
+				lastCursor, err := paymentListener.lastCursor(DepositAccountId)
+				assert.Nil(t, err)
+				assert.Nil(t, lastCursor)
+				mockRepository.AssertExpectations(t)
+			})
+		})
+
+		Convey("When streamed payment is processed", func() {
+			mocks.PredefinedTime = time.Now()
+			operation := horizon.PaymentResponse{
+				Id:          "9",
+				Type:        "payment",
+				From:        "GBIHSMPXC2KJ3NJVHEYTG3KCHYEUQRT45X6AWYWXMAXZOAX4F5LFZYYQ",
+				To:          DepositAccountId,
+				PagingToken: "900",
+				Amount:      "1",
+				AssetCode:   "BTC",
+				AssetIssuer: "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR",
+			}
+			operation.Memo.Type = "id"
+			operation.Memo.Value = "42"
+
+			id := int64(3)
+			streamCursor := &db.StreamCursor{Id: &id, Name: "payments:" + DepositAccountId, Cursor: "800"}
+			mockRepository.On("GetStreamCursor", "payments:"+DepositAccountId).Return(streamCursor, nil).Once()
+
+			Convey("it should save stream cursor and hook delivery together with the payment", func() {
+				mockRepository.On("GetReceivedPaymentByOperationId", "9").Return((*db.ReceivedPayment)(nil), nil).Once()
+				mockHorizon.On("LoadMemo", &operation).Return(nil).Once()
+				mockEntityManager.On("PersistAll", mock.Anything).Run(func(args mock.Arguments) {
+					objects := args.Get(0).([]db.Entity)
+					assert.Equal(t, 3, len(objects))
+					assert.Equal(t, "Success", objects[0].(*db.ReceivedPayment).Status)
+					assert.Equal(t, "receive", objects[1].(*db.HookDelivery).Hook)
+					assert.Equal(t, streamCursor, objects[2])
+				}).Return(nil).Once()
+
+				err := paymentListener.onStreamedPayment(accounts[1], operation)
+				assert.Nil(t, err)
+				assert.Equal(t, "900", streamCursor.Cursor)
+				assert.Equal(t, mocks.PredefinedTime, streamCursor.UpdatedAt)
+				mockRepository.AssertExpectations(t)
+				mockEntityManager.AssertExpectations(t)
+			})
+
+			Convey("it should save stream cursor when payment has been already processed", func() {
+				mockRepository.On("GetReceivedPaymentByOperationId", "9").Return(&db.ReceivedPayment{Status: "Success"}, nil).Once()
+				mockEntityManager.On("Persist", streamCursor).Return(nil).Once()
+
+				err := paymentListener.onStreamedPayment(accounts[1], operation)
+				assert.Nil(t, err)
+				assert.Equal(t, "900", streamCursor.Cursor)
+				mockRepository.AssertExpectations(t)
+				mockEntityManager.AssertExpectations(t)
+			})
+
+			Convey("it should not process payment when stream cursor cannot be loaded", func() {
+				mockRepository.ExpectedCalls = nil
+				mockRepository.On("GetStreamCursor", "payments:"+DepositAccountId).Return((*db.StreamCursor)(nil), errors.New("DB error")).Once()
+				calls := len(mockRepository.Calls)
+
+				err := paymentListener.onStreamedPayment(accounts[1], operation)
+				assert.Error(t, err)
+				assert.Equal(t, calls+1, len(mockRepository.Calls))
+			})
+		})
+	})
+}
+
+func TestPaymentListenerRefunds(t *testing.T) {
+	mockEntityManager := new(mocks.MockEntityManager)
+	mockHorizon := new(mocks.MockHorizon)
+	mockRepository := new(mocks.MockRepository)
+	mockTransactionSubmitter := new(mocks.MockTransactionSubmitter)
+
+	receiveHook := "http://receive.example.com/hook"
+
+	IssuingSeed := "SC34WILLHVADXMP6ACPMIRA6TRAWJMVCLPFNW7S6MUMXJVLAZUC4EWHP"
+	ReceivingAccountId := "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB"
+
+	config := &config.Config{
+		Assets: []string{"USD", "EUR"},
+		Accounts: &config.Accounts{
+			// GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR
+			IssuingSeed:        &IssuingSeed,
+			ReceivingAccountId: &ReceivingAccountId,
+		},
+		Hooks: &config.Hooks{
+			Receive: &receiveHook,
+		},
+		Refunds: &config.Refunds{
+			Reasons: []string{"memo_missing"},
+			Fee:     "1",
+		},
+	}
+
+	paymentListener, _ := NewPaymentListener(
+		config,
+		mockEntityManager,
+		mockHorizon,
+		mockRepository,
+		mockTransactionSubmitter,
+		mocks.Now,
+	)
+
+	Convey("Given rejected payment", t, func() {
+		account := config.ReceivingAccounts()[0]
+		mocks.PredefinedTime = time.Now()
+
+		operation := horizon.PaymentResponse{
+			Id:          "8",
+			Type:        "payment",
+			From:        "GBIHSMPXC2KJ3NJVHEYTG3KCHYEUQRT45X6AWYWXMAXZOAX4F5LFZYYQ",
+			To:          ReceivingAccountId,
+			PagingToken: "8",
+			Amount:      "200",
+			AssetCode:   "USD",
+			AssetIssuer: "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR",
+		}
+		operation.Links.Transaction.Href = "https://horizon-testnet.stellar.org/transactions/6391dd190f15f7d1665ba53c63842e368f485651a53d8d852ed442a446d1c69a"
+
+		var memo xdr.Hash
+		hash, _ := hex.DecodeString("6391dd190f15f7d1665ba53c63842e368f485651a53d8d852ed442a446d1c69a")
+		copy(memo[:], hash)
+
+		// Persist is called with the same object so the statuses are copied
+		var refundStatuses []string
+		recordRefundStatus := func(args mock.Arguments) {
+			refundStatuses = append(refundStatuses, *args.Get(0).(*db.ReceivedPayment).RefundStatus)
+		}
+
+		Convey("When payment does not have memo", func() {
+			mockRepository.On("GetReceivedPaymentByOperationId", "8").Return((*db.ReceivedPayment)(nil), nil).Once()
+			mockHorizon.On("LoadMemo", &operation).Return(nil).Once()
+
+			operationBuilder := b.Payment(
+				b.Destination{"GBIHSMPXC2KJ3NJVHEYTG3KCHYEUQRT45X6AWYWXMAXZOAX4F5LFZYYQ"},
+				b.CreditAmount{"USD", "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR", "199.0000000"},
+			)
+
+			Convey("it should refund the amount minus fee", func() {
+				ledger := uint64(100)
+				mockTransactionSubmitter.On("SubmitTransaction", ReceivingAccountId, operationBuilder, b.MemoReturn{memo}).Return(
+					horizon.SubmitTransactionResponse{Hash: "ab", Ledger: &ledger},
+					nil,
+				).Once()
+				var savedPayment *db.ReceivedPayment
+				mockEntityManager.On("Persist", mock.AnythingOfType("*db.ReceivedPayment")).Run(func(args mock.Arguments) {
+					recordRefundStatus(args)
+					savedPayment = args.Get(0).(*db.ReceivedPayment)
+				}).Return(nil).Twice()
+
+				err := paymentListener.onPayment(account, operation)
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"pending", "sent"}, refundStatuses)
+				assert.Equal(t, "Transaction does not have memo", savedPayment.Status)
+				assert.Equal(t, "ab", *savedPayment.RefundTransactionHash)
+				mockTransactionSubmitter.AssertExpectations(t)
+				mockEntityManager.AssertExpectations(t)
+			})
+
+			Convey("it should leave refund pending when submission timed out", func() {
+				sentTransactionId := int64(5)
+				mockTransactionSubmitter.On("SubmitTransaction", ReceivingAccountId, operationBuilder, b.MemoReturn{memo}).Return(
+					horizon.SubmitTransactionResponse{SentTransactionId: &sentTransactionId},
+					horizon.ErrTimeout,
+				).Once()
+				var savedPayment *db.ReceivedPayment
+				mockEntityManager.On("Persist", mock.AnythingOfType("*db.ReceivedPayment")).Run(func(args mock.Arguments) {
+					recordRefundStatus(args)
+					savedPayment = args.Get(0).(*db.ReceivedPayment)
+				}).Return(nil).Twice()
+
+				err := paymentListener.onPayment(account, operation)
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"pending", "pending"}, refundStatuses)
+				assert.Equal(t, sentTransactionId, *savedPayment.RefundTransactionId)
+				mockTransactionSubmitter.AssertExpectations(t)
+				mockEntityManager.AssertExpectations(t)
+			})
+
+			Convey("it should leave refund pending when saving the submitted transaction failed", func() {
+				sentTransactionId := int64(5)
+				ledger := uint64(100)
+				mockTransactionSubmitter.On("SubmitTransaction", ReceivingAccountId, operationBuilder, b.MemoReturn{memo}).Return(
+					horizon.SubmitTransactionResponse{Hash: "ab", Ledger: &ledger, SentTransactionId: &sentTransactionId},
+					errors.New("DB error"),
+				).Once()
+				mockEntityManager.On("Persist", mock.AnythingOfType("*db.ReceivedPayment")).Run(recordRefundStatus).Return(nil).Twice()
+
+				err := paymentListener.onPayment(account, operation)
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"pending", "pending"}, refundStatuses)
+				mockTransactionSubmitter.AssertExpectations(t)
+				mockEntityManager.AssertExpectations(t)
+			})
+
+			Convey("it should mark refund as failed when the transaction was not saved", func() {
+				mockTransactionSubmitter.On("SubmitTransaction", ReceivingAccountId, operationBuilder, b.MemoReturn{memo}).Return(
+					horizon.SubmitTransactionResponse{},
+					horizon.ErrUnavailable,
+				).Once()
+				mockEntityManager.On("Persist", mock.AnythingOfType("*db.ReceivedPayment")).Run(recordRefundStatus).Return(nil).Twice()
+
+				err := paymentListener.onPayment(account, operation)
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"pending", "failed"}, refundStatuses)
+				mockTransactionSubmitter.AssertExpectations(t)
+				mockEntityManager.AssertExpectations(t)
+			})
+
+			Convey("it should mark refund as failed when amount is not greater than fee", func() {
+				operation.Amount = "1"
+				mockHorizon.ExpectedCalls = nil
+				mockHorizon.On("LoadMemo", &operation).Return(nil).Once()
+				submitCalls := len(mockTransactionSubmitter.Calls)
+				mockEntityManager.On("Persist", mock.AnythingOfType("*db.ReceivedPayment")).Run(recordRefundStatus).Return(nil).Twice()
+
+				err := paymentListener.onPayment(account, operation)
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"pending", "failed"}, refundStatuses)
+				assert.Equal(t, submitCalls, len(mockTransactionSubmitter.Calls))
+				mockEntityManager.AssertExpectations(t)
+			})
+		})
+
+		Convey("When payment has been already refunded", func() {
+			id := int64(10)
+			refundStatus := "sent"
+			mockRepository.On("GetReceivedPaymentByOperationId", "8").Return(&db.ReceivedPayment{
+				Id:           &id,
+				OperationId:  "8",
+				Status:       "Transaction does not have memo",
+				RefundStatus: &refundStatus,
+			}, nil).Once()
+
+			Convey("it should not process it again", func() {
+				persistCalls := len(mockEntityManager.Calls)
+				submitCalls := len(mockTransactionSubmitter.Calls)
+
+				err := paymentListener.onPayment(account, operation)
+				assert.Nil(t, err)
+				mockRepository.AssertExpectations(t)
+				assert.Equal(t, persistCalls, len(mockEntityManager.Calls))
+				assert.Equal(t, submitCalls, len(mockTransactionSubmitter.Calls))
+			})
+		})
+
+		Convey("When reason is not refunded", func() {
+			operation.AssetCode = "GBP"
+			mockRepository.On("GetReceivedPaymentByOperationId", "8").Return((*db.ReceivedPayment)(nil), nil).Once()
+
+			Convey("it should only save the status", func() {
+				submitCalls := len(mockTransactionSubmitter.Calls)
+				mockEntityManager.On("Persist", mock.AnythingOfType("*db.ReceivedPayment")).Run(func(args mock.Arguments) {
+					payment := args.Get(0).(*db.ReceivedPayment)
+					assert.Equal(t, "Asset not allowed", payment.Status)
+					assert.Nil(t, payment.RefundStatus)
+				}).Return(nil).Once()
+
+				err := paymentListener.onPayment(account, operation)
+				assert.Nil(t, err)
+				assert.Equal(t, submitCalls, len(mockTransactionSubmitter.Calls))
+				mockEntityManager.AssertExpectations(t)
+			})
 		})
 	})
 }
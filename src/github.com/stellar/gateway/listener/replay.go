@@ -0,0 +1,152 @@
+package listener
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/stellar/gateway/config"
+	"github.com/stellar/gateway/horizon"
+)
+
+const replayPageLimit = 200
+
+// ReplayOptions contains a window of payments replayed by Replay.
+type ReplayOptions struct {
+	AccountId string
+	// FromCursor is exclusive, payments after this cursor are replayed.
+	// Empty FromCursor replays payments from the beginning.
+	FromCursor string
+	// ToCursor is inclusive. Empty ToCursor replays payments up to the
+	// stream cursor of the account, ToCursor after it is capped at it.
+	ToCursor string
+}
+
+// LedgerStartCursor returns a cursor of payments before the first payment
+// in a given ledger.
+func LedgerStartCursor(ledger uint32) string {
+	return strconv.FormatInt(int64(ledger)<<32, 10)
+}
+
+// LedgerEndCursor returns a cursor of the last possible payment in a given
+// ledger.
+func LedgerEndCursor(ledger uint32) string {
+	return strconv.FormatInt((int64(ledger)+1)<<32-1, 10)
+}
+
+// Replay loads historical payments of a receiving account from horizon and
+// processes them like streamed payments. Payments already processed with
+// Success status are skipped. Payments after the stream cursor are not
+// replayed and the cursor is not changed so replay does not affect the
+// running listener. Returns a cursor of the last replayed payment
+// so replay can be continued from it after an error.
+func (pl PaymentListener) Replay(ctx context.Context, options ReplayOptions) (lastCursor string, count int, err error) {
+	account, err := pl.receivingAccount(options.AccountId)
+	if err != nil {
+		return
+	}
+
+	var toCursor int64
+	if options.ToCursor != "" {
+		toCursor, err = strconv.ParseInt(options.ToCursor, 10, 64)
+		if err != nil {
+			err = errors.New("Invalid to cursor: " + options.ToCursor)
+			return
+		}
+	}
+
+	log := pl.log.WithFields(logrus.Fields{"accountId": account.AccountId})
+
+	// Payments after the stream cursor are left for the running listener.
+	// Replay would save rejected ones without refunds and the listener would
+	// skip them.
+	streamCursor, err := pl.streamCursorToken(account.AccountId)
+	if err != nil {
+		return
+	}
+	if toCursor == 0 || toCursor > streamCursor {
+		log.WithFields(logrus.Fields{"cursor": streamCursor}).Info("Replay window ends at the stream cursor")
+		toCursor = streamCursor
+	}
+
+	log.WithFields(logrus.Fields{
+		"from": options.FromCursor,
+		"to":   options.ToCursor,
+	}).Info("Replaying payments")
+
+	lastCursor = options.FromCursor
+	for {
+		var payments []horizon.PaymentResponse
+		payments, err = pl.horizon.LoadPayments(ctx, account.AccountId, lastCursor, replayPageLimit)
+		if err != nil {
+			return
+		}
+
+		for _, payment := range payments {
+			var token int64
+			token, err = strconv.ParseInt(payment.PagingToken, 10, 64)
+			if err != nil {
+				return
+			}
+			if token > toCursor {
+				log.WithFields(logrus.Fields{"count": count}).Info("Replay finished")
+				return
+			}
+
+			err = pl.onPayment(account, payment)
+			if err != nil {
+				log.WithFields(logrus.Fields{
+					"id":     payment.Id,
+					"cursor": lastCursor,
+					"err":    err,
+				}).Error("Error replaying payment")
+				return
+			}
+
+			lastCursor = payment.PagingToken
+			count++
+		}
+
+		if len(payments) < replayPageLimit {
+			log.WithFields(logrus.Fields{"count": count}).Info("Replay finished")
+			return
+		}
+	}
+}
+
+// streamCursorToken returns the saved cursor of the payments stream of a
+// receiving account. Replay is not possible before the listener has processed
+// a payment of the account.
+func (pl PaymentListener) streamCursorToken(accountId string) (token int64, err error) {
+	streamCursor, err := pl.repository.GetStreamCursor(StreamName(accountId))
+	if err != nil {
+		return
+	}
+	if streamCursor == nil {
+		err = errors.New("Payments stream of the account has not been started: " + accountId)
+		return
+	}
+
+	token, err = strconv.ParseInt(streamCursor.Cursor, 10, 64)
+	if err != nil {
+		err = errors.New("Payments stream of the account has not processed a payment yet, cursor: " + streamCursor.Cursor)
+	}
+	return
+}
+
+// receivingAccount returns a receiving account with a given ID. Accounts
+// without receive hook are not streamed so they cannot be replayed.
+func (pl PaymentListener) receivingAccount(accountId string) (account config.ReceivingAccount, err error) {
+	for _, account = range pl.config.ReceivingAccounts() {
+		if account.AccountId != accountId {
+			continue
+		}
+		if account.ReceiveHook == nil {
+			err = errors.New("Receiving account does not have receive hook: " + accountId)
+		}
+		return
+	}
+	err = errors.New("Not a receiving account: " + accountId)
+	return
+}
@@ -0,0 +1,150 @@
+package listener
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stellar/gateway/config"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaymentListenerReplay(t *testing.T) {
+	mockEntityManager := new(mocks.MockEntityManager)
+	mockHorizon := new(mocks.MockHorizon)
+	mockRepository := new(mocks.MockRepository)
+	mockTransactionSubmitter := new(mocks.MockTransactionSubmitter)
+
+	receiveHook := "http://receive.example.com/hook"
+
+	IssuingSeed := "SC34WILLHVADXMP6ACPMIRA6TRAWJMVCLPFNW7S6MUMXJVLAZUC4EWHP"
+	ReceivingAccountId := "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB"
+	DepositAccountId := "GDNXBMIJLLLXZYKZBHXJ45WQ4AJQBRVT776YKGQTDBHTSPMNAFO3OZOS"
+
+	config := &config.Config{
+		Assets: []string{"USD", "EUR"},
+		Accounts: &config.Accounts{
+			// GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR
+			IssuingSeed:        &IssuingSeed,
+			ReceivingAccountId: &ReceivingAccountId,
+		},
+		Hooks: &config.Hooks{
+			Receive: &receiveHook,
+		},
+	}
+
+	paymentListener, _ := NewPaymentListener(
+		config,
+		mockEntityManager,
+		mockHorizon,
+		mockRepository,
+		mockTransactionSubmitter,
+		mocks.Now,
+	)
+
+	Convey("Given ledger", t, func() {
+		Convey("it should return cursors of the first and the last payment", func() {
+			assert.Equal(t, "21474836480", LedgerStartCursor(5))
+			assert.Equal(t, "25769803775", LedgerEndCursor(5))
+		})
+	})
+
+	Convey("Given payments to replay", t, func() {
+		payments := []horizon.PaymentResponse{
+			{Id: "101", Type: "payment", PagingToken: "101"},
+			{Id: "150", Type: "payment", PagingToken: "150"},
+			{Id: "300", Type: "payment", PagingToken: "300"},
+		}
+		mockHorizon.On("LoadPayments", ReceivingAccountId, "100", replayPageLimit).Return(payments, nil).Once()
+		streamName := StreamName(ReceivingAccountId)
+
+		Convey("it should process payments up to ToCursor without changing stream cursor", func() {
+			mockRepository.On("GetStreamCursor", streamName).Return(&db.StreamCursor{Name: streamName, Cursor: "1000"}, nil).Once()
+			// Already processed payments are skipped
+			mockRepository.On("GetReceivedPaymentByOperationId", "101").Return(&db.ReceivedPayment{Status: "Success"}, nil).Once()
+			mockRepository.On("GetReceivedPaymentByOperationId", "150").Return(&db.ReceivedPayment{Status: "Success"}, nil).Once()
+			persistCalls := len(mockEntityManager.Calls)
+
+			lastCursor, count, err := paymentListener.Replay(context.Background(), ReplayOptions{
+				AccountId:  ReceivingAccountId,
+				FromCursor: "100",
+				ToCursor:   "299",
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, "150", lastCursor)
+			assert.Equal(t, 2, count)
+			assert.Equal(t, persistCalls, len(mockEntityManager.Calls))
+			mockHorizon.AssertExpectations(t)
+			mockRepository.AssertExpectations(t)
+		})
+
+		Convey("it should not replay payments after the stream cursor", func() {
+			mockRepository.On("GetStreamCursor", streamName).Return(&db.StreamCursor{Name: streamName, Cursor: "150"}, nil).Once()
+			mockRepository.On("GetReceivedPaymentByOperationId", "101").Return(&db.ReceivedPayment{Status: "Success"}, nil).Once()
+			mockRepository.On("GetReceivedPaymentByOperationId", "150").Return(&db.ReceivedPayment{Status: "Success"}, nil).Once()
+
+			lastCursor, count, err := paymentListener.Replay(context.Background(), ReplayOptions{
+				AccountId:  ReceivingAccountId,
+				FromCursor: "100",
+				ToCursor:   "1000",
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, "150", lastCursor)
+			assert.Equal(t, 2, count)
+			mockRepository.AssertExpectations(t)
+		})
+
+		Convey("it should return error when the stream has not processed a payment yet", func() {
+			mockHorizon.ExpectedCalls = nil
+			mockRepository.On("GetStreamCursor", streamName).Return(&db.StreamCursor{Name: streamName, Cursor: "now"}, nil).Once()
+			loadCalls := len(mockHorizon.Calls)
+
+			_, count, err := paymentListener.Replay(context.Background(), ReplayOptions{
+				AccountId:  ReceivingAccountId,
+				FromCursor: "100",
+			})
+			assert.Error(t, err)
+			assert.Equal(t, 0, count)
+			assert.Equal(t, loadCalls, len(mockHorizon.Calls))
+			mockRepository.AssertExpectations(t)
+		})
+
+		Convey("it should return error when the stream has not been started", func() {
+			mockHorizon.ExpectedCalls = nil
+			mockRepository.On("GetStreamCursor", streamName).Return((*db.StreamCursor)(nil), nil).Once()
+
+			_, _, err := paymentListener.Replay(context.Background(), ReplayOptions{
+				AccountId:  ReceivingAccountId,
+				FromCursor: "100",
+			})
+			assert.Error(t, err)
+			mockRepository.AssertExpectations(t)
+		})
+
+		Convey("it should return cursor of the last replayed payment when payment cannot be processed", func() {
+			mockRepository.On("GetStreamCursor", streamName).Return(&db.StreamCursor{Name: streamName, Cursor: "1000"}, nil).Once()
+			mockRepository.On("GetReceivedPaymentByOperationId", "101").Return(&db.ReceivedPayment{Status: "Success"}, nil).Once()
+			mockRepository.On("GetReceivedPaymentByOperationId", "150").Return((*db.ReceivedPayment)(nil), errors.New("DB error")).Once()
+
+			lastCursor, count, err := paymentListener.Replay(context.Background(), ReplayOptions{
+				AccountId:  ReceivingAccountId,
+				FromCursor: "100",
+			})
+			assert.Error(t, err)
+			assert.Equal(t, "101", lastCursor)
+			assert.Equal(t, 1, count)
+			mockRepository.AssertExpectations(t)
+		})
+	})
+
+	Convey("Given account that is not receiving account", t, func() {
+		Convey("it should return error", func() {
+			_, _, err := paymentListener.Replay(context.Background(), ReplayOptions{AccountId: DepositAccountId})
+			assert.Error(t, err)
+		})
+	})
+}
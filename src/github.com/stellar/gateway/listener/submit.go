@@ -0,0 +1,23 @@
+package listener
+
+import (
+	"context"
+
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/submitter"
+)
+
+// submitTransaction submits a transaction of the listener or the watcher.
+// pending is true when submission failed after the SentTransaction was saved
+// (ex. horizon timeout, horizon unavailable or DB error after horizon accepted
+// it). The transaction may still be applied so its result is decided by
+// TransactionRecoverer.
+func submitTransaction(
+	transactionSubmitter submitter.TransactionSubmitterInterface,
+	source string,
+	operation, memo interface{},
+) (response horizon.SubmitTransactionResponse, pending bool, err error) {
+	response, err = transactionSubmitter.SubmitTransaction(context.Background(), source, operation, memo)
+	pending = err != nil && response.SentTransactionId != nil
+	return
+}
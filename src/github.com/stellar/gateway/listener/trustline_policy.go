@@ -0,0 +1,137 @@
+package listener
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/stellar/gateway/config"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/hooks/signature"
+	"github.com/stellar/gateway/horizon"
+)
+
+// TrustlinePolicy decides if a new trustline should be authorized. reason
+// is saved with rejected trustlines. Returned error stops the operations
+// stream until the policy succeeds.
+type TrustlinePolicy interface {
+	ShouldAuthorize(operation horizon.OperationResponse) (authorize bool, reason string, err error)
+}
+
+// NewTrustlinePolicy returns policy configured in `trustlines.policy`.
+func NewTrustlinePolicy(config *config.Config, repository db.RepositoryInterface, now func() time.Time) (policy TrustlinePolicy, err error) {
+	switch config.Trustlines.Policy {
+	case "allowlist":
+		policy = AllowlistPolicy{AccountIds: config.Trustlines.Allowlist}
+	case "db":
+		policy = DBPolicy{Repository: repository}
+	case "hook":
+		hookPolicy := &HookPolicy{
+			Url:    *config.Trustlines.Hook,
+			Client: &http.Client{Timeout: 10 * time.Second},
+			now:    now,
+		}
+		if config.Hooks != nil {
+			hookPolicy.Secrets = config.Hooks.Secrets
+		}
+		policy = hookPolicy
+	default:
+		err = errors.New("Invalid trustlines.policy: " + config.Trustlines.Policy)
+	}
+	return
+}
+
+// AllowlistPolicy authorizes trustlines of accounts from `trustlines.allowlist`.
+type AllowlistPolicy struct {
+	AccountIds []string
+}
+
+func (p AllowlistPolicy) ShouldAuthorize(operation horizon.OperationResponse) (authorize bool, reason string, err error) {
+	for _, accountId := range p.AccountIds {
+		if accountId == operation.Trustor {
+			return true, "", nil
+		}
+	}
+	return false, "account_not_allowed", nil
+}
+
+// DBPolicy authorizes trustlines of accounts added to TrustlineAllowlist
+// table.
+type DBPolicy struct {
+	Repository db.RepositoryInterface
+}
+
+func (p DBPolicy) ShouldAuthorize(operation horizon.OperationResponse) (authorize bool, reason string, err error) {
+	authorize, err = p.Repository.IsTrustlineAllowed(operation.Trustor, operation.AssetCode)
+	if err == nil && !authorize {
+		reason = "account_not_allowed"
+	}
+	return
+}
+
+// HookPolicy asks `trustlines.hook` if a trustline should be authorized.
+// Requests are signed the same way as other hooks.
+type HookPolicy struct {
+	Url     string
+	Client  *http.Client
+	Secrets []string
+	now     func() time.Time
+}
+
+// HookPolicyResponse is a response expected from `trustlines.hook`.
+type HookPolicyResponse struct {
+	Authorize bool   `json:"authorize"`
+	Reason    string `json:"reason"`
+}
+
+func (p *HookPolicy) ShouldAuthorize(operation horizon.OperationResponse) (authorize bool, reason string, err error) {
+	payload := url.Values{
+		"id":           {operation.Id},
+		"account_id":   {operation.Trustor},
+		"asset_code":   {operation.AssetCode},
+		"asset_issuer": {operation.AssetIssuer},
+		"limit":        {operation.Limit},
+	}.Encode()
+
+	req, err := http.NewRequest("POST", p.Url, strings.NewReader(payload))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if len(p.Secrets) > 0 {
+		signature.SetHeaders(req.Header, p.Secrets, p.now(), []byte(payload))
+	}
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return
+	}
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("Error response from trustlines hook: %d %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		return
+	}
+
+	var response HookPolicyResponse
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		err = fmt.Errorf("Invalid response from trustlines hook: %s", err)
+		return
+	}
+
+	if !response.Authorize && response.Reason == "" {
+		response.Reason = "rejected_by_hook"
+	}
+	return response.Authorize, response.Reason, nil
+}
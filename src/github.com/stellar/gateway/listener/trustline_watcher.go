@@ -0,0 +1,283 @@
+package listener
+
+import (
+	"context"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/stellar/gateway/config"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/submitter"
+	"github.com/stellar/go-stellar-base/amount"
+	b "github.com/stellar/go-stellar-base/build"
+)
+
+// TrustlineWatcher authorizes new trustlines to `assets` of the issuing
+// account accepted by TrustlinePolicy. Horizon lists change_trust operations
+// only in the trustor's operations so all operations of the network are
+// streamed.
+type TrustlineWatcher struct {
+	config               *config.Config
+	entityManager        db.EntityManagerInterface
+	horizon              horizon.HorizonInterface
+	log                  *logrus.Entry
+	policy               TrustlinePolicy
+	repository           db.RepositoryInterface
+	transactionSubmitter submitter.TransactionSubmitterInterface
+	now                  func() time.Time
+	// CursorSaveInterval is a minimum time between saving the cursor of
+	// operations that are not change_trust of watched assets
+	CursorSaveInterval time.Duration
+	cursorSavedAt      time.Time
+}
+
+func NewTrustlineWatcher(
+	config *config.Config,
+	entityManager db.EntityManagerInterface,
+	horizon horizon.HorizonInterface,
+	repository db.RepositoryInterface,
+	transactionSubmitter submitter.TransactionSubmitterInterface,
+	now func() time.Time,
+) (w *TrustlineWatcher, err error) {
+	policy, err := NewTrustlinePolicy(config, repository, now)
+	if err != nil {
+		return
+	}
+
+	w = &TrustlineWatcher{
+		config:               config,
+		entityManager:        entityManager,
+		horizon:              horizon,
+		policy:               policy,
+		repository:           repository,
+		transactionSubmitter: transactionSubmitter,
+		now:                  now,
+		CursorSaveInterval:   time.Minute,
+		log: logrus.WithFields(logrus.Fields{
+			"service": "TrustlineWatcher",
+		}),
+	}
+	return
+}
+
+// TrustlinesStreamName returns name of the StreamCursor of operations
+// watched for trustlines to assets of a given issuing account.
+func TrustlinesStreamName(issuingAccountId string) string {
+	return "trustlines:" + issuingAccountId
+}
+
+// Watch starts streaming operations in a goroutine. The first time the
+// stream starts from the current ledger, later from the saved cursor.
+func (w *TrustlineWatcher) Watch() (err error) {
+	cursor, err := w.lastCursor()
+	if err != nil {
+		w.log.Error("Could not load last cursor from the DB")
+		return
+	}
+
+	w.log.WithFields(logrus.Fields{"cursor": cursor}).Info("Started watching trustlines")
+
+	go func() {
+		for {
+			err := w.horizon.StreamOperations(context.Background(), &cursor, w.onOperation)
+			if err != nil {
+				w.log.Error("Error while streaming: ", err)
+				w.log.Info("Sleeping...")
+				time.Sleep(10 * time.Second)
+			}
+			w.log.Info("Streaming connection closed. Restarting...")
+
+			lastCursor, err := w.lastCursor()
+			if err != nil {
+				w.log.Error("Could not load last cursor from the DB: ", err)
+				continue
+			}
+			cursor = lastCursor
+		}
+	}()
+	return
+}
+
+// lastCursor returns saved cursor of the operations stream or "now" when
+// it was not saved yet.
+func (w *TrustlineWatcher) lastCursor() (cursor string, err error) {
+	streamCursor, err := w.repository.GetStreamCursor(TrustlinesStreamName(w.config.Accounts.GetIssuingAccountId()))
+	if err != nil {
+		return
+	}
+	if streamCursor == nil {
+		return "now", nil
+	}
+	return streamCursor.Cursor, nil
+}
+
+// streamCursor returns StreamCursor of the operations stream set to cursor.
+// It's not saved in the DB.
+func (w *TrustlineWatcher) streamCursor(cursor string) (streamCursor *db.StreamCursor, err error) {
+	name := TrustlinesStreamName(w.config.Accounts.GetIssuingAccountId())
+	streamCursor, err = w.repository.GetStreamCursor(name)
+	if err != nil {
+		w.log.Error("Error loading stream cursor from the DB")
+		return
+	}
+	if streamCursor == nil {
+		streamCursor = &db.StreamCursor{Name: name}
+	}
+
+	streamCursor.Cursor = cursor
+	streamCursor.UpdatedAt = w.now()
+	return
+}
+
+// onOperation processes an operation from the stream. Cursor is saved in
+// the same DB transaction as the authorization. Cursor of other operations
+// is saved at most once per CursorSaveInterval.
+func (w *TrustlineWatcher) onOperation(operation horizon.OperationResponse) (err error) {
+	authorization, err := w.ProcessOperation(operation)
+	if err != nil {
+		return
+	}
+
+	if authorization == nil && w.now().Sub(w.cursorSavedAt) < w.CursorSaveInterval {
+		return
+	}
+
+	streamCursor, err := w.streamCursor(operation.PagingToken)
+	if err != nil {
+		return
+	}
+
+	if authorization == nil {
+		err = w.entityManager.Persist(streamCursor)
+	} else {
+		err = w.entityManager.PersistAll(authorization, streamCursor)
+	}
+	if err != nil {
+		w.log.Error("Error saving trustline authorization to the DB")
+		return
+	}
+	w.cursorSavedAt = w.now()
+	return
+}
+
+// ProcessOperation authorizes the trustline created by change_trust
+// operation when the policy accepts it. Returned TrustlineAuthorization is
+// not saved in the DB. nil is returned for other operations and operations
+// that have already been processed.
+func (w *TrustlineWatcher) ProcessOperation(operation horizon.OperationResponse) (authorization *db.TrustlineAuthorization, err error) {
+	// Older horizon versions do not send trustor
+	if operation.Trustor == "" {
+		operation.Trustor = operation.SourceAccount
+	}
+
+	if !w.isWatched(operation) {
+		return
+	}
+
+	log := w.log.WithFields(logrus.Fields{
+		"id":        operation.Id,
+		"accountId": operation.Trustor,
+		"assetCode": operation.AssetCode,
+	})
+
+	existing, err := w.repository.GetTrustlineAuthorizationByOperationId(operation.Id)
+	if err != nil {
+		log.Error("Error loading trustline authorization from the DB")
+		return
+	}
+	if existing != nil {
+		log.Info("Trustline already processed")
+		return
+	}
+
+	authorize, reason, err := w.policy.ShouldAuthorize(operation)
+	if err != nil {
+		log.WithFields(logrus.Fields{"err": err}).Error("Error from trustline policy")
+		return
+	}
+
+	authorization = &db.TrustlineAuthorization{
+		OperationId: operation.Id,
+		AccountId:   operation.Trustor,
+		AssetCode:   operation.AssetCode,
+		CreatedAt:   w.now(),
+	}
+	if reason != "" {
+		authorization.Reason = &reason
+	}
+
+	if !authorize {
+		log.WithFields(logrus.Fields{"reason": reason}).Info("Trustline rejected")
+		authorization.Status = "rejected"
+		return
+	}
+
+	w.authorize(authorization)
+	return
+}
+
+// isWatched returns true for change_trust operations creating (or changing)
+// trustlines to `assets` of the issuing account.
+func (w *TrustlineWatcher) isWatched(operation horizon.OperationResponse) bool {
+	if operation.Type != "change_trust" || operation.AssetIssuer != w.config.Accounts.GetIssuingAccountId() {
+		return false
+	}
+
+	// Trustline removed
+	limit, err := amount.Parse(operation.Limit)
+	if err != nil || limit == 0 {
+		return false
+	}
+
+	for _, code := range w.config.Assets {
+		if code == operation.AssetCode {
+			return true
+		}
+	}
+	return false
+}
+
+// authorize submits allow_trust operation from the authorizing account. The
+// result is saved in authorization Status, Reason, TransactionHash and
+// TransactionId.
+func (w *TrustlineWatcher) authorize(authorization *db.TrustlineAuthorization) {
+	log := w.log.WithFields(logrus.Fields{"id": authorization.OperationId})
+	status := "failed"
+	defer func() { authorization.Status = status }()
+
+	operation := b.AllowTrust(
+		b.Trustor{authorization.AccountId},
+		b.Authorize{true},
+		b.AllowTrustAsset{authorization.AssetCode},
+	)
+
+	response, pending, err := submitTransaction(w.transactionSubmitter, w.config.Accounts.GetAuthorizingAccountId(), operation, nil)
+	authorization.TransactionId = response.SentTransactionId
+	if err != nil {
+		log.WithFields(logrus.Fields{"err": err}).Error("Error submitting allow_trust")
+		reason := "submission_error"
+		if pending {
+			status = "pending"
+		}
+		authorization.Reason = &reason
+		return
+	}
+
+	if response.Ledger == nil {
+		log.WithFields(logrus.Fields{"errors": response.Errors}).Error("allow_trust transaction failed")
+		reason := "transaction_failed"
+		if response.Errors != nil {
+			reason = response.Errors.TransactionErrorCode
+			if response.Errors.OperationErrorCode != "" {
+				reason = response.Errors.OperationErrorCode
+			}
+		}
+		authorization.Reason = &reason
+		return
+	}
+
+	log.WithFields(logrus.Fields{"hash": response.Hash}).Info("Trustline authorized")
+	status = "authorized"
+	authorization.TransactionHash = &response.Hash
+}
@@ -0,0 +1,370 @@
+package listener
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stellar/gateway/config"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/mocks"
+	b "github.com/stellar/go-stellar-base/build"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTrustlineWatcher(t *testing.T) {
+	mockEntityManager := new(mocks.MockEntityManager)
+	mockHorizon := new(mocks.MockHorizon)
+	mockRepository := new(mocks.MockRepository)
+	mockTransactionSubmitter := new(mocks.MockTransactionSubmitter)
+
+	IssuingSeed := "SC34WILLHVADXMP6ACPMIRA6TRAWJMVCLPFNW7S6MUMXJVLAZUC4EWHP"
+	IssuingAccountId := "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR"
+	TrustorId := "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB"
+
+	config := &config.Config{
+		Assets: []string{"USD", "EUR"},
+		Accounts: &config.Accounts{
+			IssuingSeed:     &IssuingSeed,
+			AuthorizingSeed: &IssuingSeed,
+		},
+		Trustlines: &config.Trustlines{
+			Policy:    "allowlist",
+			Allowlist: []string{TrustorId},
+		},
+	}
+
+	trustlineWatcher, err := NewTrustlineWatcher(
+		config,
+		mockEntityManager,
+		mockHorizon,
+		mockRepository,
+		mockTransactionSubmitter,
+		mocks.Now,
+	)
+	assert.Nil(t, err)
+
+	Convey("TrustlineWatcher", t, func() {
+		mocks.PredefinedTime = time.Now()
+		mockRepository.ExpectedCalls = nil
+		mockEntityManager.ExpectedCalls = nil
+		mockTransactionSubmitter.ExpectedCalls = nil
+
+		operation := horizon.OperationResponse{
+			Id:            "7",
+			Type:          "change_trust",
+			PagingToken:   "700",
+			SourceAccount: TrustorId,
+			AssetType:     "credit_alphanum4",
+			AssetCode:     "USD",
+			AssetIssuer:   IssuingAccountId,
+			Trustor:       TrustorId,
+			Trustee:       IssuingAccountId,
+			Limit:         "1000.0000000",
+		}
+
+		allowTrust := b.AllowTrust(
+			b.Trustor{TrustorId},
+			b.Authorize{true},
+			b.AllowTrustAsset{"USD"},
+		)
+
+		Convey("When operation is not watched", func() {
+			submitCalls := len(mockTransactionSubmitter.Calls)
+
+			Convey("it should skip other operations", func() {
+				operation.Type = "payment"
+				authorization, err := trustlineWatcher.ProcessOperation(operation)
+				assert.Nil(t, err)
+				assert.Nil(t, authorization)
+			})
+
+			Convey("it should skip assets of other issuers", func() {
+				operation.AssetIssuer = "GBIHSMPXC2KJ3NJVHEYTG3KCHYEUQRT45X6AWYWXMAXZOAX4F5LFZYYQ"
+				authorization, err := trustlineWatcher.ProcessOperation(operation)
+				assert.Nil(t, err)
+				assert.Nil(t, authorization)
+			})
+
+			Convey("it should skip assets not in config", func() {
+				operation.AssetCode = "BTC"
+				authorization, err := trustlineWatcher.ProcessOperation(operation)
+				assert.Nil(t, err)
+				assert.Nil(t, authorization)
+			})
+
+			Convey("it should skip removed trustlines", func() {
+				operation.Limit = "0.0000000"
+				authorization, err := trustlineWatcher.ProcessOperation(operation)
+				assert.Nil(t, err)
+				assert.Nil(t, authorization)
+			})
+
+			assert.Equal(t, submitCalls, len(mockTransactionSubmitter.Calls))
+		})
+
+		Convey("When trustline has been already processed", func() {
+			mockRepository.On("GetTrustlineAuthorizationByOperationId", "7").Return(&db.TrustlineAuthorization{Status: "authorized"}, nil).Once()
+
+			Convey("it should skip it", func() {
+				authorization, err := trustlineWatcher.ProcessOperation(operation)
+				assert.Nil(t, err)
+				assert.Nil(t, authorization)
+				mockRepository.AssertExpectations(t)
+			})
+		})
+
+		Convey("When trustline is allowed by policy", func() {
+			mockRepository.On("GetTrustlineAuthorizationByOperationId", "7").Return((*db.TrustlineAuthorization)(nil), nil).Once()
+
+			Convey("it should authorize it", func() {
+				ledger := uint64(100)
+				mockTransactionSubmitter.On("SubmitTransaction", IssuingAccountId, allowTrust, nil).Return(
+					horizon.SubmitTransactionResponse{Hash: "ab", Ledger: &ledger},
+					nil,
+				).Once()
+
+				authorization, err := trustlineWatcher.ProcessOperation(operation)
+				assert.Nil(t, err)
+				assert.Equal(t, "authorized", authorization.Status)
+				assert.Equal(t, TrustorId, authorization.AccountId)
+				assert.Equal(t, "USD", authorization.AssetCode)
+				assert.Equal(t, "ab", *authorization.TransactionHash)
+				assert.Equal(t, mocks.PredefinedTime, authorization.CreatedAt)
+				mockTransactionSubmitter.AssertExpectations(t)
+			})
+
+			Convey("it should use source account when trustor is not sent", func() {
+				operation.Trustor = ""
+				ledger := uint64(100)
+				mockTransactionSubmitter.On("SubmitTransaction", IssuingAccountId, allowTrust, nil).Return(
+					horizon.SubmitTransactionResponse{Hash: "ab", Ledger: &ledger},
+					nil,
+				).Once()
+
+				authorization, err := trustlineWatcher.ProcessOperation(operation)
+				assert.Nil(t, err)
+				assert.Equal(t, "authorized", authorization.Status)
+				assert.Equal(t, TrustorId, authorization.AccountId)
+			})
+
+			Convey("it should save error code when transaction failed", func() {
+				mockTransactionSubmitter.On("SubmitTransaction", IssuingAccountId, allowTrust, nil).Return(
+					horizon.SubmitTransactionResponse{Errors: &horizon.SubmitTransactionResponseError{
+						TransactionErrorCode: "transaction_failed",
+						OperationErrorCode:   "allow_trust_trust_not_required",
+					}},
+					nil,
+				).Once()
+
+				authorization, err := trustlineWatcher.ProcessOperation(operation)
+				assert.Nil(t, err)
+				assert.Equal(t, "failed", authorization.Status)
+				assert.Equal(t, "allow_trust_trust_not_required", *authorization.Reason)
+				assert.Nil(t, authorization.TransactionHash)
+			})
+
+			Convey("it should leave authorization pending when submission timed out", func() {
+				sentTransactionId := int64(5)
+				mockTransactionSubmitter.On("SubmitTransaction", IssuingAccountId, allowTrust, nil).Return(
+					horizon.SubmitTransactionResponse{SentTransactionId: &sentTransactionId},
+					horizon.ErrTimeout,
+				).Once()
+
+				authorization, err := trustlineWatcher.ProcessOperation(operation)
+				assert.Nil(t, err)
+				assert.Equal(t, "pending", authorization.Status)
+				assert.Equal(t, sentTransactionId, *authorization.TransactionId)
+			})
+
+			Convey("it should leave authorization pending when horizon was unavailable after the transaction was saved", func() {
+				sentTransactionId := int64(5)
+				mockTransactionSubmitter.On("SubmitTransaction", IssuingAccountId, allowTrust, nil).Return(
+					horizon.SubmitTransactionResponse{SentTransactionId: &sentTransactionId},
+					horizon.ErrUnavailable,
+				).Once()
+
+				authorization, err := trustlineWatcher.ProcessOperation(operation)
+				assert.Nil(t, err)
+				assert.Equal(t, "pending", authorization.Status)
+				assert.Equal(t, sentTransactionId, *authorization.TransactionId)
+			})
+
+			Convey("it should mark authorization as failed when the transaction was not saved", func() {
+				mockTransactionSubmitter.On("SubmitTransaction", IssuingAccountId, allowTrust, nil).Return(
+					horizon.SubmitTransactionResponse{},
+					horizon.ErrUnavailable,
+				).Once()
+
+				authorization, err := trustlineWatcher.ProcessOperation(operation)
+				assert.Nil(t, err)
+				assert.Equal(t, "failed", authorization.Status)
+				assert.Equal(t, "submission_error", *authorization.Reason)
+				assert.Nil(t, authorization.TransactionId)
+			})
+		})
+
+		Convey("When trustline is not allowed by policy", func() {
+			operation.Trustor = "GBIHSMPXC2KJ3NJVHEYTG3KCHYEUQRT45X6AWYWXMAXZOAX4F5LFZYYQ"
+			mockRepository.On("GetTrustlineAuthorizationByOperationId", "7").Return((*db.TrustlineAuthorization)(nil), nil).Once()
+			submitCalls := len(mockTransactionSubmitter.Calls)
+
+			Convey("it should reject it", func() {
+				authorization, err := trustlineWatcher.ProcessOperation(operation)
+				assert.Nil(t, err)
+				assert.Equal(t, "rejected", authorization.Status)
+				assert.Equal(t, "account_not_allowed", *authorization.Reason)
+				assert.Equal(t, submitCalls, len(mockTransactionSubmitter.Calls))
+			})
+		})
+
+		Convey("When streamed operation is processed", func() {
+			id := int64(2)
+			streamCursor := &db.StreamCursor{Id: &id, Name: "trustlines:" + IssuingAccountId, Cursor: "600"}
+			mockRepository.On("GetStreamCursor", "trustlines:"+IssuingAccountId).Return(streamCursor, nil)
+
+			Convey("it should save stream cursor together with the authorization", func() {
+				operation.Trustor = "GBIHSMPXC2KJ3NJVHEYTG3KCHYEUQRT45X6AWYWXMAXZOAX4F5LFZYYQ"
+				mockRepository.On("GetTrustlineAuthorizationByOperationId", "7").Return((*db.TrustlineAuthorization)(nil), nil).Once()
+				mockEntityManager.On("PersistAll", mock.Anything).Run(func(args mock.Arguments) {
+					objects := args.Get(0).([]db.Entity)
+					assert.Equal(t, 2, len(objects))
+					assert.Equal(t, "rejected", objects[0].(*db.TrustlineAuthorization).Status)
+					assert.Equal(t, streamCursor, objects[1])
+				}).Return(nil).Once()
+
+				err := trustlineWatcher.onOperation(operation)
+				assert.Nil(t, err)
+				assert.Equal(t, "700", streamCursor.Cursor)
+				mockEntityManager.AssertExpectations(t)
+			})
+
+			Convey("it should save cursor of other operations once per interval", func() {
+				operation.Type = "payment"
+				mockEntityManager.On("Persist", streamCursor).Return(nil).Once()
+
+				trustlineWatcher.cursorSavedAt = time.Time{}
+				err := trustlineWatcher.onOperation(operation)
+				assert.Nil(t, err)
+				assert.Equal(t, "700", streamCursor.Cursor)
+
+				operation.PagingToken = "800"
+				err = trustlineWatcher.onOperation(operation)
+				assert.Nil(t, err)
+				assert.Equal(t, "700", streamCursor.Cursor)
+				mockEntityManager.AssertExpectations(t)
+			})
+
+			Convey("it should return error when policy fails", func() {
+				trustlineWatcher.policy = DBPolicy{Repository: mockRepository}
+				defer func() { trustlineWatcher.policy = AllowlistPolicy{AccountIds: config.Trustlines.Allowlist} }()
+				mockRepository.On("GetTrustlineAuthorizationByOperationId", "7").Return((*db.TrustlineAuthorization)(nil), nil).Once()
+				mockRepository.On("IsTrustlineAllowed", TrustorId, "USD").Return(false, errors.New("DB error")).Once()
+				persistCalls := len(mockEntityManager.Calls)
+
+				err := trustlineWatcher.onOperation(operation)
+				assert.NotNil(t, err)
+				assert.Equal(t, persistCalls, len(mockEntityManager.Calls))
+				assert.Equal(t, "600", streamCursor.Cursor)
+			})
+		})
+	})
+}
+
+func TestTrustlinePolicy(t *testing.T) {
+	operation := horizon.OperationResponse{
+		Id:          "7",
+		Type:        "change_trust",
+		AssetCode:   "USD",
+		AssetIssuer: "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR",
+		Trustor:     "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB",
+		Limit:       "1000.0000000",
+	}
+
+	Convey("DBPolicy", t, func() {
+		mockRepository := new(mocks.MockRepository)
+		policy := DBPolicy{Repository: mockRepository}
+
+		Convey("it should authorize accounts from TrustlineAllowlist", func() {
+			mockRepository.On("IsTrustlineAllowed", operation.Trustor, "USD").Return(true, nil).Once()
+			authorize, reason, err := policy.ShouldAuthorize(operation)
+			assert.Nil(t, err)
+			assert.True(t, authorize)
+			assert.Equal(t, "", reason)
+		})
+
+		Convey("it should reject other accounts", func() {
+			mockRepository.On("IsTrustlineAllowed", operation.Trustor, "USD").Return(false, nil).Once()
+			authorize, reason, err := policy.ShouldAuthorize(operation)
+			assert.Nil(t, err)
+			assert.False(t, authorize)
+			assert.Equal(t, "account_not_allowed", reason)
+		})
+	})
+
+	Convey("HookPolicy", t, func() {
+		var respond func(w http.ResponseWriter, r *http.Request)
+		hookServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			respond(w, r)
+		}))
+		defer hookServer.Close()
+
+		policy := &HookPolicy{
+			Url:     hookServer.URL,
+			Client:  http.DefaultClient,
+			Secrets: []string{"test-secret-value-1"},
+			now:     time.Now,
+		}
+
+		Convey("it should send signed trustline details", func() {
+			respond = func(w http.ResponseWriter, r *http.Request) {
+				r.ParseForm()
+				assert.Equal(t, "7", r.PostForm.Get("id"))
+				assert.Equal(t, operation.Trustor, r.PostForm.Get("account_id"))
+				assert.Equal(t, "USD", r.PostForm.Get("asset_code"))
+				assert.Equal(t, operation.AssetIssuer, r.PostForm.Get("asset_issuer"))
+				assert.NotEqual(t, "", r.Header.Get("X-Gateway-Signature"))
+				w.Write([]byte(`{"authorize": true}`))
+			}
+
+			authorize, _, err := policy.ShouldAuthorize(operation)
+			assert.Nil(t, err)
+			assert.True(t, authorize)
+		})
+
+		Convey("it should return reason of rejection", func() {
+			respond = func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"authorize": false, "reason": "kyc_pending"}`))
+			}
+
+			authorize, reason, err := policy.ShouldAuthorize(operation)
+			assert.Nil(t, err)
+			assert.False(t, authorize)
+			assert.Equal(t, "kyc_pending", reason)
+		})
+
+		Convey("it should use default reason", func() {
+			respond = func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"authorize": false}`))
+			}
+
+			_, reason, err := policy.ShouldAuthorize(operation)
+			assert.Nil(t, err)
+			assert.Equal(t, "rejected_by_hook", reason)
+		})
+
+		Convey("it should return error when hook fails", func() {
+			respond = func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			}
+
+			_, _, err := policy.ShouldAuthorize(operation)
+			assert.Equal(t, "Error response from trustlines hook: 503 unavailable", err.Error())
+		})
+	})
+}
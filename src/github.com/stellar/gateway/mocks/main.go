@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"context"
 	"time"
 
 	"github.com/stellar/gateway/db"
@@ -17,26 +18,47 @@ func (m *MockEntityManager) Persist(object db.Entity) (err error) {
 	return a.Error(0)
 }
 
+func (m *MockEntityManager) PersistAll(objects ...db.Entity) (err error) {
+	a := m.Called(objects)
+	return a.Error(0)
+}
+
+// MockHorizon does not pass ctx to mock.Called so expectations don't need to match it
 type MockHorizon struct {
 	mock.Mock
 }
 
-func (m *MockHorizon) LoadAccount(accountId string) (response horizon.AccountResponse, err error) {
+func (m *MockHorizon) LoadAccount(ctx context.Context, accountId string) (response horizon.AccountResponse, err error) {
 	a := m.Called(accountId)
 	return a.Get(0).(horizon.AccountResponse), a.Error(1)
 }
 
-func (m *MockHorizon) LoadMemo(p *horizon.PaymentResponse) (err error) {
+func (m *MockHorizon) LoadMemo(ctx context.Context, p *horizon.PaymentResponse) (err error) {
 	a := m.Called(p)
 	return a.Error(0)
 }
 
-func (m *MockHorizon) StreamPayments(accountId string, cursor *string, onPaymentHandler horizon.PaymentHandler) (err error) {
+func (m *MockHorizon) LoadTransaction(ctx context.Context, hash string) (response horizon.TransactionResponse, err error) {
+	a := m.Called(hash)
+	return a.Get(0).(horizon.TransactionResponse), a.Error(1)
+}
+
+func (m *MockHorizon) LoadPayments(ctx context.Context, accountId string, cursor string, limit int) (payments []horizon.PaymentResponse, err error) {
+	a := m.Called(accountId, cursor, limit)
+	return a.Get(0).([]horizon.PaymentResponse), a.Error(1)
+}
+
+func (m *MockHorizon) StreamPayments(ctx context.Context, accountId string, cursor *string, onPaymentHandler horizon.PaymentHandler) (err error) {
 	a := m.Called(accountId, cursor, onPaymentHandler)
 	return a.Error(0)
 }
 
-func (m *MockHorizon) SubmitTransaction(txeBase64 string) (response horizon.SubmitTransactionResponse, err error) {
+func (m *MockHorizon) StreamOperations(ctx context.Context, cursor *string, onOperationHandler horizon.OperationHandler) (err error) {
+	a := m.Called(cursor, onOperationHandler)
+	return a.Error(0)
+}
+
+func (m *MockHorizon) SubmitTransaction(ctx context.Context, txeBase64 string) (response horizon.SubmitTransactionResponse, err error) {
 	a := m.Called(txeBase64)
 	return a.Get(0).(horizon.SubmitTransactionResponse), a.Error(1)
 }
@@ -45,20 +67,91 @@ type MockRepository struct {
 	mock.Mock
 }
 
-func (m *MockRepository) GetLastCursorValue() (cursor *string, err error) {
-	a := m.Called()
+func (m *MockRepository) GetLastCursorValue(accountId string) (cursor *string, err error) {
+	a := m.Called(accountId)
 	return a.Get(0).(*string), a.Error(1)
 }
 
+func (m *MockRepository) GetStreamCursor(name string) (cursor *db.StreamCursor, err error) {
+	a := m.Called(name)
+	return a.Get(0).(*db.StreamCursor), a.Error(1)
+}
+
+func (m *MockRepository) GetReceivedPaymentByOperationId(operationId string) (payment *db.ReceivedPayment, err error) {
+	a := m.Called(operationId)
+	return a.Get(0).(*db.ReceivedPayment), a.Error(1)
+}
+
+func (m *MockRepository) GetReceivedPayments(filter db.ReceivedPaymentsFilter) (payments []db.ReceivedPayment, err error) {
+	a := m.Called(filter)
+	return a.Get(0).([]db.ReceivedPayment), a.Error(1)
+}
+
+func (m *MockRepository) GetSendingTransactions(submittedBefore time.Time) (transactions []db.SentTransaction, err error) {
+	a := m.Called(submittedBefore)
+	return a.Get(0).([]db.SentTransaction), a.Error(1)
+}
+
+func (m *MockRepository) GetIdempotencyKey(key string) (idempotencyKey *db.IdempotencyKey, err error) {
+	a := m.Called(key)
+	return a.Get(0).(*db.IdempotencyKey), a.Error(1)
+}
+
+func (m *MockRepository) GetSentTransactionById(id int64) (transaction *db.SentTransaction, err error) {
+	a := m.Called(id)
+	return a.Get(0).(*db.SentTransaction), a.Error(1)
+}
+
+func (m *MockRepository) GetSentTransactions(filter db.SentTransactionsFilter) (transactions []db.SentTransaction, err error) {
+	a := m.Called(filter)
+	return a.Get(0).([]db.SentTransaction), a.Error(1)
+}
+
+func (m *MockRepository) GetPendingHookDeliveries(before time.Time, limit int) (deliveries []db.HookDelivery, err error) {
+	a := m.Called(before, limit)
+	return a.Get(0).([]db.HookDelivery), a.Error(1)
+}
+
+func (m *MockRepository) GetHookDeliveryById(id int64) (delivery *db.HookDelivery, err error) {
+	a := m.Called(id)
+	return a.Get(0).(*db.HookDelivery), a.Error(1)
+}
+
+func (m *MockRepository) GetHookDeliveries(filter db.HookDeliveriesFilter) (deliveries []db.HookDelivery, err error) {
+	a := m.Called(filter)
+	return a.Get(0).([]db.HookDelivery), a.Error(1)
+}
+
+func (m *MockRepository) GetTrustlineAuthorizationByOperationId(operationId string) (authorization *db.TrustlineAuthorization, err error) {
+	a := m.Called(operationId)
+	return a.Get(0).(*db.TrustlineAuthorization), a.Error(1)
+}
+
+func (m *MockRepository) GetTrustlineAuthorizations(filter db.TrustlineAuthorizationsFilter) (authorizations []db.TrustlineAuthorization, err error) {
+	a := m.Called(filter)
+	return a.Get(0).([]db.TrustlineAuthorization), a.Error(1)
+}
+
+func (m *MockRepository) IsTrustlineAllowed(accountId, assetCode string) (allowed bool, err error) {
+	a := m.Called(accountId, assetCode)
+	return a.Bool(0), a.Error(1)
+}
+
 type MockTransactionSubmitter struct {
 	mock.Mock
 }
 
-func (ts *MockTransactionSubmitter) SubmitTransaction(seed string, operation, memo interface{}) (response horizon.SubmitTransactionResponse, err error) {
+// SubmitTransaction does not pass ctx to mock.Called so expectations don't need to match it
+func (ts *MockTransactionSubmitter) SubmitTransaction(ctx context.Context, seed string, operation, memo interface{}) (response horizon.SubmitTransactionResponse, err error) {
 	a := ts.Called(seed, operation, memo)
 	return a.Get(0).(horizon.SubmitTransactionResponse), a.Error(1)
 }
 
+func (ts *MockTransactionSubmitter) SignTransaction(source string, operation, memo interface{}) (txeB64 string, err error) {
+	a := ts.Called(source, operation, memo)
+	return a.String(0), a.Error(1)
+}
+
 var PredefinedTime time.Time
 
 func Now() time.Time {
@@ -0,0 +1,96 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	log "github.com/Sirupsen/logrus"
+	"time"
+
+	"github.com/stellar/gateway/config"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/listener"
+	"github.com/stellar/gateway/submitter"
+)
+
+// ReplayPayments processes historical payments of a receiving account again.
+// It can be run while the gateway server is running: hook deliveries are
+// saved to the DB and sent by the server's deliverer. Refunds are never sent
+// by replay because its TransactionSubmitter would use the same sequence
+// numbers as the server's one.
+func ReplayPayments(config config.Config, options listener.ReplayOptions) (err error) {
+	if config.Refunds != nil {
+		log.Warn("Refunds are not sent when replaying payments")
+		config.Refunds = nil
+	}
+
+	paymentListener, err := newCommandPaymentListener(config)
+	if err != nil {
+		return
+	}
+
+	lastCursor, count, err := paymentListener.Replay(context.Background(), options)
+	log.WithFields(log.Fields{
+		"count":  count,
+		"cursor": lastCursor,
+	}).Print("Replayed payments")
+	return
+}
+
+// ResetCursor sets the cursor the payments stream of a receiving account is
+// started from. The gateway server must be stopped, otherwise the cursor is
+// overwritten when the next payment is processed. Only the StreamCursor of
+// the account is changed so PaymentListener is not needed.
+func ResetCursor(config config.Config, accountId, cursor string) (err error) {
+	found := false
+	for _, account := range config.ReceivingAccounts() {
+		found = found || account.AccountId == accountId
+	}
+	if !found {
+		return errors.New("Not a receiving account: " + accountId)
+	}
+
+	entityManager, err := db.NewEntityManager(config.Database.Type, config.Database.Url)
+	if err != nil {
+		return
+	}
+	repository, err := db.NewRepository(config.Database.Type, config.Database.Url)
+	if err != nil {
+		return
+	}
+
+	name := listener.StreamName(accountId)
+	streamCursor, err := repository.GetStreamCursor(name)
+	if err != nil {
+		return
+	}
+	if streamCursor == nil {
+		streamCursor = &db.StreamCursor{Name: name}
+	}
+
+	streamCursor.Cursor = cursor
+	streamCursor.UpdatedAt = time.Now()
+	return entityManager.Persist(streamCursor)
+}
+
+// newCommandPaymentListener creates PaymentListener used by CLI commands.
+// No accounts are initialized in its TransactionSubmitter so it cannot
+// submit transactions.
+func newCommandPaymentListener(config config.Config) (paymentListener listener.PaymentListener, err error) {
+	entityManager, err := db.NewEntityManager(config.Database.Type, config.Database.Url)
+	if err != nil {
+		return
+	}
+	repository, err := db.NewRepository(config.Database.Type, config.Database.Url)
+	if err != nil {
+		return
+	}
+
+	if config.NetworkPassphrase == "" {
+		config.NetworkPassphrase = "Test SDF Network ; September 2015"
+	}
+
+	h := newHorizonPool(config)
+	ts := submitter.NewTransactionSubmitter(h, &entityManager, config.NetworkPassphrase)
+
+	return listener.NewPaymentListener(&config, &entityManager, h, &repository, &ts, time.Now)
+}
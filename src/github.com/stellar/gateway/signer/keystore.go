@@ -0,0 +1,176 @@
+package signer
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/stellar/go-stellar-base/keypair"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// Number of PBKDF2 iterations used to derive encryption key from passphrase
+const keystoreIterations = 100000
+
+// Keystore is a JSON file containing secret seeds encrypted with a passphrase
+// (AES-256-GCM, key derived with PBKDF2-HMAC-SHA256).
+type Keystore struct {
+	Keys []KeystoreKey `json:"keys"`
+}
+
+type KeystoreKey struct {
+	Address    string `json:"address"`
+	Salt       string `json:"salt"`
+	Nonce      string `json:"nonce"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// LoadKeystore decrypts all seeds in keystore file and returns their signers.
+func LoadKeystore(path, passphrase string) (signers []Signer, err error) {
+	keystore, err := readKeystore(path)
+	if err != nil {
+		return
+	}
+
+	for _, key := range keystore.Keys {
+		var seed string
+		seed, err = key.decrypt(passphrase)
+		if err != nil {
+			return
+		}
+
+		var signer *SeedSigner
+		signer, err = NewSeedSigner(seed)
+		if err != nil {
+			return
+		}
+
+		if signer.Address() != key.Address {
+			err = errors.New("Keystore key does not match its address: " + key.Address)
+			return
+		}
+
+		signers = append(signers, signer)
+	}
+	return
+}
+
+// AddToKeystore encrypts seed with passphrase and adds it to keystore file.
+// The file is created when it does not exist.
+func AddToKeystore(path, seed, passphrase string) (err error) {
+	kp, err := keypair.Parse(seed)
+	if err != nil {
+		return
+	}
+
+	if _, ok := kp.(*keypair.Full); !ok {
+		err = errors.New("Secret seed expected")
+		return
+	}
+
+	keystore, err := readKeystore(path)
+	if os.IsNotExist(err) {
+		err = nil
+	}
+	if err != nil {
+		return
+	}
+
+	for _, key := range keystore.Keys {
+		if key.Address == kp.Address() {
+			err = errors.New("Keystore already contains " + kp.Address())
+			return
+		}
+	}
+
+	salt := make([]byte, 32)
+	_, err = io.ReadFull(rand.Reader, salt)
+	if err != nil {
+		return
+	}
+
+	gcm, err := newKeystoreCipher(passphrase, salt)
+	if err != nil {
+		return
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return
+	}
+
+	keystore.Keys = append(keystore.Keys, KeystoreKey{
+		Address:    kp.Address(),
+		Salt:       base64.StdEncoding.EncodeToString(salt),
+		Nonce:      base64.StdEncoding.EncodeToString(nonce),
+		Ciphertext: base64.StdEncoding.EncodeToString(gcm.Seal(nil, nonce, []byte(seed), []byte(kp.Address()))),
+	})
+
+	data, err := json.MarshalIndent(keystore, "", "  ")
+	if err != nil {
+		return
+	}
+
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+func readKeystore(path string) (keystore Keystore, err error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(data, &keystore)
+	return
+}
+
+func (key KeystoreKey) decrypt(passphrase string) (seed string, err error) {
+	salt, err := base64.StdEncoding.DecodeString(key.Salt)
+	if err != nil {
+		return
+	}
+
+	nonce, err := base64.StdEncoding.DecodeString(key.Nonce)
+	if err != nil {
+		return
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(key.Ciphertext)
+	if err != nil {
+		return
+	}
+
+	gcm, err := newKeystoreCipher(passphrase, salt)
+	if err != nil {
+		return
+	}
+
+	if len(nonce) != gcm.NonceSize() {
+		err = errors.New("Invalid keystore nonce")
+		return
+	}
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, []byte(key.Address))
+	if err != nil {
+		err = errors.New("Cannot decrypt keystore key (invalid passphrase?): " + key.Address)
+		return
+	}
+
+	seed = string(plaintext)
+	return
+}
+
+func newKeystoreCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(pbkdf2.Key([]byte(passphrase), salt, keystoreIterations, 32, sha256.New))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,82 @@
+package signer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeystore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keystore")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	Convey("Given keystore file", t, func() {
+		path := filepath.Join(dir, "keystore.json")
+		os.Remove(path)
+
+		err := AddToKeystore(path, "SC34WILLHVADXMP6ACPMIRA6TRAWJMVCLPFNW7S6MUMXJVLAZUC4EWHP", "passphrase")
+		assert.Nil(t, err)
+		err = AddToKeystore(path, "SC37TBSIAYKIDQ6GTGLT2HSORLIHZQHBXVFI5P5K4Q5TSHRTRBK3UNWG", "passphrase")
+		assert.Nil(t, err)
+
+		Convey("it should not contain secret seeds in plain text", func() {
+			data, err := ioutil.ReadFile(path)
+			assert.Nil(t, err)
+			assert.NotContains(t, string(data), "SC34WILLHVADXMP6ACPMIRA6TRAWJMVCLPFNW7S6MUMXJVLAZUC4EWHP")
+		})
+
+		Convey("When passphrase is valid", func() {
+			Convey("it should load signers", func() {
+				signers, err := LoadKeystore(path, "passphrase")
+				assert.Nil(t, err)
+				assert.Equal(t, 2, len(signers))
+				assert.Equal(t, "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR", signers[0].Address())
+				assert.Equal(t, "GBQXA3ABGQGTCLEVZIUTDRWWJOQD5LSAEDZAG7GMOGD2HBLWONGUVO4I", signers[1].Address())
+			})
+		})
+
+		Convey("When passphrase is invalid", func() {
+			Convey("it should return error", func() {
+				_, err := LoadKeystore(path, "invalid")
+				assert.NotNil(t, err)
+			})
+		})
+
+		Convey("When keystore was created by a previous version", func() {
+			// Key derived with PBKDF2-HMAC-SHA256 (100000 iterations) of
+			// "passphrase"
+			err := ioutil.WriteFile(path, []byte(`{
+  "keys": [
+    {
+      "address": "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR",
+      "salt": "DzfXOkqq6amtXVBhYrKOw8VnWdUUxNs0MR9jtmVysdQ=",
+      "nonce": "V3XLpZVS+KL4byAd",
+      "ciphertext": "6RoU/Qf4LYyU7B531cc4894M/ilMEAXux40+x1erVnvpIELH5u3Tlt74CqGVZ2hJNY2hCB0xf/rnII22WgbGmIXzTJyrSsuO"
+    }
+  ]
+}`), 0600)
+			assert.Nil(t, err)
+
+			Convey("it should load signers", func() {
+				signers, err := LoadKeystore(path, "passphrase")
+				assert.Nil(t, err)
+				assert.Equal(t, 1, len(signers))
+				assert.Equal(t, "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR", signers[0].Address())
+			})
+		})
+
+		Convey("When seed is already in keystore", func() {
+			Convey("it should return error", func() {
+				err := AddToKeystore(path, "SC34WILLHVADXMP6ACPMIRA6TRAWJMVCLPFNW7S6MUMXJVLAZUC4EWHP", "passphrase")
+				assert.NotNil(t, err)
+			})
+		})
+	})
+}
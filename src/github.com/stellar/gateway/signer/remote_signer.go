@@ -0,0 +1,107 @@
+package signer
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/stellar/go-stellar-base/keypair"
+	"github.com/stellar/go-stellar-base/xdr"
+)
+
+// RemoteSigner delegates signing to a remote HTTP signing service.
+//
+// The service receives POST request with `address` and `hash` (hex encoded)
+// params and must respond with JSON object containing base64 encoded ed25519
+// signature of the hash: `{"signature": "..."}`. When Token is set, it's
+// sent in `Authorization: Bearer` header.
+type RemoteSigner struct {
+	Url     string
+	Token   string
+	Client  *http.Client
+	keypair keypair.KP
+}
+
+type remoteSignerResponse struct {
+	Signature string `json:"signature"`
+}
+
+func NewRemoteSigner(url, address string) (signer *RemoteSigner, err error) {
+	kp, err := keypair.Parse(address)
+	if err != nil {
+		return
+	}
+
+	signer = &RemoteSigner{
+		Url:     url,
+		Client:  &http.Client{Timeout: 10 * time.Second},
+		keypair: kp,
+	}
+	return
+}
+
+func (s *RemoteSigner) Address() string {
+	return s.keypair.Address()
+}
+
+func (s *RemoteSigner) Sign(hash [32]byte) (signature xdr.DecoratedSignature, err error) {
+	params := url.Values{
+		"address": {s.Address()},
+		"hash":    {hex.EncodeToString(hash[:])},
+	}
+	req, err := http.NewRequest("POST", s.Url, strings.NewReader(params.Encode()))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if s.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.Token)
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return
+	}
+
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return
+	}
+
+	if resp.StatusCode != 200 {
+		err = fmt.Errorf("Remote signer StatusCode indicates error: %s", body)
+		return
+	}
+
+	var response remoteSignerResponse
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return
+	}
+
+	rawSignature, err := base64.StdEncoding.DecodeString(response.Signature)
+	if err != nil {
+		return
+	}
+
+	// Do not trust remote service
+	err = s.keypair.Verify(hash[:], rawSignature)
+	if err != nil {
+		err = errors.New("Remote signer returned invalid signature")
+		return
+	}
+
+	signature = xdr.DecoratedSignature{
+		Hint:      xdr.SignatureHint(s.keypair.Hint()),
+		Signature: xdr.Signature(rawSignature),
+	}
+	return
+}
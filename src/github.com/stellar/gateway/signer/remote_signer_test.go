@@ -0,0 +1,97 @@
+package signer
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stellar/go-stellar-base/keypair"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoteSigner(t *testing.T) {
+	// GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR
+	kp, err := keypair.Parse("SC34WILLHVADXMP6ACPMIRA6TRAWJMVCLPFNW7S6MUMXJVLAZUC4EWHP")
+	if err != nil {
+		panic(err)
+	}
+
+	var respond func(w http.ResponseWriter, r *http.Request)
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r)
+	}))
+	defer testServer.Close()
+
+	hash := [32]byte{1, 2, 3}
+
+	Convey("Given remote signer", t, func() {
+		remoteSigner, err := NewRemoteSigner(testServer.URL, kp.Address())
+		assert.Nil(t, err)
+
+		Convey("When signing service returns valid signature", func() {
+			respond = func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, kp.Address(), r.PostFormValue("address"))
+				rawHash, err := hex.DecodeString(r.PostFormValue("hash"))
+				assert.Nil(t, err)
+
+				signature, err := kp.Sign(rawHash)
+				assert.Nil(t, err)
+				w.Write([]byte(`{"signature": "` + base64.StdEncoding.EncodeToString(signature) + `"}`))
+			}
+
+			Convey("it should return decorated signature", func() {
+				expected, err := kp.SignDecorated(hash[:])
+				assert.Nil(t, err)
+
+				signature, err := remoteSigner.Sign(hash)
+				assert.Nil(t, err)
+				assert.Equal(t, expected, signature)
+			})
+		})
+
+		Convey("When token is set", func() {
+			remoteSigner.Token = "secret-token"
+			respond = func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "Bearer secret-token" {
+					http.Error(w, "forbidden", http.StatusForbidden)
+					return
+				}
+				signature, err := kp.Sign(hash[:])
+				assert.Nil(t, err)
+				w.Write([]byte(`{"signature": "` + base64.StdEncoding.EncodeToString(signature) + `"}`))
+			}
+
+			Convey("it should send it in Authorization header", func() {
+				_, err := remoteSigner.Sign(hash)
+				assert.Nil(t, err)
+			})
+		})
+
+		Convey("When signing service returns invalid signature", func() {
+			respond = func(w http.ResponseWriter, r *http.Request) {
+				signature, _ := kp.Sign([]byte("other data"))
+				w.Write([]byte(`{"signature": "` + base64.StdEncoding.EncodeToString(signature) + `"}`))
+			}
+
+			Convey("it should return error", func() {
+				_, err := remoteSigner.Sign(hash)
+				assert.NotNil(t, err)
+				assert.Equal(t, "Remote signer returned invalid signature", err.Error())
+			})
+		})
+
+		Convey("When signing service returns error", func() {
+			respond = func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "forbidden", http.StatusForbidden)
+			}
+
+			Convey("it should return error", func() {
+				_, err := remoteSigner.Sign(hash)
+				assert.NotNil(t, err)
+			})
+		})
+	})
+}
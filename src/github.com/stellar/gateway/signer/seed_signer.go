@@ -0,0 +1,34 @@
+package signer
+
+import (
+	"github.com/stellar/go-stellar-base/keypair"
+	"github.com/stellar/go-stellar-base/xdr"
+)
+
+// SeedSigner signs with a secret seed held in memory.
+type SeedSigner struct {
+	keypair keypair.KP
+}
+
+func NewSeedSigner(seed string) (signer *SeedSigner, err error) {
+	kp, err := keypair.Parse(seed)
+	if err != nil {
+		return
+	}
+
+	if _, ok := kp.(*keypair.Full); !ok {
+		err = keypair.ErrCannotSign
+		return
+	}
+
+	signer = &SeedSigner{keypair: kp}
+	return
+}
+
+func (s *SeedSigner) Address() string {
+	return s.keypair.Address()
+}
+
+func (s *SeedSigner) Sign(hash [32]byte) (xdr.DecoratedSignature, error) {
+	return s.keypair.SignDecorated(hash[:])
+}
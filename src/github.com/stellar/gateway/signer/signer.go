@@ -0,0 +1,120 @@
+package signer
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/stellar/go-stellar-base/build"
+	"github.com/stellar/go-stellar-base/keypair"
+	"github.com/stellar/go-stellar-base/xdr"
+)
+
+// Signer signs transactions on behalf of a single account. Implementations
+// do not need to hold the secret seed of the account in memory.
+type Signer interface {
+	// Address returns ID of the account this Signer signs for
+	Address() string
+	// Sign returns a decorated signature of the transaction hash
+	Sign(hash [32]byte) (xdr.DecoratedSignature, error)
+}
+
+// SignTransaction returns transaction envelope signed by all signers.
+func SignTransaction(tx *build.TransactionBuilder, signers ...Signer) (txe build.TransactionEnvelopeBuilder, err error) {
+	if tx.Err != nil {
+		err = tx.Err
+		return
+	}
+
+	hash, err := tx.Hash()
+	if err != nil {
+		return
+	}
+
+	txe = tx.Sign()
+	if txe.Err != nil {
+		err = txe.Err
+		return
+	}
+
+	for _, signer := range signers {
+		var signature xdr.DecoratedSignature
+		signature, err = signer.Sign(hash)
+		if err != nil {
+			err = fmt.Errorf("Cannot sign transaction with %s: %s", signer.Address(), err)
+			return
+		}
+		txe.E.Signatures = append(txe.E.Signatures, signature)
+	}
+	return
+}
+
+// Registry holds signers of accounts used by the gateway server. It's safe
+// for concurrent use.
+type Registry struct {
+	mutex       sync.RWMutex
+	signers     map[string]Signer
+	remoteUrl   string
+	remoteToken string
+}
+
+// NewRegistry creates a new Registry. When remoteUrl is not empty signing of
+// accounts without a registered Signer is delegated to a remote signing service
+// authenticated with remoteToken (when not empty).
+func NewRegistry(remoteUrl, remoteToken string) (r *Registry) {
+	return &Registry{
+		signers:     make(map[string]Signer),
+		remoteUrl:   remoteUrl,
+		remoteToken: remoteToken,
+	}
+}
+
+func (r *Registry) Add(signer Signer) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.signers[signer.Address()] = signer
+}
+
+// Get returns a Signer for an account with a given address. Remote signers
+// of accounts that were not added are not cached so the Registry does not
+// grow with addresses sent by API callers.
+func (r *Registry) Get(address string) (signer Signer, err error) {
+	r.mutex.RLock()
+	signer, exist := r.signers[address]
+	r.mutex.RUnlock()
+	if exist {
+		return
+	}
+
+	if r.remoteUrl != "" {
+		var remoteSigner *RemoteSigner
+		remoteSigner, err = NewRemoteSigner(r.remoteUrl, address)
+		if err != nil {
+			return nil, err
+		}
+		remoteSigner.Token = r.remoteToken
+		return remoteSigner, nil
+	}
+
+	err = fmt.Errorf("No signer for account %s", address)
+	return
+}
+
+// Resolve returns a Signer for a secret seed or an account ID registered in
+// the Registry (see Get).
+func (r *Registry) Resolve(seedOrAddress string) (signer Signer, err error) {
+	kp, err := keypair.Parse(seedOrAddress)
+	if err != nil {
+		return
+	}
+
+	if _, ok := kp.(*keypair.Full); ok {
+		signer, err = NewSeedSigner(seedOrAddress)
+		if err != nil {
+			return
+		}
+		r.Add(signer)
+		return
+	}
+
+	return r.Get(kp.Address())
+}
@@ -0,0 +1,40 @@
+package signer
+
+import (
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	Convey("Given registry with remote signing service", t, func() {
+		registry := NewRegistry("http://signer.example.com/sign", "token")
+		seedSigner, err := NewSeedSigner("SC34WILLHVADXMP6ACPMIRA6TRAWJMVCLPFNW7S6MUMXJVLAZUC4EWHP")
+		assert.Nil(t, err)
+		registry.Add(seedSigner)
+
+		Convey("it should return added signer", func() {
+			signer, err := registry.Get("GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR")
+			assert.Nil(t, err)
+			assert.Equal(t, seedSigner, signer)
+		})
+
+		Convey("it should not cache remote signers of other accounts", func() {
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					signer, err := registry.Get("GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB")
+					assert.Nil(t, err)
+					assert.IsType(t, &RemoteSigner{}, signer)
+					assert.Equal(t, "token", signer.(*RemoteSigner).Token)
+				}()
+			}
+			wg.Wait()
+			assert.Equal(t, 1, len(registry.signers))
+		})
+	})
+}
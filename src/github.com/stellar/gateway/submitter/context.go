@@ -0,0 +1,22 @@
+package submitter
+
+import "context"
+
+type contextKey int
+
+const idempotencyKeyContextKey contextKey = iota
+
+// WithIdempotencyKey returns a copy of ctx carrying `idempotency_key` of the
+// request. SentTransactions submitted with this context are linked to the key.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey, key)
+}
+
+// IdempotencyKeyFromContext returns the key set by WithIdempotencyKey or nil.
+func IdempotencyKeyFromContext(ctx context.Context) *string {
+	key, ok := ctx.Value(idempotencyKeyContextKey).(string)
+	if !ok {
+		return nil
+	}
+	return &key
+}
@@ -0,0 +1,116 @@
+package submitter
+
+import (
+	"errors"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/signer"
+	"github.com/stellar/go-stellar-base/xdr"
+)
+
+// ErrInsufficientSigners is returned when available signers of the account
+// cannot meet the threshold of submitted operations.
+var ErrInsufficientSigners = errors.New("Available signers cannot meet account threshold")
+
+type threshold int
+
+const (
+	thresholdLow threshold = iota
+	thresholdMedium
+	thresholdHigh
+)
+
+func (t threshold) String() string {
+	switch t {
+	case thresholdLow:
+		return "low"
+	case thresholdMedium:
+		return "medium"
+	default:
+		return "high"
+	}
+}
+
+// operationThreshold returns threshold category of the operation.
+// See: https://www.stellar.org/developers/learn/concepts/multi-sig.html
+func operationThreshold(operation xdr.Operation) threshold {
+	switch operation.Body.Type {
+	case xdr.OperationTypeAllowTrust, xdr.OperationTypeInflation:
+		return thresholdLow
+	case xdr.OperationTypeAccountMerge:
+		return thresholdHigh
+	case xdr.OperationTypeSetOptions:
+		op := operation.Body.SetOptionsOp
+		if op != nil && (op.MasterWeight != nil || op.LowThreshold != nil ||
+			op.MedThreshold != nil || op.HighThreshold != nil || op.Signer != nil) {
+			return thresholdHigh
+		}
+		return thresholdMedium
+	default:
+		return thresholdMedium
+	}
+}
+
+// update sets account thresholds and signers' weights from horizon response.
+func (account *Account) update(accountResponse horizon.AccountResponse) {
+	account.Thresholds = accountResponse.Thresholds
+	account.SignerWeights = make(map[string]byte)
+	for _, s := range accountResponse.Signers {
+		account.SignerWeights[s.GetPublicKey()] = s.Weight
+	}
+
+	// Master key has weight 1 by default
+	if len(account.SignerWeights) == 0 {
+		account.SignerWeights[account.Address] = 1
+	}
+}
+
+func (account *Account) thresholdValue(t threshold) byte {
+	switch t {
+	case thresholdLow:
+		return account.Thresholds.LowThreshold
+	case thresholdMedium:
+		return account.Thresholds.MedThreshold
+	default:
+		return account.Thresholds.HighThreshold
+	}
+}
+
+// selectSigners returns signers of the account whose combined weight meets
+// the given threshold. Returns ErrInsufficientSigners when the threshold
+// cannot be met.
+func (account *Account) selectSigners(t threshold) (signers []signer.Signer, err error) {
+	account.Mutex.Lock()
+	defer account.Mutex.Unlock()
+
+	needed := int(account.thresholdValue(t))
+	// At least one signature is always required
+	if needed == 0 {
+		needed = 1
+	}
+
+	weight := 0
+	for _, s := range account.Signers {
+		signerWeight := int(account.SignerWeights[s.Address()])
+		if signerWeight == 0 {
+			continue
+		}
+
+		signers = append(signers, s)
+		weight += signerWeight
+		if weight >= needed {
+			return
+		}
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"service":   "TransactionSubmitter",
+		"account":   account.Address,
+		"threshold": t.String(),
+		"weight":    weight,
+		"needed":    needed,
+	}).Error("Cannot meet threshold of account")
+	err = ErrInsufficientSigners
+	return
+}
@@ -0,0 +1,12 @@
+package submitter
+
+import (
+	"github.com/stellar/go-stellar-base/build"
+	"github.com/stellar/go-stellar-base/xdr"
+)
+
+// TransactionHash returns hex encoded hash of the transaction on a given network.
+func TransactionHash(tx *xdr.Transaction, network build.Network) (string, error) {
+	txBuilder := build.TransactionBuilder{TX: tx, NetworkID: network.ID()}
+	return txBuilder.HashHex()
+}
@@ -0,0 +1,282 @@
+package submitter
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/go-stellar-base/build"
+	"github.com/stellar/go-stellar-base/strkey"
+	"github.com/stellar/go-stellar-base/xdr"
+)
+
+// TransactionRecoverer resolves SentTransactions left in "sending" state
+// (ex. when the process died or horizon did not respond during submission).
+// For every such transaction it checks in horizon whether it was included in
+// a ledger and, if not, resubmits it when its sequence number is still valid.
+// Pending refunds and trustline authorizations linked to a resolved
+// transaction are updated with its result.
+type TransactionRecoverer struct {
+	Horizon       horizon.HorizonInterface
+	EntityManager db.EntityManagerInterface
+	Repository    db.RepositoryInterface
+	Network       build.Network
+	// Interval between periodic recovery runs
+	Interval time.Duration
+	// Periodic runs only recover transactions submitted at least MinAge ago
+	// so they do not race with submissions that are still in progress.
+	MinAge time.Duration
+	now    func() time.Time
+	log    *logrus.Entry
+}
+
+func NewTransactionRecoverer(
+	horizon horizon.HorizonInterface,
+	entityManager db.EntityManagerInterface,
+	repository db.RepositoryInterface,
+	networkPassphrase string,
+	now func() time.Time,
+) (tr TransactionRecoverer) {
+	tr.Horizon = horizon
+	tr.EntityManager = entityManager
+	tr.Repository = repository
+	tr.Network = build.Network{networkPassphrase}
+	tr.Interval = time.Minute
+	tr.MinAge = 5 * time.Minute
+	tr.now = now
+	tr.log = logrus.WithFields(logrus.Fields{
+		"service": "TransactionRecoverer",
+	})
+	return
+}
+
+// Start runs recovery every tr.Interval in a separate goroutine.
+func (tr *TransactionRecoverer) Start() {
+	go func() {
+		for {
+			time.Sleep(tr.Interval)
+			err := tr.RecoverAll(tr.MinAge)
+			if err != nil {
+				tr.log.Error("Error recovering transactions: ", err)
+			}
+		}
+	}()
+}
+
+// RecoverAll recovers all transactions in "sending" state submitted at least
+// minAge ago. Errors of individual transactions are logged and skipped, these
+// transactions will be retried in the next run.
+func (tr *TransactionRecoverer) RecoverAll(minAge time.Duration) (err error) {
+	transactions, err := tr.Repository.GetSendingTransactions(tr.now().Add(-minAge))
+	if err != nil {
+		return
+	}
+
+	for i := range transactions {
+		recoverErr := tr.Recover(&transactions[i])
+		if recoverErr != nil {
+			tr.log.WithFields(logrus.Fields{
+				"id":    *transactions[i].Id,
+				"error": recoverErr,
+			}).Error("Cannot recover transaction")
+		}
+	}
+	return
+}
+
+// Recover resolves a single transaction in "sending" state. Transaction is left
+// in "sending" state when its final status cannot be determined yet.
+func (tr *TransactionRecoverer) Recover(transaction *db.SentTransaction) (err error) {
+	var envelope xdr.TransactionEnvelope
+	err = xdr.SafeUnmarshalBase64(transaction.EnvelopeXdr, &envelope)
+	if err != nil {
+		return
+	}
+
+	hash, err := TransactionHash(&envelope.Tx, tr.Network)
+	if err != nil {
+		return
+	}
+
+	log := tr.log.WithFields(logrus.Fields{
+		"id":   *transaction.Id,
+		"hash": hash,
+	})
+
+	found, err := tr.resolveFromHorizon(transaction, hash, log)
+	if err != nil || found {
+		return
+	}
+
+	// Transaction source can be a channel account so it's not always equal to
+	// transaction.Source.
+	sourceKey := envelope.Tx.SourceAccount.Ed25519
+	if sourceKey == nil {
+		return errors.New("Invalid transaction source account")
+	}
+	sourceAddress, err := strkey.Encode(strkey.VersionByteAccountID, sourceKey[:])
+	if err != nil {
+		return
+	}
+
+	accountResponse, err := tr.Horizon.LoadAccount(context.Background(), sourceAddress)
+	if err != nil {
+		return
+	}
+
+	accountSequence, err := strconv.ParseUint(accountResponse.SequenceNumber, 10, 64)
+	if err != nil {
+		return
+	}
+
+	transactionSequence := uint64(envelope.Tx.SeqNum)
+
+	switch {
+	case accountSequence >= transactionSequence:
+		// Transaction could have been applied after it was loaded above
+		found, err = tr.resolveFromHorizon(transaction, hash, log)
+		if err != nil || found {
+			return
+		}
+		log.Info("Transaction sequence number consumed, marking as failed")
+		transaction.MarkSequenceConsumed()
+		return tr.save(transaction, hash)
+	case accountSequence+1 < transactionSequence:
+		// Previous transactions of this account have not been applied yet.
+		log.Info("Transaction sequence number too high, skipping")
+		return nil
+	}
+
+	log.Info("Resubmitting transaction")
+	submitResponse, err := tr.Horizon.SubmitTransaction(context.Background(), transaction.EnvelopeXdr)
+	if err != nil {
+		return
+	}
+
+	transaction.SetHorizonUrl(submitResponse.ServerUrl)
+
+	if submitResponse.Ledger != nil {
+		transaction.MarkSucceeded(*submitResponse.Ledger)
+	} else {
+		// Transaction could have been applied in the meantime. The next run
+		// will find it in horizon or mark it as failed.
+		if submitResponse.Errors != nil && submitResponse.Errors.TransactionErrorCode == "transaction_bad_seq" {
+			return nil
+		}
+		var resultXdr string
+		if submitResponse.Extras != nil {
+			resultXdr = submitResponse.Extras.ResultXdr
+		}
+		transaction.MarkFailed(resultXdr)
+	}
+	return tr.save(transaction, hash)
+}
+
+// resolveFromHorizon loads transaction with a given hash from horizon and
+// saves its result. found is false when horizon does not know the transaction.
+func (tr *TransactionRecoverer) resolveFromHorizon(transaction *db.SentTransaction, hash string, log *logrus.Entry) (found bool, err error) {
+	transactionResponse, err := tr.Horizon.LoadTransaction(context.Background(), hash)
+	if err == horizon.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return
+	}
+
+	if transactionResponse.Ledger != nil && !isTransactionFailed(transactionResponse.ResultXdr) {
+		log.Info("Transaction found in ledger")
+		transaction.MarkSucceeded(*transactionResponse.Ledger)
+	} else {
+		log.Info("Transaction found but failed")
+		transaction.MarkFailed(transactionResponse.ResultXdr)
+	}
+	return true, tr.save(transaction, hash)
+}
+
+// save saves a resolved transaction in the same DB transaction as pending
+// refund or trustline authorization waiting for its result.
+func (tr *TransactionRecoverer) save(transaction *db.SentTransaction, hash string) (err error) {
+	entities := []db.Entity{transaction}
+	succeeded := transaction.Status == "success"
+
+	payments, err := tr.Repository.GetReceivedPayments(db.ReceivedPaymentsFilter{
+		RefundTransactionId: *transaction.Id,
+		Limit:               1,
+	})
+	if err != nil {
+		return
+	}
+	for i := range payments {
+		payment := &payments[i]
+		if payment.RefundStatus == nil || *payment.RefundStatus != "pending" {
+			continue
+		}
+		refundStatus := "failed"
+		if succeeded {
+			refundStatus = "sent"
+			payment.RefundTransactionHash = &hash
+		}
+		payment.RefundStatus = &refundStatus
+		entities = append(entities, payment)
+	}
+
+	authorizations, err := tr.Repository.GetTrustlineAuthorizations(db.TrustlineAuthorizationsFilter{
+		TransactionId: *transaction.Id,
+		Limit:         1,
+	})
+	if err != nil {
+		return
+	}
+	for i := range authorizations {
+		authorization := &authorizations[i]
+		if authorization.Status != "pending" {
+			continue
+		}
+		if succeeded {
+			authorization.Status = "authorized"
+			authorization.Reason = nil
+			authorization.TransactionHash = &hash
+		} else {
+			reason := transactionErrorCode(transaction.ResultXdr)
+			authorization.Status = "failed"
+			authorization.Reason = &reason
+		}
+		entities = append(entities, authorization)
+	}
+
+	if len(entities) == 1 {
+		return tr.EntityManager.Persist(transaction)
+	}
+	return tr.EntityManager.PersistAll(entities...)
+}
+
+// transactionErrorCode returns a code of the failed operation (or
+// transaction) decoded from resultXdr or transaction_failed when it cannot be
+// decoded.
+func transactionErrorCode(resultXdr *string) string {
+	if resultXdr != nil {
+		resultErrors, err := horizon.DecodeTransactionResult(*resultXdr)
+		if err == nil && resultErrors.OperationErrorCode != "" {
+			return resultErrors.OperationErrorCode
+		}
+		if err == nil && resultErrors.TransactionErrorCode != "" {
+			return resultErrors.TransactionErrorCode
+		}
+	}
+	return "transaction_failed"
+}
+
+// isTransactionFailed returns true only when resultXdr can be decoded and
+// contains an error code.
+func isTransactionFailed(resultXdr string) bool {
+	var txResult xdr.TransactionResult
+	err := xdr.SafeUnmarshalBase64(resultXdr, &txResult)
+	if err != nil {
+		return false
+	}
+	return txResult.Result.Code != xdr.TransactionResultCodeTxSuccess
+}
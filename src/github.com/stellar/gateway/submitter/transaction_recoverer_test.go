@@ -0,0 +1,277 @@
+package submitter
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/go-stellar-base/build"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTransactionRecoverer(t *testing.T) {
+	mockEntityManager := new(mocks.MockEntityManager)
+	mockHorizon := new(mocks.MockHorizon)
+	mockRepository := new(mocks.MockRepository)
+
+	mocks.PredefinedTime = time.Now()
+
+	transactionRecoverer := NewTransactionRecoverer(
+		mockHorizon,
+		mockEntityManager,
+		mockRepository,
+		"Test SDF Network ; September 2015",
+		mocks.Now,
+	)
+
+	// GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR
+	seed := "SC34WILLHVADXMP6ACPMIRA6TRAWJMVCLPFNW7S6MUMXJVLAZUC4EWHP"
+	source := "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR"
+
+	tx := build.Transaction(
+		build.SourceAccount{seed},
+		build.Sequence{101},
+		build.Network{"Test SDF Network ; September 2015"},
+		build.Payment(
+			build.Destination{"GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632"},
+			build.NativeAmount{"100"},
+		),
+	)
+	hash, err := tx.HashHex()
+	if err != nil {
+		panic(err)
+	}
+	txe := tx.Sign(seed)
+	txeB64, err := txe.Base64()
+	if err != nil {
+		panic(err)
+	}
+
+	Convey("TransactionRecoverer", t, func() {
+		id := int64(1)
+		transaction := db.SentTransaction{
+			Id:          &id,
+			Status:      "sending",
+			Source:      source,
+			SubmittedAt: mocks.PredefinedTime,
+			EnvelopeXdr: txeB64,
+		}
+
+		paymentsFilter := db.ReceivedPaymentsFilter{RefundTransactionId: id, Limit: 1}
+		authorizationsFilter := db.TrustlineAuthorizationsFilter{TransactionId: id, Limit: 1}
+		mockRepository.ExpectedCalls = nil
+		mockRepository.On("GetReceivedPayments", paymentsFilter).Return([]db.ReceivedPayment{}, nil)
+		mockRepository.On("GetTrustlineAuthorizations", authorizationsFilter).Return([]db.TrustlineAuthorization{}, nil)
+
+		Convey("When transaction is found in horizon", func() {
+			ledger := uint64(1234)
+			mockHorizon.On("LoadTransaction", hash).Return(
+				horizon.TransactionResponse{Hash: hash, Ledger: &ledger},
+				nil,
+			).Once()
+			mockEntityManager.On("Persist", &transaction).Return(nil).Once()
+
+			Convey("it should mark it as succeeded", func() {
+				err := transactionRecoverer.Recover(&transaction)
+				assert.Nil(t, err)
+				assert.Equal(t, "success", transaction.Status)
+				assert.Equal(t, ledger, *transaction.Ledger)
+				mockHorizon.AssertExpectations(t)
+				mockEntityManager.AssertExpectations(t)
+			})
+		})
+
+		Convey("When transaction of a pending refund is found in horizon", func() {
+			ledger := uint64(1234)
+			mockHorizon.On("LoadTransaction", hash).Return(
+				horizon.TransactionResponse{Hash: hash, Ledger: &ledger},
+				nil,
+			).Once()
+
+			paymentId := int64(7)
+			refundStatus := "pending"
+			mockRepository.ExpectedCalls = nil
+			mockRepository.On("GetReceivedPayments", paymentsFilter).Return([]db.ReceivedPayment{
+				{Id: &paymentId, RefundStatus: &refundStatus, RefundTransactionId: &id},
+			}, nil).Once()
+			mockRepository.On("GetTrustlineAuthorizations", authorizationsFilter).Return([]db.TrustlineAuthorization{}, nil).Once()
+
+			var savedPayment *db.ReceivedPayment
+			mockEntityManager.On("PersistAll", mock.Anything).Run(func(args mock.Arguments) {
+				entities := args.Get(0).([]db.Entity)
+				assert.Equal(t, 2, len(entities))
+				assert.Equal(t, &transaction, entities[0])
+				savedPayment = entities[1].(*db.ReceivedPayment)
+			}).Return(nil).Once()
+
+			Convey("it should mark the refund as sent", func() {
+				err := transactionRecoverer.Recover(&transaction)
+				assert.Nil(t, err)
+				assert.Equal(t, "success", transaction.Status)
+				assert.Equal(t, "sent", *savedPayment.RefundStatus)
+				assert.Equal(t, hash, *savedPayment.RefundTransactionHash)
+				mockRepository.AssertExpectations(t)
+				mockEntityManager.AssertExpectations(t)
+			})
+		})
+
+		Convey("When transaction of a pending authorization failed", func() {
+			mockHorizon.On("LoadTransaction", hash).Return(
+				horizon.TransactionResponse{},
+				horizon.ErrNotFound,
+			).Twice()
+			mockHorizon.On("LoadAccount", source).Return(
+				horizon.AccountResponse{AccountId: source, SequenceNumber: "101"},
+				nil,
+			).Once()
+
+			authorizationId := int64(8)
+			mockRepository.ExpectedCalls = nil
+			mockRepository.On("GetReceivedPayments", paymentsFilter).Return([]db.ReceivedPayment{}, nil).Once()
+			mockRepository.On("GetTrustlineAuthorizations", authorizationsFilter).Return([]db.TrustlineAuthorization{
+				{Id: &authorizationId, Status: "pending", TransactionId: &id},
+			}, nil).Once()
+
+			var savedAuthorization *db.TrustlineAuthorization
+			mockEntityManager.On("PersistAll", mock.Anything).Run(func(args mock.Arguments) {
+				entities := args.Get(0).([]db.Entity)
+				assert.Equal(t, 2, len(entities))
+				savedAuthorization = entities[1].(*db.TrustlineAuthorization)
+			}).Return(nil).Once()
+
+			Convey("it should mark the authorization as failed", func() {
+				err := transactionRecoverer.Recover(&transaction)
+				assert.Nil(t, err)
+				assert.Equal(t, "failure", transaction.Status)
+				assert.Equal(t, "failed", savedAuthorization.Status)
+				assert.Equal(t, "transaction_failed", *savedAuthorization.Reason)
+				assert.Nil(t, savedAuthorization.TransactionHash)
+				mockRepository.AssertExpectations(t)
+				mockEntityManager.AssertExpectations(t)
+			})
+		})
+
+		Convey("When horizon returns error", func() {
+			mockHorizon.On("LoadTransaction", hash).Return(
+				horizon.TransactionResponse{},
+				errors.New("Connection error"),
+			).Once()
+
+			Convey("it should leave transaction in sending state", func() {
+				err := transactionRecoverer.Recover(&transaction)
+				assert.Error(t, err)
+				assert.Equal(t, "sending", transaction.Status)
+				mockHorizon.AssertExpectations(t)
+				mockEntityManager.AssertNotCalled(t, "Persist")
+			})
+		})
+
+		Convey("When transaction is not found and sequence number was consumed", func() {
+			mockHorizon.On("LoadTransaction", hash).Return(
+				horizon.TransactionResponse{},
+				horizon.ErrNotFound,
+			).Once()
+			mockHorizon.On("LoadAccount", source).Return(
+				horizon.AccountResponse{AccountId: source, SequenceNumber: "101"},
+				nil,
+			).Once()
+			mockEntityManager.On("Persist", &transaction).Return(nil).Once()
+
+			Convey("it should mark it as failed when it's still not found", func() {
+				mockHorizon.On("LoadTransaction", hash).Return(
+					horizon.TransactionResponse{},
+					horizon.ErrNotFound,
+				).Once()
+
+				err := transactionRecoverer.Recover(&transaction)
+				assert.Nil(t, err)
+				assert.Equal(t, "failure", transaction.Status)
+				mockHorizon.AssertExpectations(t)
+				mockEntityManager.AssertExpectations(t)
+			})
+
+			Convey("it should mark it as succeeded when it was applied in the meantime", func() {
+				ledger := uint64(1236)
+				mockHorizon.On("LoadTransaction", hash).Return(
+					horizon.TransactionResponse{Hash: hash, Ledger: &ledger},
+					nil,
+				).Once()
+
+				err := transactionRecoverer.Recover(&transaction)
+				assert.Nil(t, err)
+				assert.Equal(t, "success", transaction.Status)
+				assert.Equal(t, ledger, *transaction.Ledger)
+				mockHorizon.AssertExpectations(t)
+				mockEntityManager.AssertExpectations(t)
+			})
+		})
+
+		Convey("When transaction is not found and previous transactions are pending", func() {
+			mockHorizon.On("LoadTransaction", hash).Return(
+				horizon.TransactionResponse{},
+				horizon.ErrNotFound,
+			).Once()
+			mockHorizon.On("LoadAccount", source).Return(
+				horizon.AccountResponse{AccountId: source, SequenceNumber: "99"},
+				nil,
+			).Once()
+
+			Convey("it should leave transaction in sending state", func() {
+				err := transactionRecoverer.Recover(&transaction)
+				assert.Nil(t, err)
+				assert.Equal(t, "sending", transaction.Status)
+				mockHorizon.AssertExpectations(t)
+				mockEntityManager.AssertNotCalled(t, "Persist")
+			})
+		})
+
+		Convey("When transaction is not found and sequence number is valid", func() {
+			mockHorizon.On("LoadTransaction", hash).Return(
+				horizon.TransactionResponse{},
+				horizon.ErrNotFound,
+			).Once()
+			mockHorizon.On("LoadAccount", source).Return(
+				horizon.AccountResponse{AccountId: source, SequenceNumber: "100"},
+				nil,
+			).Once()
+
+			Convey("it should resubmit it", func() {
+				ledger := uint64(1235)
+				mockHorizon.On("SubmitTransaction", txeB64).Return(
+					horizon.SubmitTransactionResponse{Ledger: &ledger},
+					nil,
+				).Once()
+				mockEntityManager.On("Persist", &transaction).Return(nil).Once()
+
+				err := transactionRecoverer.Recover(&transaction)
+				assert.Nil(t, err)
+				assert.Equal(t, "success", transaction.Status)
+				assert.Equal(t, ledger, *transaction.Ledger)
+				mockHorizon.AssertExpectations(t)
+				mockEntityManager.AssertExpectations(t)
+			})
+
+			Convey("it should leave it in sending state on transaction_bad_seq", func() {
+				mockHorizon.On("SubmitTransaction", txeB64).Return(
+					horizon.SubmitTransactionResponse{
+						Errors: &horizon.SubmitTransactionResponseError{
+							TransactionErrorCode: "transaction_bad_seq",
+						},
+					},
+					nil,
+				).Once()
+
+				err := transactionRecoverer.Recover(&transaction)
+				assert.Nil(t, err)
+				assert.Equal(t, "sending", transaction.Status)
+				mockHorizon.AssertExpectations(t)
+				mockEntityManager.AssertNotCalled(t, "Persist")
+			})
+		})
+	})
+}
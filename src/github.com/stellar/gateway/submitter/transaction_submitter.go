@@ -1,6 +1,7 @@
 package submitter
 
 import (
+	"context"
 	"errors"
 	"strconv"
 	"sync"
@@ -9,85 +10,213 @@ import (
 	"github.com/Sirupsen/logrus"
 	"github.com/stellar/gateway/db"
 	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/signer"
 	"github.com/stellar/go-stellar-base/build"
-	"github.com/stellar/go-stellar-base/keypair"
 )
 
 type TransactionSubmitterInterface interface {
-	SubmitTransaction(seed string, operation, memo interface{}) (response horizon.SubmitTransactionResponse, err error)
+	SubmitTransaction(ctx context.Context, source string, operation, memo interface{}) (response horizon.SubmitTransactionResponse, err error)
+	SignTransaction(source string, operation, memo interface{}) (txeB64 string, err error)
 }
 
 type TransactionSubmitter struct {
-	Horizon       *horizon.Horizon
-	Accounts      map[string]*Account // seed => *Account
-	EntityManager *db.EntityManager
+	Horizon       horizon.HorizonInterface
+	Accounts      map[string]*Account // address => *Account
+	EntityManager db.EntityManagerInterface
 	Network       build.Network
-	log           *logrus.Entry
+	// MaxBadSeqRetries is a number of times transaction is rebuilt and
+	// resubmitted with a synced sequence number after transaction_bad_seq error.
+	MaxBadSeqRetries int
+	// Channels is a pool of free channel accounts. When it's not nil, a channel
+	// account is leased for every submission and used as a transaction source
+	// while the submitting account is an operation source.
+	Channels chan *Account
+	log      *logrus.Entry
 }
 
 type Account struct {
-	Keypair        keypair.KP
-	Seed           string
+	Address string
+	// Signers are all available signers of the account. Only signers needed to
+	// meet the threshold of submitted operations are used.
+	Signers        []signer.Signer
+	Thresholds     horizon.AccountThresholds
+	SignerWeights  map[string]byte // signer address => weight
 	SequenceNumber uint64
 	Mutex          sync.Mutex
 }
 
-func NewTransactionSubmitter(horizon *horizon.Horizon, entityManager *db.EntityManager, networkPassphrase string) (ts TransactionSubmitter) {
+func NewTransactionSubmitter(horizon horizon.HorizonInterface, entityManager db.EntityManagerInterface, networkPassphrase string) (ts TransactionSubmitter) {
 	ts.Horizon = horizon
 	ts.EntityManager = entityManager
 	ts.Accounts = make(map[string]*Account)
 	ts.Network = build.Network{networkPassphrase}
+	ts.MaxBadSeqRetries = 3
 	ts.log = logrus.WithFields(logrus.Fields{
 		"service": "TransactionSubmitter",
 	})
 	return
 }
 
-func (ts *TransactionSubmitter) LoadAccount(seed string) (account *Account, err error) {
-	account = &Account{}
-	account.Keypair, err = keypair.Parse(seed)
+func (ts *TransactionSubmitter) LoadAccount(address string, signers ...signer.Signer) (account *Account, err error) {
+	account = &Account{
+		Address: address,
+		Signers: signers,
+	}
+
+	accountResponse, err := ts.Horizon.LoadAccount(context.Background(), account.Address)
 	if err != nil {
-		ts.log.Print("Invalid seed")
 		return
 	}
 
-	accountResponse, err := ts.Horizon.LoadAccount(account.Keypair.Address())
+	account.SequenceNumber, err = strconv.ParseUint(accountResponse.SequenceNumber, 10, 64)
+	account.update(accountResponse)
+	return
+}
+
+// InitAccount loads account that will be signed by given signers. Only
+// initialized accounts can be used as a source in SubmitTransaction.
+// Returns ErrInsufficientSigners when signers cannot meet the low threshold
+// of the account. Medium threshold (payments) is only checked with a warning
+// because allow_trust of the authorizing account needs the low threshold.
+func (ts *TransactionSubmitter) InitAccount(address string, signers ...signer.Signer) (err error) {
+	account, err := ts.LoadAccount(address, signers...)
 	if err != nil {
 		return
 	}
 
-	account.Seed = seed
-	account.SequenceNumber, err = strconv.ParseUint(accountResponse.SequenceNumber, 10, 64)
+	_, err = account.selectSigners(thresholdLow)
+	if err != nil {
+		return
+	}
+	if _, mediumErr := account.selectSigners(thresholdMedium); mediumErr != nil {
+		ts.log.Warn("Signers of account ", address, " can submit low threshold operations only")
+	}
+
+	ts.Accounts[account.Address] = account
 	return
 }
 
-func (ts *TransactionSubmitter) InitAccount(seed string) (err error) {
-	_, err = ts.GetAccount(seed)
+// InitChannels loads channel accounts and adds them to the pool of channels.
+func (ts *TransactionSubmitter) InitChannels(signers []signer.Signer) (err error) {
+	channels := make(chan *Account, len(signers))
+	for _, s := range signers {
+		var channel *Account
+		channel, err = ts.LoadAccount(s.Address(), s)
+		if err != nil {
+			return
+		}
+		// Channels are transaction sources only
+		_, err = channel.selectSigners(thresholdLow)
+		if err != nil {
+			return
+		}
+		channels <- channel
+	}
+	ts.Channels = channels
 	return
 }
 
-func (ts *TransactionSubmitter) GetAccount(seed string) (account *Account, err error) {
-	account, exist := ts.Accounts[seed]
+func (ts *TransactionSubmitter) GetAccount(address string) (account *Account, err error) {
+	account, exist := ts.Accounts[address]
 	if !exist {
-		account, err = ts.LoadAccount(seed)
-		ts.Accounts[seed] = account
+		err = errors.New("Account not initialized: " + address)
 	}
 	return
 }
 
-func (ts *TransactionSubmitter) SubmitTransaction(seed string, operation, memo interface{}) (response horizon.SubmitTransactionResponse, err error) {
-	account, err := ts.GetAccount(seed)
+// SubmitTransaction builds, signs and submits transaction with a given
+// operation. source is an ID of an initialized account. ctx is used only to
+// link saved SentTransactions to the request (see WithIdempotencyKey), the
+// submission is not cancelled with it.
+func (ts *TransactionSubmitter) SubmitTransaction(ctx context.Context, source string, operation, memo interface{}) (response horizon.SubmitTransactionResponse, err error) {
+	account, err := ts.GetAccount(source)
 	if err != nil {
 		return
 	}
 
-	var sequenceNumber uint64
+	// Account used as a transaction source
+	transactionSource := account
 
-	account.Mutex.Lock()
-	account.SequenceNumber++
-	sequenceNumber = account.SequenceNumber
-	account.Mutex.Unlock()
+	if ts.Channels != nil {
+		channel := <-ts.Channels
+		defer func() { ts.Channels <- channel }()
+		transactionSource = channel
+	}
+
+	mutators, err := ts.transactionMutators(operation, memo)
+	if err != nil {
+		return
+	}
+
+	// Id of the first SentTransaction, retries are linked to it
+	var retryOf *int64
+
+	for attempt := 0; ; attempt++ {
+		response, err = ts.submit(ctx, account, transactionSource, mutators, retryOf)
+		if err != nil {
+			return
+		}
+		if retryOf == nil {
+			retryOf = response.SentTransactionId
+		}
 
+		if response.Errors == nil || response.Errors.TransactionErrorCode != "transaction_bad_seq" {
+			return
+		}
+
+		err = ts.syncSequenceNumber(transactionSource)
+		if err != nil {
+			// Failed transaction is returned, the sequence number is synced
+			// again after the next transaction_bad_seq
+			ts.log.Error("Cannot sync sequence number ", err)
+			err = nil
+			return
+		}
+
+		if attempt >= ts.MaxBadSeqRetries {
+			ts.log.Print("transaction_bad_seq retries exhausted for ", transactionSource.Address)
+			return
+		}
+
+		ts.log.Print("Resubmitting transaction after transaction_bad_seq")
+	}
+}
+
+// SignTransaction builds and signs transaction exactly like SubmitTransaction
+// but does not submit or save it. Sequence number of the source account is
+// not used so the transaction may be already invalid when another one is submitted.
+func (ts *TransactionSubmitter) SignTransaction(source string, operation, memo interface{}) (txeB64 string, err error) {
+	account, err := ts.GetAccount(source)
+	if err != nil {
+		return
+	}
+
+	transactionSource := account
+
+	if ts.Channels != nil {
+		channel := <-ts.Channels
+		defer func() { ts.Channels <- channel }()
+		transactionSource = channel
+	}
+
+	mutators, err := ts.transactionMutators(operation, memo)
+	if err != nil {
+		return
+	}
+
+	signers, err := ts.selectSigners(account, transactionSource, mutators)
+	if err != nil {
+		return
+	}
+
+	transactionSource.Mutex.Lock()
+	sequenceNumber := transactionSource.SequenceNumber + 1
+	transactionSource.Mutex.Unlock()
+
+	return ts.sign(account, transactionSource, signers, sequenceNumber, mutators)
+}
+
+func (ts *TransactionSubmitter) transactionMutators(operation, memo interface{}) (mutators []build.TransactionMutator, err error) {
 	operationMutator, ok := operation.(build.TransactionMutator)
 	if !ok {
 		ts.log.Error("Cannot cast operationMutator to build.TransactionMutator")
@@ -95,12 +224,7 @@ func (ts *TransactionSubmitter) SubmitTransaction(seed string, operation, memo i
 		return
 	}
 
-	mutators := []build.TransactionMutator{
-		build.SourceAccount{account.Seed},
-		build.Sequence{sequenceNumber},
-		ts.Network,
-		operationMutator,
-	}
+	mutators = []build.TransactionMutator{operationMutator}
 
 	if memo != nil {
 		memoMutator, ok := memo.(build.TransactionMutator)
@@ -111,52 +235,160 @@ func (ts *TransactionSubmitter) SubmitTransaction(seed string, operation, memo i
 		}
 		mutators = append(mutators, memoMutator)
 	}
+	return
+}
 
-	tx := build.Transaction(mutators...)
+// submit builds, signs and submits a single transaction with the next sequence
+// number of transactionSource. Every attempt is saved as a separate
+// SentTransaction, retries are linked to the first attempt with retryOf.
+func (ts *TransactionSubmitter) submit(
+	ctx context.Context,
+	account, transactionSource *Account,
+	mutators []build.TransactionMutator,
+	retryOf *int64,
+) (response horizon.SubmitTransactionResponse, err error) {
+	// Fail before the sequence number is used when thresholds cannot be met
+	signers, err := ts.selectSigners(account, transactionSource, mutators)
+	if err != nil {
+		ts.log.Error("Cannot select signers ", err)
+		return
+	}
+
+	var sequenceNumber uint64
 
-	txe := tx.Sign(seed)
-	txeB64, err := txe.Base64()
+	transactionSource.Mutex.Lock()
+	transactionSource.SequenceNumber++
+	sequenceNumber = transactionSource.SequenceNumber
+	transactionSource.Mutex.Unlock()
 
+	txeB64, err := ts.sign(account, transactionSource, signers, sequenceNumber, mutators)
 	if err != nil {
-		ts.log.Error("Cannot encode transaction envelope ", err)
 		return
 	}
 
 	sentTransaction := &db.SentTransaction{
-		Status:      "sending",
-		Source:      account.Keypair.Address(),
-		SubmittedAt: time.Now(),
-		EnvelopeXdr: txeB64,
+		Status:         "sending",
+		Source:         account.Address,
+		SubmittedAt:    time.Now(),
+		EnvelopeXdr:    txeB64,
+		IdempotencyKey: IdempotencyKeyFromContext(ctx),
+		RetryOf:        retryOf,
 	}
 	err = ts.EntityManager.Persist(sentTransaction)
 	if err != nil {
 		return
 	}
 
-	response, err = ts.Horizon.SubmitTransaction(txeB64)
+	response, err = ts.Horizon.SubmitTransaction(context.Background(), txeB64)
+	response.SentTransactionId = sentTransaction.Id
 	if err != nil {
-		ts.log.Error("Error submitting transaction ", err)
+		ts.log.WithFields(logrus.Fields{"horizon": response.ServerUrl}).Error("Error submitting transaction ", err)
 		return
 	}
 
+	sentTransaction.SetHorizonUrl(response.ServerUrl)
+
 	if response.Ledger != nil {
 		sentTransaction.MarkSucceeded(*response.Ledger)
-	} else {
+	} else if response.Extras != nil {
 		sentTransaction.MarkFailed(response.Extras.ResultXdr)
+	} else {
+		// Rejected without a transaction result (ex. malformed envelope)
+		sentTransaction.Status = "failure"
 	}
 	err = ts.EntityManager.Persist(sentTransaction)
+	return
+}
+
+// sign builds transaction with a given sequence number and returns base64
+// encoded envelope signed by signers.
+func (ts *TransactionSubmitter) sign(
+	account, transactionSource *Account,
+	signers []signer.Signer,
+	sequenceNumber uint64,
+	mutators []build.TransactionMutator,
+) (txeB64 string, err error) {
+	mutators = append([]build.TransactionMutator{
+		build.SourceAccount{transactionSource.Address},
+		build.Sequence{sequenceNumber},
+		ts.Network,
+	}, mutators...)
+
+	tx := build.Transaction(mutators...)
+
+	if transactionSource != account && tx.Err == nil {
+		for i := range tx.TX.Operations {
+			err = build.SourceAccount{account.Address}.MutateOperation(&tx.TX.Operations[i])
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	txe, err := signer.SignTransaction(tx, signers...)
 	if err != nil {
+		ts.log.Error("Cannot sign transaction ", err)
 		return
 	}
 
-	// Sync sequence number
-	if response.Errors != nil && response.Errors.TransactionErrorCode == "transaction_bad_seq" {
-		account.Mutex.Lock()
-		ts.log.Print("Syncing sequence number for ", account.Keypair.Address())
-		accountResponse, _ := ts.Horizon.LoadAccount(account.Keypair.Address())
-		account.SequenceNumber, err = strconv.ParseUint(accountResponse.SequenceNumber, 10, 64)
-		account.Mutex.Unlock()
+	txeB64, err = txe.Base64()
+	if err != nil {
+		ts.log.Error("Cannot encode transaction envelope ", err)
+	}
+	return
+}
+
+func (ts *TransactionSubmitter) syncSequenceNumber(account *Account) (err error) {
+	account.Mutex.Lock()
+	defer account.Mutex.Unlock()
+	ts.log.Print("Syncing sequence number for ", account.Address)
+	accountResponse, err := ts.Horizon.LoadAccount(context.Background(), account.Address)
+	if err != nil {
+		return
+	}
+	account.SequenceNumber, err = strconv.ParseUint(accountResponse.SequenceNumber, 10, 64)
+	account.update(accountResponse)
+	return
+}
+
+// selectSigners returns signers needed to meet thresholds of account (source
+// of operations) and transactionSource.
+func (ts *TransactionSubmitter) selectSigners(
+	account, transactionSource *Account,
+	mutators []build.TransactionMutator,
+) (signers []signer.Signer, err error) {
+	tx := build.Transaction(mutators...)
+	if tx.Err != nil {
+		err = tx.Err
+		return
+	}
+
+	// Transaction source needs low threshold to pay a fee and use sequence number
+	transactionThreshold := thresholdLow
+	operationsThreshold := thresholdLow
+	for _, operation := range tx.TX.Operations {
+		if t := operationThreshold(operation); t > operationsThreshold {
+			operationsThreshold = t
+		}
+	}
+
+	if transactionSource == account {
+		if operationsThreshold > transactionThreshold {
+			transactionThreshold = operationsThreshold
+		}
+		return account.selectSigners(transactionThreshold)
+	}
+
+	signers, err = transactionSource.selectSigners(transactionThreshold)
+	if err != nil {
+		return
+	}
+
+	accountSigners, err := account.selectSigners(operationsThreshold)
+	if err != nil {
+		return
 	}
 
+	signers = append(signers, accountSigners...)
 	return
 }
@@ -0,0 +1,447 @@
+package submitter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/gateway/signer"
+	"github.com/stellar/go-stellar-base/build"
+	"github.com/stellar/go-stellar-base/keypair"
+	"github.com/stellar/go-stellar-base/strkey"
+	"github.com/stellar/go-stellar-base/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTransactionSubmitter(t *testing.T) {
+	mockEntityManager := new(mocks.MockEntityManager)
+	mockHorizon := new(mocks.MockHorizon)
+
+	// GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR
+	seed := "SC34WILLHVADXMP6ACPMIRA6TRAWJMVCLPFNW7S6MUMXJVLAZUC4EWHP"
+	address := "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR"
+	seedSigner, err := signer.NewSeedSigner(seed)
+	if err != nil {
+		panic(err)
+	}
+
+	operation := build.Payment(
+		build.Destination{"GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632"},
+		build.NativeAmount{"100"},
+	)
+
+	badSeqResponse := horizon.SubmitTransactionResponse{
+		Errors: &horizon.SubmitTransactionResponseError{
+			TransactionErrorCode: "transaction_bad_seq",
+		},
+		Extras: &horizon.SubmitTransactionResponseExtras{},
+	}
+
+	Convey("TransactionSubmitter", t, func() {
+		transactionSubmitter := NewTransactionSubmitter(
+			mockHorizon,
+			mockEntityManager,
+			"Test SDF Network ; September 2015",
+		)
+		transactionSubmitter.MaxBadSeqRetries = 2
+
+		mockHorizon.On("LoadAccount", address).Return(
+			horizon.AccountResponse{AccountId: address, SequenceNumber: "100"},
+			nil,
+		).Once()
+		err := transactionSubmitter.InitAccount(address, seedSigner)
+		assert.Nil(t, err)
+
+		// Persist is called with the same object so saved SentTransactions
+		// are collected when they get an id
+		var sentTransactions []*db.SentTransaction
+		nextId := int64(1)
+		mockEntityManager.On("Persist", mock.Anything).Run(func(args mock.Arguments) {
+			sentTransaction, ok := args.Get(0).(*db.SentTransaction)
+			if ok && sentTransaction.Id == nil {
+				sentTransaction.SetId(nextId)
+				nextId++
+				sentTransactions = append(sentTransactions, sentTransaction)
+			}
+		}).Return(nil)
+
+		Convey("When transaction_bad_seq is returned once", func() {
+			ledger := uint64(1234)
+			mockHorizon.On("SubmitTransaction", mock.Anything).Return(badSeqResponse, nil).Once()
+			mockHorizon.On("LoadAccount", address).Return(
+				horizon.AccountResponse{AccountId: address, SequenceNumber: "110"},
+				nil,
+			).Once()
+			mockHorizon.On("SubmitTransaction", mock.Anything).Return(
+				horizon.SubmitTransactionResponse{Ledger: &ledger},
+				nil,
+			).Once()
+
+			Convey("it should resubmit with a synced sequence number", func() {
+				sentTransactions = nil
+				response, err := transactionSubmitter.SubmitTransaction(context.Background(), address, operation, nil)
+				assert.Nil(t, err)
+				assert.Nil(t, response.Errors)
+				assert.Equal(t, ledger, *response.Ledger)
+				assert.Equal(t, uint64(111), transactionSubmitter.Accounts[address].SequenceNumber)
+				mockHorizon.AssertExpectations(t)
+
+				// Retry is linked to the first attempt
+				assert.Equal(t, 2, len(sentTransactions))
+				assert.Nil(t, sentTransactions[0].RetryOf)
+				assert.Equal(t, "failure", sentTransactions[0].Status)
+				assert.Equal(t, *sentTransactions[0].Id, *sentTransactions[1].RetryOf)
+				assert.Equal(t, "success", sentTransactions[1].Status)
+				assert.Equal(t, sentTransactions[1].Id, response.SentTransactionId)
+			})
+		})
+
+		Convey("When transaction is submitted", func() {
+			ledger := uint64(1234)
+			mockHorizon.On("SubmitTransaction", mock.Anything).Return(
+				horizon.SubmitTransactionResponse{Ledger: &ledger, ServerUrl: "https://horizon-2.example.com"},
+				nil,
+			).Once()
+
+			Convey("it should save horizon server URL", func() {
+				_, err := transactionSubmitter.SubmitTransaction(context.Background(), address, operation, nil)
+				assert.Nil(t, err)
+
+				calls := mockEntityManager.Calls
+				sentTransaction := calls[len(calls)-1].Arguments.Get(0).(*db.SentTransaction)
+				assert.Equal(t, "success", sentTransaction.Status)
+				assert.Equal(t, "https://horizon-2.example.com", *sentTransaction.HorizonUrl)
+				assert.Nil(t, sentTransaction.IdempotencyKey)
+			})
+
+			Convey("it should link the transaction to idempotency_key of the request", func() {
+				ctx := WithIdempotencyKey(context.Background(), "key1")
+				_, err := transactionSubmitter.SubmitTransaction(ctx, address, operation, nil)
+				assert.Nil(t, err)
+
+				calls := mockEntityManager.Calls
+				sentTransaction := calls[len(calls)-1].Arguments.Get(0).(*db.SentTransaction)
+				assert.Equal(t, "key1", *sentTransaction.IdempotencyKey)
+			})
+		})
+
+		Convey("When transaction is only signed", func() {
+			Convey("it should not submit it or use sequence number", func() {
+				horizonCalls := len(mockHorizon.Calls)
+				txeB64, err := transactionSubmitter.SignTransaction(address, operation, nil)
+				assert.Nil(t, err)
+
+				var envelope xdr.TransactionEnvelope
+				err = xdr.SafeUnmarshalBase64(txeB64, &envelope)
+				assert.Nil(t, err)
+				assert.Equal(t, xdr.SequenceNumber(101), envelope.Tx.SeqNum)
+				assert.Equal(t, 1, len(envelope.Signatures))
+				assert.Equal(t, uint64(100), transactionSubmitter.Accounts[address].SequenceNumber)
+				assert.Equal(t, horizonCalls, len(mockHorizon.Calls))
+			})
+		})
+
+		Convey("When horizon rejects transaction without result", func() {
+			mockHorizon.On("SubmitTransaction", mock.Anything).Return(horizon.SubmitTransactionResponse{}, nil).Once()
+
+			Convey("it should mark it failed", func() {
+				response, err := transactionSubmitter.SubmitTransaction(context.Background(), address, operation, nil)
+				assert.Nil(t, err)
+				assert.Nil(t, response.Ledger)
+
+				calls := mockEntityManager.Calls
+				sentTransaction := calls[len(calls)-1].Arguments.Get(0).(*db.SentTransaction)
+				assert.Equal(t, "failure", sentTransaction.Status)
+				assert.Nil(t, sentTransaction.ResultXdr)
+				mockHorizon.AssertExpectations(t)
+			})
+		})
+
+		Convey("When sequence number cannot be synced after transaction_bad_seq", func() {
+			mockHorizon.On("SubmitTransaction", mock.Anything).Return(badSeqResponse, nil).Once()
+			mockHorizon.On("LoadAccount", address).Return(horizon.AccountResponse{}, horizon.ErrTimeout).Once()
+
+			Convey("it should return the failed transaction", func() {
+				response, err := transactionSubmitter.SubmitTransaction(context.Background(), address, operation, nil)
+				assert.Nil(t, err)
+				assert.Equal(t, "transaction_bad_seq", response.Errors.TransactionErrorCode)
+
+				calls := mockEntityManager.Calls
+				sentTransaction := calls[len(calls)-1].Arguments.Get(0).(*db.SentTransaction)
+				assert.Equal(t, "failure", sentTransaction.Status)
+				assert.Equal(t, sentTransaction.Id, response.SentTransactionId)
+				mockHorizon.AssertExpectations(t)
+			})
+		})
+
+		Convey("When transaction_bad_seq is returned every time", func() {
+			mockHorizon.On("SubmitTransaction", mock.Anything).Return(badSeqResponse, nil).Times(3)
+			mockHorizon.On("LoadAccount", address).Return(
+				horizon.AccountResponse{AccountId: address, SequenceNumber: "110"},
+				nil,
+			).Times(3)
+
+			Convey("it should return error when retries are exhausted", func() {
+				response, err := transactionSubmitter.SubmitTransaction(context.Background(), address, operation, nil)
+				assert.Nil(t, err)
+				assert.Equal(t, "transaction_bad_seq", response.Errors.TransactionErrorCode)
+				mockHorizon.AssertExpectations(t)
+			})
+		})
+	})
+}
+
+func TestTransactionSubmitterMultisig(t *testing.T) {
+	mockEntityManager := new(mocks.MockEntityManager)
+	mockHorizon := new(mocks.MockHorizon)
+
+	address := "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR"
+	masterSigner, err := signer.NewSeedSigner("SC34WILLHVADXMP6ACPMIRA6TRAWJMVCLPFNW7S6MUMXJVLAZUC4EWHP")
+	if err != nil {
+		panic(err)
+	}
+	// GBQXA3ABGQGTCLEVZIUTDRWWJOQD5LSAEDZAG7GMOGD2HBLWONGUVO4I
+	secondSigner, err := signer.NewSeedSigner("SC37TBSIAYKIDQ6GTGLT2HSORLIHZQHBXVFI5P5K4Q5TSHRTRBK3UNWG")
+	if err != nil {
+		panic(err)
+	}
+
+	operation := build.Payment(
+		build.Destination{"GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632"},
+		build.NativeAmount{"100"},
+	)
+
+	accountResponse := func(medThreshold byte) horizon.AccountResponse {
+		return horizon.AccountResponse{
+			AccountId:      address,
+			SequenceNumber: "100",
+			Thresholds: horizon.AccountThresholds{
+				LowThreshold: 1,
+				MedThreshold: medThreshold,
+			},
+			Signers: []horizon.AccountSignerResponse{
+				{PublicKey: address, Weight: 1},
+				{PublicKey: secondSigner.Address(), Weight: 1},
+			},
+		}
+	}
+
+	Convey("TransactionSubmitter with multisig account", t, func() {
+		transactionSubmitter := NewTransactionSubmitter(
+			mockHorizon,
+			mockEntityManager,
+			"Test SDF Network ; September 2015",
+		)
+
+		Convey("When signers meet the threshold", func() {
+			mockHorizon.On("LoadAccount", address).Return(accountResponse(2), nil).Once()
+			err := transactionSubmitter.InitAccount(address, masterSigner, secondSigner)
+			assert.Nil(t, err)
+
+			ledger := uint64(1234)
+			mockEntityManager.On("Persist", mock.Anything).Return(nil).Twice()
+			mockHorizon.On("SubmitTransaction", mock.AnythingOfType("string")).Return(
+				horizon.SubmitTransactionResponse{Ledger: &ledger},
+				nil,
+			).Run(func(args mock.Arguments) {
+				var envelope xdr.TransactionEnvelope
+				err := xdr.SafeUnmarshalBase64(args.String(0), &envelope)
+				assert.Nil(t, err)
+				assert.Equal(t, 2, len(envelope.Signatures))
+			}).Once()
+
+			Convey("it should sign transaction with both signers", func() {
+				response, err := transactionSubmitter.SubmitTransaction(context.Background(), address, operation, nil)
+				assert.Nil(t, err)
+				assert.Equal(t, ledger, *response.Ledger)
+				mockHorizon.AssertExpectations(t)
+				mockEntityManager.AssertExpectations(t)
+			})
+		})
+
+		Convey("When signers cannot meet the low threshold", func() {
+			response := accountResponse(3)
+			response.Thresholds.LowThreshold = 3
+			mockHorizon.On("LoadAccount", address).Return(response, nil).Once()
+
+			Convey("it should not initialize the account", func() {
+				err := transactionSubmitter.InitAccount(address, masterSigner, secondSigner)
+				assert.Equal(t, ErrInsufficientSigners, err)
+				assert.Nil(t, transactionSubmitter.Accounts[address])
+				mockHorizon.AssertExpectations(t)
+			})
+		})
+
+		Convey("When signers cannot meet the threshold", func() {
+			mockHorizon.On("LoadAccount", address).Return(accountResponse(3), nil).Once()
+			err := transactionSubmitter.InitAccount(address, masterSigner, secondSigner)
+			assert.Nil(t, err)
+
+			Convey("it should return error without submitting transaction", func() {
+				_, err := transactionSubmitter.SubmitTransaction(context.Background(), address, operation, nil)
+				assert.Equal(t, ErrInsufficientSigners, err)
+				assert.Equal(t, uint64(100), transactionSubmitter.Accounts[address].SequenceNumber)
+				mockHorizon.AssertExpectations(t)
+				mockEntityManager.AssertExpectations(t)
+			})
+		})
+	})
+}
+
+func TestTransactionSubmitterChannels(t *testing.T) {
+	mockEntityManager := new(mocks.MockEntityManager)
+	mockHorizon := new(mocks.MockHorizon)
+
+	accountSeed := "SC34WILLHVADXMP6ACPMIRA6TRAWJMVCLPFNW7S6MUMXJVLAZUC4EWHP"
+	accountKeypair := keypair.MustParse(accountSeed)
+	accountSigner, err := signer.NewSeedSigner(accountSeed)
+	if err != nil {
+		panic(err)
+	}
+
+	channelSeed := "SC37TBSIAYKIDQ6GTGLT2HSORLIHZQHBXVFI5P5K4Q5TSHRTRBK3UNWG"
+	channelKeypair := keypair.MustParse(channelSeed)
+	channelSigner, err := signer.NewSeedSigner(channelSeed)
+	if err != nil {
+		panic(err)
+	}
+
+	operation := build.Payment(
+		build.Destination{"GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632"},
+		build.NativeAmount{"100"},
+	)
+
+	Convey("TransactionSubmitter with channel accounts", t, func() {
+		mockHorizon.ExpectedCalls = nil
+		mockEntityManager.ExpectedCalls = nil
+
+		transactionSubmitter := NewTransactionSubmitter(
+			mockHorizon,
+			mockEntityManager,
+			"Test SDF Network ; September 2015",
+		)
+
+		mockHorizon.On("LoadAccount", accountKeypair.Address()).Return(
+			horizon.AccountResponse{AccountId: accountKeypair.Address(), SequenceNumber: "100"},
+			nil,
+		).Once()
+		err := transactionSubmitter.InitAccount(accountKeypair.Address(), accountSigner)
+		assert.Nil(t, err)
+
+		mockHorizon.On("LoadAccount", channelKeypair.Address()).Return(
+			horizon.AccountResponse{AccountId: channelKeypair.Address(), SequenceNumber: "200"},
+			nil,
+		).Once()
+		err = transactionSubmitter.InitChannels([]signer.Signer{channelSigner})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(transactionSubmitter.Channels))
+
+		mockEntityManager.On("Persist", mock.Anything).Return(nil)
+
+		Convey("When transaction is submitted", func() {
+			ledger := uint64(1234)
+			var envelope xdr.TransactionEnvelope
+			mockHorizon.On("SubmitTransaction", mock.AnythingOfType("string")).Return(
+				horizon.SubmitTransactionResponse{Ledger: &ledger},
+				nil,
+			).Run(func(args mock.Arguments) {
+				err := xdr.SafeUnmarshalBase64(args.String(0), &envelope)
+				assert.Nil(t, err)
+			}).Once()
+
+			Convey("it should lease a channel as transaction source", func() {
+				_, err := transactionSubmitter.SubmitTransaction(context.Background(), accountKeypair.Address(), operation, nil)
+				assert.Nil(t, err)
+
+				assert.Equal(t, channelKeypair.Address(), accountIdAddress(envelope.Tx.SourceAccount))
+				assert.Equal(t, xdr.SequenceNumber(201), envelope.Tx.SeqNum)
+				assert.Equal(t, accountKeypair.Address(), accountIdAddress(*envelope.Tx.Operations[0].SourceAccount))
+				assert.Equal(t, uint64(201), channelAccount(&transactionSubmitter).SequenceNumber)
+				assert.Equal(t, uint64(100), transactionSubmitter.Accounts[accountKeypair.Address()].SequenceNumber)
+				mockHorizon.AssertExpectations(t)
+			})
+
+			Convey("it should be co-signed by the submitting account", func() {
+				_, err := transactionSubmitter.SubmitTransaction(context.Background(), accountKeypair.Address(), operation, nil)
+				assert.Nil(t, err)
+
+				assert.Equal(t, 2, len(envelope.Signatures))
+				var hints [][4]byte
+				for _, signature := range envelope.Signatures {
+					hints = append(hints, [4]byte(signature.Hint))
+				}
+				assert.Contains(t, hints, channelKeypair.Hint())
+				assert.Contains(t, hints, accountKeypair.Hint())
+			})
+		})
+
+		Convey("When submission returns error", func() {
+			mockHorizon.On("SubmitTransaction", mock.AnythingOfType("string")).Return(
+				horizon.SubmitTransactionResponse{},
+				horizon.ErrTimeout,
+			).Once()
+
+			Convey("it should release the channel", func() {
+				_, err := transactionSubmitter.SubmitTransaction(context.Background(), accountKeypair.Address(), operation, nil)
+				assert.Equal(t, horizon.ErrTimeout, err)
+				assert.Equal(t, 1, len(transactionSubmitter.Channels))
+			})
+		})
+
+		Convey("When all channels are leased", func() {
+			ledger := uint64(1234)
+			mockHorizon.On("SubmitTransaction", mock.AnythingOfType("string")).Return(
+				horizon.SubmitTransactionResponse{Ledger: &ledger},
+				nil,
+			).Once()
+
+			channel := <-transactionSubmitter.Channels
+
+			Convey("it should wait until a channel is released", func() {
+				done := make(chan error)
+				go func() {
+					_, err := transactionSubmitter.SubmitTransaction(context.Background(), accountKeypair.Address(), operation, nil)
+					done <- err
+				}()
+
+				select {
+				case <-done:
+					t.Error("Transaction submitted without a free channel")
+				case <-time.After(50 * time.Millisecond):
+				}
+
+				transactionSubmitter.Channels <- channel
+
+				select {
+				case err := <-done:
+					assert.Nil(t, err)
+				case <-time.After(time.Second):
+					t.Error("Transaction not submitted after the channel was released")
+				}
+				assert.Equal(t, 1, len(transactionSubmitter.Channels))
+				mockHorizon.AssertExpectations(t)
+			})
+		})
+	})
+}
+
+// channelAccount returns the only channel account of the pool without
+// leasing it.
+func channelAccount(ts *TransactionSubmitter) *Account {
+	channel := <-ts.Channels
+	ts.Channels <- channel
+	return channel
+}
+
+func accountIdAddress(accountId xdr.AccountId) string {
+	address, err := strkey.Encode(strkey.VersionByteAccountID, accountId.Ed25519[:])
+	if err != nil {
+		panic(err)
+	}
+	return address
+}